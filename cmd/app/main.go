@@ -1,7 +1,33 @@
 package main
 
-import "banners-management/internal/app"
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"banners-management/internal/app"
+	"banners-management/internal/model/entity"
+)
+
+const (
+	createAdminCmd = "create-admin"
+	createUserCmd  = "create-user"
+)
 
 func main() {
+	if len(os.Args) > 1 && (os.Args[1] == createAdminCmd || os.Args[1] == createUserCmd) {
+		defaultRole := entity.RoleUser
+		if os.Args[1] == createAdminCmd {
+			defaultRole = entity.RoleAdmin
+		}
+
+		err := app.CreateUser(context.Background(), os.Args[2:], defaultRole, os.Stdin, os.Stdout)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	app.Run()
 }
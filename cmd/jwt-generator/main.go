@@ -1,24 +1,24 @@
 package main
 
 import (
+	"banners-management/internal/app"
 	"banners-management/internal/config"
-	"banners-management/internal/lib/jwt"
+	"banners-management/internal/model/entity"
 	"flag"
 	"fmt"
 	"io"
 	"os"
-	"time"
+	"slices"
+	"strings"
 )
 
-const (
-	roleUser  = "user"
-	roleAdmin = "admin"
-)
+var roles = []string{entity.RoleUser, entity.RoleViewer, entity.RoleEditor, entity.RoleAdmin}
 
 func main() {
 	flagSet := flag.NewFlagSet("jwt-generator-role", flag.ContinueOnError)
 	flagSet.SetOutput(io.Discard)
 	roleFlag := flagSet.String("role", "", "")
+	scopesFlag := flagSet.String("scopes", "", "")
 
 	_ = flagSet.Parse(os.Args[1:])
 
@@ -27,13 +27,20 @@ func main() {
 		os.Exit(1)
 	}
 	role := *roleFlag
-	if role != roleUser && role != roleAdmin {
-		fmt.Println("role should be either 'user' or 'admin'")
+	if !slices.Contains(roles, role) {
+		fmt.Printf("role should be one of %s\n", strings.Join(roles, ", "))
 		os.Exit(1)
 	}
+	var scopes []string
+	if *scopesFlag != "" {
+		scopes = strings.Split(*scopesFlag, ",")
+	}
 	cfg := config.MustLoad(os.Args[1:], os.LookupEnv)
-	manager := jwt.NewManager(string(cfg.JwtSettings.SecretKey), time.Duration(cfg.JwtSettings.Expire))
-	token, err := manager.GenerateToken(role)
+	manager, err := app.NewJwtManager(&cfg.JwtSettings)
+	if err != nil {
+		panic(err)
+	}
+	token, err := manager.GenerateToken(role, scopes...)
 	if err != nil {
 		panic(err)
 	}
@@ -15,23 +15,48 @@ import (
 	"banners-management/internal/config"
 	"banners-management/internal/lib/jwt"
 	"banners-management/internal/lib/logger/sl"
+	"banners-management/internal/service/apikey"
+	"banners-management/internal/service/audit"
+	"banners-management/internal/service/auth"
 	"banners-management/internal/service/banner"
+	"banners-management/internal/service/feature"
+	"banners-management/internal/service/tag"
 	"banners-management/internal/storage/pgs"
+	"banners-management/internal/storage/repo"
 )
 
 // App is the main application structure. It holds all the dependencies and the server.
 type App struct {
-	logger        *slog.Logger
-	jwtManager    *jwt.Manager
-	bannerService *banner.Service
+	logger         *slog.Logger
+	jwtManager     *jwt.Manager
+	bannerService  *banner.Service
+	featureService *feature.Service
+	tagService     *tag.Service
+	authService    *auth.Service
+	apiKeyService  *apikey.Service
+	auditService   *audit.Service
 }
 
 // New creates a new instance of the App.
-func New(logger *slog.Logger, jwtManager *jwt.Manager, bannerSvc *banner.Service) *App {
+func New(
+	logger *slog.Logger,
+	jwtManager *jwt.Manager,
+	bannerSvc *banner.Service,
+	featureSvc *feature.Service,
+	tagSvc *tag.Service,
+	authSvc *auth.Service,
+	apiKeySvc *apikey.Service,
+	auditSvc *audit.Service,
+) *App {
 	return &App{
-		logger:        logger,
-		jwtManager:    jwtManager,
-		bannerService: bannerSvc,
+		logger:         logger,
+		jwtManager:     jwtManager,
+		bannerService:  bannerSvc,
+		featureService: featureSvc,
+		tagService:     tagSvc,
+		authService:    authSvc,
+		apiKeyService:  apiKeySvc,
+		auditService:   auditSvc,
 	}
 }
 
@@ -68,8 +93,14 @@ func (a *App) shutdownGracefully(ctx context.Context, server *http.Server) {
 func Run() {
 	appStartCtx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
 	defer cancel()
-	cfg, app, storage, logger := initServices(appStartCtx)
+	cfg, app, storage, stats, logger := initServices(appStartCtx)
 	run(context.Background(), cfg, app)
+	waitForReturn(
+		context.Background(),
+		10*time.Second,
+		stats.Close,
+		func() { logger.Error("failed to flush banner stats") },
+	)
 	waitForReturn(
 		context.Background(),
 		10*time.Second,
@@ -79,20 +110,41 @@ func Run() {
 }
 
 // initServices initializes all required services for the main application.
-func initServices(ctx context.Context) (*config.Config, *App, *pgs.Storage, *slog.Logger) {
+func initServices(ctx context.Context) (*config.Config, *App, *pgs.Storage, *banner.StatsBuffer, *slog.Logger) {
 	cfg := config.MustLoad(os.Args[1:], os.LookupEnv)
 
 	logger := initLogger(cfg.Env)
-	storage := initStorage(ctx, cfg.DB.ConnectionString(), logger)
+	storage := initStorage(ctx, cfg.DB.ConnectionString(), cfg.Banner.VersionsLimit, logger)
 	redisClient := initRedisCache(ctx, cfg.Cache.ConnectionString(), logger)
-	jwtManager := jwt.NewManager(string(cfg.JwtSettings.SecretKey), time.Duration(cfg.JwtSettings.Expire))
+	jwtManager := initJwtManager(&cfg.JwtSettings, logger)
+	RegisterMetrics(storage, redisClient)
+
+	localCache := initLocalCache(&cfg.Cache, redisClient, logger)
+	cacheReader := banner.NewCacheReader(storage, redisClient, localCache, logger)
+	cacheWriter := banner.NewCacheWriter(storage, redisClient, localCache, logger)
+	jobDelayDeleter := initJobDelayDeleter(&cfg.Jobs, redisClient, cacheWriter, storage, logger)
+	statsBuffer := banner.NewStatsBuffer(
+		context.Background(), storage, time.Duration(cfg.Banner.StatsFlushInterval), logger,
+	)
+	bannerService := banner.NewService(
+		cacheReader, storage, storage, cacheWriter, cacheWriter, cacheWriter, cacheWriter, cacheWriter, jobDelayDeleter,
+		statsBuffer, storage, logger,
+	)
+
+	featureService := feature.NewService(storage, logger)
+	tagService := tag.NewService(storage, logger)
+	tokenStore := auth.NewRedisTokenStore(redisClient)
+	authService := auth.NewService(
+		storage, tokenStore, jwtManager, time.Duration(cfg.JwtSettings.RefreshExpire), logger,
+	)
 
-	cacheReader := banner.NewCacheReader(storage, redisClient, logger)
-	jobDelayDeleter := banner.NewRedisChannelDeleter(context.Background(), redisClient, storage, logger)
-	bannerService := banner.NewService(cacheReader, storage, jobDelayDeleter, storage, logger)
+	apiKeyService := apikey.NewService(storage, logger)
+	auditService := audit.NewService(storage, logger)
 
-	app := New(logger, jwtManager, bannerService)
-	return cfg, app, storage, logger
+	app := New(
+		logger, jwtManager, bannerService, featureService, tagService, authService, apiKeyService, auditService,
+	)
+	return cfg, app, storage, statsBuffer, logger
 }
 
 // RunWithConfig starts the application with the provided configuration.
@@ -109,9 +161,24 @@ func RunWithConfig(
 
 // run starts the app.
 func run(ctx context.Context, cfg *config.Config, app *App) {
+	handler := routes.New(
+		app.logger,
+		app.jwtManager,
+		newVerifier(&cfg.Auth.OIDC, app.jwtManager, app.logger),
+		app.bannerService,
+		app.featureService,
+		app.tagService,
+		app.authService,
+		app.apiKeyService,
+		app.auditService,
+		cfg.TokenEndpointEnabled(),
+	)
+	if !cfg.TokenEndpointEnabled() {
+		app.logger.Info("token endpoint disabled")
+	}
 	server := &http.Server{
 		Addr:         cfg.HTTPServer.Address,
-		Handler:      routes.New(app.logger, app.jwtManager, app.bannerService),
+		Handler:      handler,
 		WriteTimeout: time.Duration(cfg.HTTPServer.Timeout),
 		IdleTimeout:  time.Duration(cfg.HTTPServer.IdleTimeout),
 		ReadTimeout:  time.Duration(cfg.HTTPServer.Timeout),
@@ -161,8 +228,8 @@ func initLogger(env string) *slog.Logger {
 }
 
 // initStorage initializes the application storage.
-func initStorage(ctx context.Context, connString string, logger *slog.Logger) *pgs.Storage {
-	storage, err := pgs.New(ctx, connString)
+func initStorage(ctx context.Context, connString string, versionsLimit int, logger *slog.Logger) *pgs.Storage {
+	storage, err := pgs.New(ctx, connString, versionsLimit)
 	if err != nil {
 		logger.Error("failed to initialize storage", sl.Err(err))
 		os.Exit(1)
@@ -172,6 +239,18 @@ func initStorage(ctx context.Context, connString string, logger *slog.Logger) *p
 	return storage
 }
 
+// initJwtManager initializes the JWT token manager with the configured keys.
+func initJwtManager(cfg *config.JwtSettings, logger *slog.Logger) *jwt.Manager {
+	jwtManager, err := NewJwtManager(cfg)
+	if err != nil {
+		logger.Error("failed to initialize jwt manager", sl.Err(err))
+		os.Exit(1)
+	}
+
+	logger.Info("jwt manager initialized", slog.String("signing_kid", cfg.SigningKeyID))
+	return jwtManager
+}
+
 // initRedisCache initializes the application cache.
 func initRedisCache(ctx context.Context, connString string, logger *slog.Logger) *redis.Cache {
 	redisClient, err := redis.NewCache(ctx, connString)
@@ -183,3 +262,46 @@ func initRedisCache(ctx context.Context, connString string, logger *slog.Logger)
 	logger.Info("redis cache initialized", slog.String("cache", "redis"))
 	return redisClient
 }
+
+// initLocalCache initializes the in-process cache in front of redis, if it's enabled by the config.
+func initLocalCache(cfg *config.Cache, redisClient *redis.Cache, logger *slog.Logger) *banner.LocalCache {
+	if cfg.LocalSize <= 0 {
+		logger.Info("local cache disabled")
+		return nil
+	}
+
+	localCache := banner.NewLocalCache(
+		context.Background(),
+		cfg.LocalSize,
+		time.Duration(cfg.LocalTTL),
+		redisClient,
+		logger,
+	)
+
+	logger.Info("local cache initialized", slog.Int("size", cfg.LocalSize))
+	return localCache
+}
+
+// initJobDelayDeleter initializes the deleter, that executes the deferred deletions.
+func initJobDelayDeleter(
+	cfg *config.Jobs,
+	redisClient *redis.Cache,
+	deleter repo.BannerDeleter,
+	audit repo.AuditSaver,
+	logger *slog.Logger,
+) *banner.RedisStreamDeleter {
+	policy := banner.RetryPolicy{
+		MaxAttempts: cfg.MaxAttempts,
+		Backoff:     time.Duration(cfg.RetryBackoff),
+		MaxBackoff:  time.Duration(cfg.MaxRetryBackoff),
+	}
+	jobDelayDeleter, err := banner.NewRedisStreamDeleter(
+		context.Background(), redisClient, deleter, audit, policy, logger,
+	)
+	if err != nil {
+		logger.Error("failed to initialize job delay deleter", sl.Err(err))
+		os.Exit(1)
+	}
+
+	return jobDelayDeleter
+}
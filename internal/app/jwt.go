@@ -0,0 +1,74 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"banners-management/internal/config"
+	"banners-management/internal/lib/jwt"
+	"banners-management/internal/lib/oidc"
+)
+
+const oidcRequestTimeout = 5 * time.Second
+
+// NewJwtManager reads the keys from the configuration and returns a new jwt.Manager.
+func NewJwtManager(cfg *config.JwtSettings) (*jwt.Manager, error) {
+	keys := make([]*jwt.Key, len(cfg.Keys))
+	for i, k := range cfg.Keys {
+		privatePEM, err := readOptional(k.PrivateKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		publicPEM, err := readOptional(k.PublicKeyPath)
+		if err != nil {
+			return nil, err
+		}
+
+		keys[i], err = jwt.ParseKey(k.ID, k.Alg, privatePEM, publicPEM)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return jwt.NewKeyManager(string(cfg.SecretKey), keys, cfg.SigningKeyID, time.Duration(cfg.Expire))
+}
+
+// newVerifier returns the verifier of the tokens issued by the manager and, if it's configured,
+// by the external OIDC identity provider.
+func newVerifier(cfg *config.OIDC, manager *jwt.Manager, logger *slog.Logger) jwt.Verifier {
+	if cfg.Issuer == "" {
+		return manager
+	}
+
+	mapping := make([]oidc.RoleMapping, len(cfg.RoleMapping))
+	for i, m := range cfg.RoleMapping {
+		mapping[i] = oidc.RoleMapping{Value: m.Value, Role: m.Role, Scopes: m.Scopes}
+	}
+	verifier := oidc.NewVerifier(oidc.Config{
+		Issuer:       cfg.Issuer,
+		Audience:     cfg.Audience,
+		RoleClaim:    cfg.RoleClaim,
+		RoleMapping:  mapping,
+		JWKSCacheTTL: time.Duration(cfg.JWKSCacheTTL),
+	}, &http.Client{Timeout: oidcRequestTimeout})
+
+	logger.Info("oidc verifier initialized", slog.String("issuer", cfg.Issuer))
+	return jwt.ChainVerifier{manager, verifier}
+}
+
+// readOptional returns the content of the file by the path, or nil if the path is empty.
+func readOptional(path string) ([]byte, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key: %w", err)
+	}
+
+	return content, nil
+}
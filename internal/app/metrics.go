@@ -0,0 +1,19 @@
+package app
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"banners-management/internal/cache/redis"
+	"banners-management/internal/lib/metrics"
+	"banners-management/internal/storage/pgs"
+)
+
+// RegisterMetrics registers the collectors of the connection pool stats of the storage and the cache,
+// so they're served by GET /metrics along with the request, cache and job metrics.
+// It must be called once.
+func RegisterMetrics(storage *pgs.Storage, cache *redis.Cache) {
+	prometheus.MustRegister(
+		metrics.NewPgxPoolCollector(storage.PoolStat),
+		metrics.NewRedisPoolCollector(cache.PoolStats),
+	)
+}
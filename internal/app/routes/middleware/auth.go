@@ -1,24 +1,70 @@
 package middleware
 
 import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
 	"banners-management/internal/lib/api"
 	"banners-management/internal/lib/api/jsn"
 	"banners-management/internal/lib/api/msg"
 	"banners-management/internal/lib/jwt"
 	"banners-management/internal/lib/logger/sl"
-	"log/slog"
-	"net/http"
-	"strings"
+	"banners-management/internal/model/entity"
+	"banners-management/internal/service/apikey"
 )
 
-const Authorization = "Authorization"
+const (
+	Authorization = "Authorization"
+	APIKey        = "X-API-Key"
+)
+
+// TokenRevocations reports whether an access token was revoked by its ID.
+type TokenRevocations interface {
+	TokenRevoked(ctx context.Context, tokenID string) (bool, error)
+}
+
+// APIKeys authenticates the callers by their API keys.
+type APIKeys interface {
+	Authenticate(ctx context.Context, key string) (*entity.APIKey, error)
+}
 
 // NewAuthorizationMiddleware creates a new authorization middleware.
-// It checks the Authorization header for a valid JWT token.
-// If the token is valid, it extracts the role from it and adds it to the request context.
-func NewAuthorizationMiddleware(logger *slog.Logger, manager *jwt.Manager) Middleware {
+// If the request has the X-API-Key header, the key must be accepted by the keys,
+// and the role, the scopes and the subject of the key are added to the request context.
+// Otherwise, it checks the Authorization header for a JWT token, that is accepted by the verifier
+// and was not revoked. If the token is valid, it extracts the role, the scopes, the subject and the token ID
+// from it and adds them to the request context.
+func NewAuthorizationMiddleware(
+	logger *slog.Logger,
+	verifier jwt.Verifier,
+	revocations TokenRevocations,
+	keys APIKeys,
+) Middleware {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if key := r.Header.Get(APIKey); key != "" {
+				k, err := keys.Authenticate(r.Context(), key)
+				if errors.Is(err, apikey.ErrInvalid) {
+					logger.Info("invalid api key")
+					jsn.EncodeResponse(w, http.StatusUnauthorized, api.ErrResponse(msg.APINotAuthorized), logger)
+					return
+				} else if err != nil {
+					logger.Error("failed to authenticate api key", sl.Err(err))
+					jsn.EncodeResponse(w, http.StatusInternalServerError, api.ErrResponse(msg.ErrUnknown), logger)
+					return
+				}
+
+				r = api.SetUserRole(r, k.Role)
+				r = api.SetUserScopes(r, k.Scopes)
+				r = api.SetUserSubject(r, k.Subject())
+
+				next.ServeHTTP(w, r)
+				return
+			}
+
 			token := r.Header.Get(Authorization)
 			if token == "" {
 				logger.Info("nothing in Authorization header")
@@ -28,21 +74,31 @@ func NewAuthorizationMiddleware(logger *slog.Logger, manager *jwt.Manager) Middl
 
 			token = strings.TrimPrefix(token, "Bearer ")
 
-			err := manager.VerifyToken(token)
+			claims, err := verifier.Verify(r.Context(), token)
 			if err != nil {
 				logger.Info("invalid jwt token", sl.Err(err))
 				jsn.EncodeResponse(w, http.StatusUnauthorized, api.ErrResponse(msg.APINotAuthorized), logger)
 				return
 			}
 
-			role, err := manager.GetRole(token)
-			if err != nil {
-				logger.Info("failed to get role from token", sl.Err(err))
-				jsn.EncodeResponse(w, http.StatusUnauthorized, api.ErrResponse(msg.APINotAuthorized), logger)
-				return
+			if claims.ID != "" {
+				revoked, err := revocations.TokenRevoked(r.Context(), claims.ID)
+				if err != nil {
+					logger.Error("failed to check token revocation", sl.Err(err))
+					jsn.EncodeResponse(w, http.StatusInternalServerError, api.ErrResponse(msg.ErrUnknown), logger)
+					return
+				}
+				if revoked {
+					logger.Info("revoked jwt token", slog.String("token_id", claims.ID))
+					jsn.EncodeResponse(w, http.StatusUnauthorized, api.ErrResponse(msg.APINotAuthorized), logger)
+					return
+				}
 			}
 
-			r = api.SetUserRole(r, role)
+			r = api.SetUserRole(r, claims.Role)
+			r = api.SetUserScopes(r, claims.Scopes)
+			r = api.SetTokenID(r, claims.ID)
+			r = api.SetUserSubject(r, claims.Subject)
 
 			next.ServeHTTP(w, r)
 		})
@@ -53,8 +109,22 @@ func NewAuthorizationMiddleware(logger *slog.Logger, manager *jwt.Manager) Middl
 // and if so, gives access to the calling endpoint, otherwise returns 403 Forbidden status code response.
 func EnsureAdmin(next http.Handler, logger *slog.Logger) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		role := api.UserRole(r)
-		if role != "admin" {
+		if !api.UserPrincipal(r).IsAdmin() {
+			jsn.EncodeResponse(w, http.StatusForbidden, api.ErrResponse(msg.APIForbidden), logger)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// EnsureReader returns new http.Handler that checks if the incoming request authorized with a role,
+// that can read the banners management data (viewer, editor or admin), and if so, gives access
+// to the calling endpoint, otherwise returns 403 Forbidden status code response.
+// The modifications are authorized by the endpoints themselves.
+func EnsureReader(next http.Handler, logger *slog.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !api.UserPrincipal(r).CanRead() {
 			jsn.EncodeResponse(w, http.StatusForbidden, api.ErrResponse(msg.APIForbidden), logger)
 			return
 		}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"banners-management/internal/lib/metrics"
+)
+
+// NewMetricsMiddleware creates a new metrics middleware.
+// It counts the requests and observes their durations by method, route and response status.
+// route returns the route pattern of the request, so the requests to the same route with different ids
+// are counted together.
+func NewMetricsMiddleware(route func(r *http.Request) string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			wrw := &wrappedResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+
+			t1 := time.Now()
+
+			next.ServeHTTP(wrw, r)
+
+			labels := []string{r.Method, route(r), strconv.Itoa(wrw.statusCode)}
+			metrics.HTTPRequests.WithLabelValues(labels...).Inc()
+			metrics.HTTPRequestDuration.WithLabelValues(labels...).Observe(time.Since(t1).Seconds())
+		})
+	}
+}
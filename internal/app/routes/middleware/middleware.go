@@ -20,6 +20,11 @@ func (w *wrappedResponseWriter) WriteHeader(statusCode int) {
 	w.ResponseWriter.WriteHeader(statusCode)
 }
 
+// Unwrap returns the original http.ResponseWriter, so http.ResponseController can flush the streamed responses.
+func (w *wrappedResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
 // Chain creates a new middleware that chains the provided middlewares.
 func Chain(ms ...Middleware) Middleware {
 	return func(next http.Handler) http.Handler {
@@ -4,16 +4,38 @@ import (
 	"log/slog"
 	"net/http"
 
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
 	"banners-management/internal/app/routes/middleware"
+	apikeyhndl "banners-management/internal/handlers/admin/apikey"
+	audithndl "banners-management/internal/handlers/admin/audit"
 	adm "banners-management/internal/handlers/admin/banner"
+	featurehndl "banners-management/internal/handlers/admin/feature"
+	taghndl "banners-management/internal/handlers/admin/tag"
 	"banners-management/internal/handlers/auth"
 	bannerhndl "banners-management/internal/handlers/banner"
 	"banners-management/internal/lib/jwt"
+	apikeysvc "banners-management/internal/service/apikey"
+	auditsvc "banners-management/internal/service/audit"
+	authsvc "banners-management/internal/service/auth"
 	bannersvc "banners-management/internal/service/banner"
+	featuresvc "banners-management/internal/service/feature"
+	tagsvc "banners-management/internal/service/tag"
 )
 
 // New creates a new router with all the middlewares.
-func New(logger *slog.Logger, manager *jwt.Manager, bannerSvc *bannersvc.Service) http.Handler {
+func New(
+	logger *slog.Logger,
+	manager *jwt.Manager,
+	verifier jwt.Verifier,
+	bannerSvc *bannersvc.Service,
+	featureSvc *featuresvc.Service,
+	tagSvc *tagsvc.Service,
+	authSvc *authsvc.Service,
+	apiKeySvc *apikeysvc.Service,
+	auditSvc *auditsvc.Service,
+	tokenEndpoint bool,
+) http.Handler {
 	healthRouter := http.NewServeMux()
 	healthRouter.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -22,6 +44,8 @@ func New(logger *slog.Logger, manager *jwt.Manager, bannerSvc *bannersvc.Service
 
 	usrRouter := http.NewServeMux()
 	usrRouter.Handle("GET /user_banner", bannerhndl.NewGetHandler(bannerSvc, logger))
+	usrRouter.Handle("GET /user_banners", bannerhndl.NewGetManyHandler(bannerSvc, logger))
+	usrRouter.Handle("GET /user_banner/click", bannerhndl.NewClickHandler(bannerSvc, logger))
 
 	mw := middleware.Chain(
 		middleware.NewRecovererMiddleware(logger),
@@ -31,22 +55,74 @@ func New(logger *slog.Logger, manager *jwt.Manager, bannerSvc *bannersvc.Service
 	)
 	authMw := middleware.Chain(
 		mw,
-		middleware.NewAuthorizationMiddleware(logger, manager),
+		middleware.NewAuthorizationMiddleware(logger, verifier, authSvc, apiKeySvc),
 	)
 
+	adminOnly := func(next http.Handler) http.Handler { return middleware.EnsureAdmin(next, logger) }
 	admRouter := http.NewServeMux()
 	admRouter.Handle("GET /banner", adm.NewGetHandler(bannerSvc, logger))
 	admRouter.Handle("POST /banner", adm.NewCreateHandler(bannerSvc, logger))
+	admRouter.Handle("POST /banner/bulk", adm.NewBulkCreateHandler(bannerSvc, logger))
+	admRouter.Handle("GET /banner/export", adm.NewExportHandler(bannerSvc, logger))
 	admRouter.Handle("PATCH /banner/{id}", adm.NewUpdateHandler(bannerSvc, logger))
 	admRouter.Handle("DELETE /banner/{id}", adm.NewDeleteHandler(bannerSvc, logger))
 	admRouter.Handle("DELETE /banner", adm.NewDeleteByFeatureTagHandler(bannerSvc, logger))
+	admRouter.Handle("GET /banner/{id}/versions", adm.NewVersionsHandler(bannerSvc, logger))
+	admRouter.Handle("POST /banner/{id}/versions/{version}/restore", adm.NewRestoreVersionHandler(bannerSvc, logger))
+	admRouter.Handle("GET /banner/{id}/variants", adm.NewVariantsHandler(bannerSvc, logger))
+	admRouter.Handle("POST /banner/{id}/variants", adm.NewCreateVariantHandler(bannerSvc, logger))
+	admRouter.Handle("PATCH /banner/{id}/variants/{variant_id}", adm.NewUpdateVariantHandler(bannerSvc, logger))
+	admRouter.Handle("DELETE /banner/{id}/variants/{variant_id}", adm.NewDeleteVariantHandler(bannerSvc, logger))
+	admRouter.Handle("GET /banner/{id}/stats", adm.NewStatsHandler(bannerSvc, logger))
+	admRouter.Handle("GET /jobs/{id}", adm.NewJobHandler(bannerSvc, logger))
+
+	admRouter.Handle("GET /feature", featurehndl.NewListHandler(featureSvc, logger))
+	admRouter.Handle("POST /feature", adminOnly(featurehndl.NewCreateHandler(featureSvc, logger)))
+	admRouter.Handle("GET /feature/{id}", featurehndl.NewGetHandler(featureSvc, logger))
+	admRouter.Handle("PATCH /feature/{id}", adminOnly(featurehndl.NewUpdateHandler(featureSvc, logger)))
+	admRouter.Handle("DELETE /feature/{id}", adminOnly(featurehndl.NewDeleteHandler(featureSvc, logger)))
+
+	admRouter.Handle("GET /tag", taghndl.NewListHandler(tagSvc, logger))
+	admRouter.Handle("POST /tag", adminOnly(taghndl.NewCreateHandler(tagSvc, logger)))
+	admRouter.Handle("GET /tag/{id}", taghndl.NewGetHandler(tagSvc, logger))
+	admRouter.Handle("PATCH /tag/{id}", adminOnly(taghndl.NewUpdateHandler(tagSvc, logger)))
+	admRouter.Handle("DELETE /tag/{id}", adminOnly(taghndl.NewDeleteHandler(tagSvc, logger)))
+
+	admRouter.Handle("GET /api_key", adminOnly(apikeyhndl.NewListHandler(apiKeySvc, logger)))
+	admRouter.Handle("POST /api_key", adminOnly(apikeyhndl.NewCreateHandler(apiKeySvc, logger)))
+	admRouter.Handle("DELETE /api_key/{id}", adminOnly(apikeyhndl.NewDeleteHandler(apiKeySvc, logger)))
 
-	usrRouter.Handle("/", middleware.EnsureAdmin(admRouter, logger))
+	admRouter.Handle("GET /audit", adminOnly(audithndl.NewGetHandler(auditSvc, logger)))
+
+	// banners are modified by the editors within their scopes, which is checked by the banner handlers
+	usrRouter.Handle("/", middleware.EnsureReader(admRouter, logger))
 
 	mainRouter := http.NewServeMux()
 	mainRouter.Handle("GET /health", healthRouter)
-	mainRouter.Handle("GET /token", mw(auth.NewAuthHandler(manager, logger)))
+	mainRouter.Handle("GET /metrics", promhttp.Handler())
+	mainRouter.Handle("GET /.well-known/jwks.json", mw(auth.NewJWKSHandler(manager, logger)))
+	mainRouter.Handle("POST /auth/login", mw(auth.NewLoginHandler(authSvc, logger)))
+	mainRouter.Handle("POST /auth/refresh", mw(auth.NewRefreshHandler(authSvc, logger)))
+	mainRouter.Handle("POST /auth/logout", authMw(auth.NewLogoutHandler(authSvc, logger)))
+	if tokenEndpoint {
+		mainRouter.Handle("GET /token", mw(auth.NewAuthHandler(manager, logger)))
+	}
 	mainRouter.Handle("/", authMw(usrRouter))
 
-	return mainRouter
+	return middleware.NewMetricsMiddleware(routePattern(admRouter, usrRouter, mainRouter))(mainRouter)
+}
+
+// routePattern returns a function, that returns the pattern of the first of the muxes, that has a specific route
+// for the request, or "unmatched" if there is no such route.
+// The muxes are nested, so they're listed from the innermost one.
+func routePattern(muxes ...*http.ServeMux) func(r *http.Request) string {
+	return func(r *http.Request) string {
+		for _, mux := range muxes {
+			if _, pattern := mux.Handler(r); pattern != "" && pattern != "/" {
+				return pattern
+			}
+		}
+
+		return "unmatched"
+	}
 }
@@ -0,0 +1,71 @@
+package app
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"banners-management/internal/config"
+	"banners-management/internal/lib/jwt"
+	slogdiscard "banners-management/internal/lib/logger/slogimpl"
+	authdto "banners-management/internal/model/dto/auth"
+	"banners-management/internal/service/auth"
+	"banners-management/internal/storage/pgs"
+)
+
+// CreateUser registers a new user with the login, password, role and scopes from the command line args.
+// If the role is not provided, defaultRole is used. If the password is not provided,
+// it is read from the first line of in.
+// The configuration is read from the CONFIG_PATH environment variable.
+// It is used to create the first admin, that is able to log in via POST /auth/login, and the other users.
+func CreateUser(ctx context.Context, args []string, defaultRole string, in io.Reader, out io.Writer) error {
+	flagSet := flag.NewFlagSet("create-user", flag.ContinueOnError)
+	flagSet.SetOutput(out)
+	login := flagSet.String("login", "", "login of the user")
+	password := flagSet.String("password", "", "password of the user, read from stdin if omitted")
+	role := flagSet.String("role", defaultRole, "role of the user: user, viewer, editor or admin")
+	scopes := flagSet.String("scopes", "", "comma-separated scopes of the user, e.g. feature:42:write")
+	if err := flagSet.Parse(args); err != nil {
+		return err
+	}
+	if *login == "" {
+		return errors.New("login is required")
+	}
+
+	if *password == "" {
+		_, _ = fmt.Fprint(out, "password: ")
+		line, err := bufio.NewReader(in).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("failed to read password: %w", err)
+		}
+		*password = strings.TrimRight(line, "\r\n")
+	}
+
+	cfg := config.MustLoad(nil, os.LookupEnv)
+	storage, err := pgs.New(ctx, cfg.DB.ConnectionString(), cfg.Banner.VersionsLimit)
+	if err != nil {
+		return fmt.Errorf("failed to initialize storage: %w", err)
+	}
+	defer func() { _ = storage.Close(ctx) }()
+
+	jwtManager := jwt.NewManager(string(cfg.JwtSettings.SecretKey), time.Duration(cfg.JwtSettings.Expire))
+	// no tokens are issued by the command, so it doesn't need the token store
+	svc := auth.NewService(storage, nil, jwtManager, 0, slogdiscard.NewDiscardLogger())
+	dto := authdto.CreateUserDTO{Login: *login, Password: *password, Role: *role}
+	if *scopes != "" {
+		dto.Scopes = strings.Split(*scopes, ",")
+	}
+	id, err := svc.CreateUser(ctx, dto)
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(out, "%s %q created with id %d\n", *role, *login, id)
+	return nil
+}
@@ -0,0 +1,117 @@
+// Package memory contains an in-process cache implementation.
+package memory
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// entry is an item stored in Cache.
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time
+}
+
+// Cache is a thread-safe in-memory LRU cache, bounded by the number of items.
+// When the cache is full, the least recently used item is evicted to free the space for a new one.
+// Every item also has its own expiration time, after which it is not returned anymore.
+type Cache[K comparable, V any] struct {
+	mu    sync.Mutex
+	size  int
+	items map[K]*list.Element
+	order *list.List
+}
+
+// NewCache returns a new Cache instance, that stores at most size items.
+// It panics if size is not positive.
+func NewCache[K comparable, V any](size int) *Cache[K, V] {
+	if size <= 0 {
+		panic("cache.memory.NewCache: size must be positive")
+	}
+
+	return &Cache[K, V]{
+		size:  size,
+		items: make(map[K]*list.Element, size),
+		order: list.New(),
+	}
+}
+
+// Get returns the value stored by the key and true, or zero value and false
+// if there is no such key or it has already expired.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var zero V
+	el, ok := c.items[key]
+	if !ok {
+		return zero, false
+	}
+
+	e := el.Value.(*entry[K, V]) //nolint:errcheck // only entries are stored in the list
+	if time.Now().After(e.expiresAt) {
+		c.remove(el)
+		return zero, false
+	}
+
+	c.order.MoveToFront(el)
+	return e.value, true
+}
+
+// Set stores the value by the key for the ttl duration.
+// If ttl is not positive, the value is not stored and the previous one is removed.
+func (c *Cache[K, V]) Set(key K, value V, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		c.remove(el)
+	}
+	if ttl <= 0 {
+		return
+	}
+
+	if c.order.Len() >= c.size {
+		c.remove(c.order.Back())
+	}
+
+	el := c.order.PushFront(&entry[K, V]{key: key, value: value, expiresAt: time.Now().Add(ttl)})
+	c.items[key] = el
+}
+
+// Delete removes the values stored by the keys. Keys that do not exist are ignored.
+func (c *Cache[K, V]) Delete(keys ...K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		if el, ok := c.items[key]; ok {
+			c.remove(el)
+		}
+	}
+}
+
+// Clear removes all the values from the cache.
+func (c *Cache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.order.Init()
+	clear(c.items)
+}
+
+// Len returns the number of items in the cache, including the expired ones that were not evicted yet.
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+// remove removes the list element from the cache. The caller must hold the lock.
+func (c *Cache[K, V]) remove(el *list.Element) {
+	e := c.order.Remove(el).(*entry[K, V]) //nolint:errcheck // only entries are stored in the list
+	delete(c.items, e.key)
+}
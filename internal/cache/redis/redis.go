@@ -26,16 +26,19 @@ const (
 
 // CacheItem is a struct containing the Value that needs to be stored or restored in/from redis cache
 // and a Status. For more information, check descriptions: StatusExists, StatusNotFound, StatusNotExists.
+// CreatedAt is the moment the Value was retrieved from its origin.
 type CacheItem[T any] struct {
-	Value  T      `json:"value"`
-	Status Status `json:"status"`
+	Value     T         `json:"value"`
+	Status    Status    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
-// NewCacheItem returns new CacheItem.
+// NewCacheItem returns new CacheItem, created at the current moment.
 func NewCacheItem[T any](value T, status Status) *CacheItem[T] {
 	return &CacheItem[T]{
-		Value:  value,
-		Status: status,
+		Value:     value,
+		Status:    status,
+		CreatedAt: time.Now(),
 	}
 }
 
@@ -59,6 +62,11 @@ func NewCache(ctx context.Context, connString string) (*Cache, error) {
 	return &Cache{client: client}, nil
 }
 
+// PoolStats returns the current stats of the redis client connection pool.
+func (c *Cache) PoolStats() *redis.PoolStats {
+	return c.client.PoolStats()
+}
+
 // Set serializes the item into a json struct and sets this string in redis cache by the provided key.
 // Note: it is not a method of Cache, but a function that accepts it. It is because for now methods can't be generic.
 // See: https://github.com/golang/go/issues/49085.
@@ -97,3 +105,93 @@ func Get[T any](c *Cache, ctx context.Context, key string) (*CacheItem[T], error
 
 	return item, nil
 }
+
+// MGet retrieves the values by the given keys from the redis cache in a single round trip
+// and deserializes them into go structs of type T. The items are returned in the order of keys,
+// the missing ones have StatusNotFound.
+// Note: it is not a method of Cache, but a function that accepts it. It is because for now methods can't be generic.
+func MGet[T any](c *Cache, ctx context.Context, keys ...string) ([]*CacheItem[T], error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	vs, err := c.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("cache.redis.MGet: %w", err)
+	}
+
+	items := make([]*CacheItem[T], len(vs))
+	for i, v := range vs {
+		s, ok := v.(string)
+		if !ok {
+			var t T
+			items[i] = NewCacheItem[T](t, StatusNotFound)
+			continue
+		}
+
+		items[i] = new(CacheItem[T])
+		if err := json.Unmarshal([]byte(s), items[i]); err != nil {
+			return nil, fmt.Errorf("cache.redis.MGet: %w", err)
+		}
+	}
+
+	return items, nil
+}
+
+// Delete removes the provided keys from redis cache. Keys that do not exist are ignored.
+func (c *Cache) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	err := c.client.Del(ctx, keys...).Err()
+	if err != nil {
+		return fmt.Errorf("cache.redis.Delete: %w", err)
+	}
+
+	return nil
+}
+
+// Flush removes all the keys from the current redis database.
+// It is used mainly for testing.
+func (c *Cache) Flush(ctx context.Context) error {
+	err := c.client.FlushDB(ctx).Err()
+	if err != nil {
+		return fmt.Errorf("cache.redis.Flush: %w", err)
+	}
+
+	return nil
+}
+
+// GetDel atomically retrieves the value by the given key from the redis cache and removes the key.
+// The value is deserialized into a go struct of type T.
+// Note: it is not a method of Cache, but a function that accepts it. It is because for now methods can't be generic.
+func GetDel[T any](c *Cache, ctx context.Context, key string) (*CacheItem[T], error) {
+	v, err := c.client.GetDel(ctx, key).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			var t T
+			return NewCacheItem[T](t, StatusNotFound), nil
+		}
+
+		return nil, fmt.Errorf("cache.redis.GetDel: %w", err)
+	}
+
+	item := new(CacheItem[T])
+	err = json.Unmarshal([]byte(v), item)
+	if err != nil {
+		return nil, fmt.Errorf("cache.redis.GetDel: %w", err)
+	}
+
+	return item, nil
+}
+
+// Exists reports whether the given key is stored in redis cache.
+func (c *Cache) Exists(ctx context.Context, key string) (bool, error) {
+	n, err := c.client.Exists(ctx, key).Result()
+	if err != nil {
+		return false, fmt.Errorf("cache.redis.Exists: %w", err)
+	}
+
+	return n > 0, nil
+}
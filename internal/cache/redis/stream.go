@@ -0,0 +1,184 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// payloadField is the name of the stream entry field that holds the message.
+const payloadField = "payload"
+
+// StreamMessage is a message read from the redis stream.
+type StreamMessage struct {
+	ID      string
+	Payload []byte
+}
+
+// PendingMessage describes a stream message that was delivered to a consumer, but wasn't acknowledged yet.
+type PendingMessage struct {
+	ID         string
+	Idle       time.Duration
+	Deliveries int64
+}
+
+// StreamAdd serializes the message into a json and appends it to the redis stream.
+// It returns the ID of the added entry.
+// The stream is never trimmed by length, since the pending entries would be lost then,
+// the entries are removed by Ack instead.
+func (c *Cache) StreamAdd(ctx context.Context, stream string, message any) (string, error) {
+	const comp = "cache.redis.stream.StreamAdd"
+	bytes, err := json.Marshal(message)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", comp, err)
+	}
+
+	id, err := c.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: stream,
+		Values: map[string]any{payloadField: bytes},
+	}).Result()
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", comp, err)
+	}
+
+	return id, nil
+}
+
+// CreateGroup creates the consumer group for the redis stream, creating the stream itself if needed.
+// It does nothing if the group already exists.
+func (c *Cache) CreateGroup(ctx context.Context, stream, group string) error {
+	const comp = "cache.redis.stream.CreateGroup"
+	err := c.client.XGroupCreateMkStream(ctx, stream, group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("%s: %w", comp, err)
+	}
+
+	return nil
+}
+
+// ReadGroup reads at most count new messages from the redis stream on behalf of the consumer of the group.
+// It blocks for at most block duration if there are no new messages.
+func (c *Cache) ReadGroup(
+	ctx context.Context,
+	stream, group, consumer string,
+	count int64,
+	block time.Duration,
+) ([]StreamMessage, error) {
+	const comp = "cache.redis.stream.ReadGroup"
+	res, err := c.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    group,
+		Consumer: consumer,
+		Streams:  []string{stream, ">"},
+		Count:    count,
+		Block:    block,
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("%s: %w", comp, err)
+	}
+
+	var messages []StreamMessage
+	for _, s := range res {
+		messages = append(messages, toStreamMessages(s.Messages)...)
+	}
+
+	return messages, nil
+}
+
+// Pending returns at most count messages of the redis stream that were delivered to the group consumers,
+// but weren't acknowledged yet.
+func (c *Cache) Pending(ctx context.Context, stream, group string, count int64) ([]PendingMessage, error) {
+	const comp = "cache.redis.stream.Pending"
+	res, err := c.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: stream,
+		Group:  group,
+		Start:  "-",
+		End:    "+",
+		Count:  count,
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", comp, err)
+	}
+
+	messages := make([]PendingMessage, len(res))
+	for i, p := range res {
+		messages[i] = PendingMessage{ID: p.ID, Idle: p.Idle, Deliveries: p.RetryCount}
+	}
+
+	return messages, nil
+}
+
+// Claim transfers the ownership of the pending messages with the given ids, that are idle
+// for at least minIdle, to the consumer of the group, and returns them.
+// Every claim counts as a new delivery of the message.
+func (c *Cache) Claim(
+	ctx context.Context,
+	stream, group, consumer string,
+	minIdle time.Duration,
+	ids ...string,
+) ([]StreamMessage, error) {
+	const comp = "cache.redis.stream.Claim"
+	res, err := c.client.XClaim(ctx, &redis.XClaimArgs{
+		Stream:   stream,
+		Group:    group,
+		Consumer: consumer,
+		MinIdle:  minIdle,
+		Messages: ids,
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", comp, err)
+	}
+
+	return toStreamMessages(res), nil
+}
+
+// Ack acknowledges the messages with the given ids, so they're not delivered to the group consumers anymore,
+// and removes them from the stream. It expects the group to be the only one reading the stream.
+func (c *Cache) Ack(ctx context.Context, stream, group string, ids ...string) error {
+	_, err := c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.XAck(ctx, stream, group, ids...)
+		pipe.XDel(ctx, stream, ids...)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("cache.redis.stream.Ack: %w", err)
+	}
+
+	return nil
+}
+
+// ListPush serializes the message into a json and appends it to the tail of the redis list.
+func (c *Cache) ListPush(ctx context.Context, list string, message any) error {
+	const comp = "cache.redis.stream.ListPush"
+	bytes, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("%s: %w", comp, err)
+	}
+
+	err = c.client.RPush(ctx, list, bytes).Err()
+	if err != nil {
+		return fmt.Errorf("%s: %w", comp, err)
+	}
+
+	return nil
+}
+
+// toStreamMessages converts go-redis stream messages into StreamMessage slice.
+// Messages that were already removed from the stream have no payload.
+func toStreamMessages(xms []redis.XMessage) []StreamMessage {
+	messages := make([]StreamMessage, len(xms))
+	for i, m := range xms {
+		messages[i].ID = m.ID
+		if p, ok := m.Values[payloadField].(string); ok {
+			messages[i].Payload = []byte(p)
+		}
+	}
+
+	return messages
+}
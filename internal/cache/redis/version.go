@@ -0,0 +1,103 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// setIfVersionScript sets KEYS[1] to ARGV[2] for ARGV[3] milliseconds,
+// only if the version stored by KEYS[2] (0 if missing) equals ARGV[1].
+var setIfVersionScript = redis.NewScript(`
+local cur = redis.call('GET', KEYS[2]) or '0'
+if cur ~= ARGV[1] then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+return 1
+`)
+
+// VersionKey returns the key, the version of the given key is stored by.
+func VersionKey(key string) string {
+	return "version:" + key
+}
+
+// Versions returns the versions stored by the given version keys in a single round trip.
+// The missing versions are 0.
+func (c *Cache) Versions(ctx context.Context, versionKeys ...string) ([]int64, error) {
+	if len(versionKeys) == 0 {
+		return nil, nil
+	}
+
+	vs, err := c.client.MGet(ctx, versionKeys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("cache.redis.Versions: %w", err)
+	}
+
+	res := make([]int64, len(vs))
+	for i, v := range vs {
+		s, ok := v.(string)
+		if !ok {
+			continue
+		}
+		res[i], err = strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("cache.redis.Versions: %w", err)
+		}
+	}
+
+	return res, nil
+}
+
+// BumpVersions increments the versions stored by the given version keys in a single transaction.
+// Each version expires in exp after its last bump. If exp is not positive, the versions never expire.
+func (c *Cache) BumpVersions(ctx context.Context, exp time.Duration, versionKeys ...string) error {
+	if len(versionKeys) == 0 {
+		return nil
+	}
+
+	_, err := c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, k := range versionKeys {
+			pipe.Incr(ctx, k)
+			if exp > 0 {
+				pipe.PExpire(ctx, k, exp)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("cache.redis.BumpVersions: %w", err)
+	}
+
+	return nil
+}
+
+// SetIfVersion works the same way as Set does, but atomically checks that the version stored by versionKey
+// is still the given one, so the item read before the version was bumped is never set after the bump.
+// It reports whether the item was set.
+// Note: it is not a method of Cache, but a function that accepts it. It is because for now methods can't be generic.
+func SetIfVersion[T any](
+	c *Cache,
+	ctx context.Context,
+	key, versionKey string,
+	version int64,
+	item *CacheItem[T],
+	exp time.Duration,
+) (bool, error) {
+	value, err := json.Marshal(item)
+	if err != nil {
+		return false, fmt.Errorf("cache.redis.SetIfVersion: %w", err)
+	}
+
+	ms := max(exp.Milliseconds(), 1)
+	set, err := setIfVersionScript.Run(ctx, c.client, []string{key, versionKey}, version, value, ms).Int()
+	if err != nil {
+		return false, fmt.Errorf("cache.redis.SetIfVersion: %w", err)
+	}
+
+	return set == 1, nil
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Auth contains the authentication settings.
+type Auth struct {
+	// TokenEndpoint enables the GET /token endpoint, that issues a token with any role without credentials.
+	// It is meant for local development only, and is never enabled in the prod env.
+	TokenEndpoint bool `json:"token_endpoint"`
+	// OIDC is the external identity provider, whose tokens are accepted along with the own ones.
+	OIDC OIDC `json:"oidc"`
+}
+
+func (a Auth) String() string {
+	return fmt.Sprintf("{TokenEndpoint: %t, OIDC: %s}", a.TokenEndpoint, a.OIDC)
+}
+
+// OIDC contains the settings of an external OIDC identity provider. It is disabled if Issuer is empty.
+type OIDC struct {
+	Issuer string `json:"issuer"`
+	// Audience is the required value of the aud claim. It must be set, if Issuer is set.
+	Audience string `json:"audience"`
+	// RoleClaim is the token claim the role is read from, e.g. "groups".
+	RoleClaim string `json:"role_claim"`
+	// RoleMapping maps the role claim values to the service roles and scopes.
+	// The role of the first matching rule is taken, and the scopes of all the matching rules are granted.
+	RoleMapping []OIDCRoleMapping `json:"role_mapping"`
+	// JWKSCacheTTL is the time the public keys of the provider are cached for.
+	JWKSCacheTTL Duration `json:"jwks_cache_ttl"`
+}
+
+func (o OIDC) String() string {
+	return fmt.Sprintf("{Issuer: %s, Audience: %s, RoleClaim: %s, RoleMapping: %v, JWKSCacheTTL: %v}",
+		o.Issuer, o.Audience, o.RoleClaim, o.RoleMapping, o.JWKSCacheTTL)
+}
+
+// validate returns an error if the provider is enabled without the audience,
+// since the tokens issued by it for any other client would be accepted then.
+func (o OIDC) validate() error {
+	if o.Issuer != "" && o.Audience == "" {
+		return errors.New("auth.oidc.audience is required, if auth.oidc.issuer is set")
+	}
+
+	return nil
+}
+
+// OIDCRoleMapping maps the Value of the role claim to the service Role and Scopes.
+type OIDCRoleMapping struct {
+	Value  string   `json:"value"`
+	Role   string   `json:"role"`
+	Scopes []string `json:"scopes"`
+}
@@ -0,0 +1,17 @@
+package config
+
+import "fmt"
+
+// Banner contains the settings for banner management.
+type Banner struct {
+	// VersionsLimit is the number of previous banner versions kept in the history.
+	// Zero or a negative value means that the history is not limited.
+	VersionsLimit int `json:"versions_limit"`
+	// StatsFlushInterval is the interval between the writes of the buffered banner impressions and clicks.
+	// Zero means the default interval.
+	StatsFlushInterval Duration `json:"stats_flush_interval"`
+}
+
+func (b Banner) String() string {
+	return fmt.Sprintf("{VersionsLimit: %d, StatsFlushInterval: %v}", b.VersionsLimit, b.StatsFlushInterval)
+}
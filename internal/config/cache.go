@@ -6,23 +6,30 @@ import (
 	"strconv"
 )
 
-// Cache contains the settings for the connection to application cache.
+// Cache contains the settings for the connection to application cache
+// and for the optional in-process cache in front of it.
 type Cache struct {
 	Host   string `json:"host"`
 	Port   int    `json:"port"`
 	User   Secret `json:"user"`
 	Pass   Secret `json:"pass"`
 	DBName int    `json:"db_name"`
+	// LocalSize is the maximum number of items in the in-process cache. Zero disables the in-process cache.
+	LocalSize int `json:"local_size"`
+	// LocalTTL is the maximum time for an item to be stored in the in-process cache.
+	LocalTTL Duration `json:"local_ttl"`
 }
 
 func (d Cache) String() string {
 	return fmt.Sprintf(
-		"{Host: %s, Port: %d, User: %s, Pass: %s, DBName: %d}",
+		"{Host: %s, Port: %d, User: %s, Pass: %s, DBName: %d, LocalSize: %d, LocalTTL: %v}",
 		d.Host,
 		d.Port,
 		d.User,
 		d.Pass,
 		d.DBName,
+		d.LocalSize,
+		d.LocalTTL,
 	)
 }
 
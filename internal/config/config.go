@@ -8,6 +8,7 @@ import (
 	"io"
 	"log"
 	"os"
+	"path/filepath"
 )
 
 const (
@@ -22,11 +23,21 @@ type Config struct {
 	Cache       Cache       `json:"cache"`
 	JwtSettings JwtSettings `json:"jwt_settings"`
 	HTTPServer  HTTPServer  `json:"http_server"`
+	Banner      Banner      `json:"banner"`
+	Jobs        Jobs        `json:"jobs"`
+	Auth        Auth        `json:"auth"`
 }
 
 func (c Config) String() string {
-	return fmt.Sprintf("{Env: %s, DB: %s, Cache: %s, JwtSettings: %s, HTTPServer: %s}",
-		c.Env, c.DB, c.Cache, c.JwtSettings, c.HTTPServer)
+	return fmt.Sprintf(
+		"{Env: %s, DB: %s, Cache: %s, JwtSettings: %s, HTTPServer: %s, Banner: %s, Jobs: %s, Auth: %s}",
+		c.Env, c.DB, c.Cache, c.JwtSettings, c.HTTPServer, c.Banner, c.Jobs, c.Auth)
+}
+
+// TokenEndpointEnabled reports whether the GET /token endpoint should be served.
+// It is always disabled in the prod env, regardless of the Auth settings.
+func (c Config) TokenEndpointEnabled() bool {
+	return c.Auth.TokenEndpoint && c.Env != ProdEnv
 }
 
 // MustLoad reads the configuration from the file specified from the command line 'config' argument
@@ -47,6 +58,10 @@ func MustLoad(args []string, getenv func(string) (string, bool)) *Config {
 	if err != nil {
 		log.Fatalf("failed to unmarshal config: %s", err)
 	}
+	cfg.JwtSettings.resolvePaths(filepath.Dir(configPath))
+	if err = cfg.Auth.OIDC.validate(); err != nil {
+		log.Fatalf("invalid config: %s", err)
+	}
 
 	return cfg
 }
@@ -0,0 +1,18 @@
+package config
+
+import "fmt"
+
+// Jobs contains the settings for the deferred jobs execution.
+type Jobs struct {
+	// MaxAttempts is the number of attempts to execute a job before it's moved to the dead-letter list.
+	MaxAttempts int `json:"max_attempts"`
+	// RetryBackoff is the delay before the first retry of a failed job. Every next delay is doubled.
+	RetryBackoff Duration `json:"retry_backoff"`
+	// MaxRetryBackoff is the maximum delay between the retries of a failed job.
+	MaxRetryBackoff Duration `json:"max_retry_backoff"`
+}
+
+func (j Jobs) String() string {
+	return fmt.Sprintf("{MaxAttempts: %d, RetryBackoff: %v, MaxRetryBackoff: %v}",
+		j.MaxAttempts, j.RetryBackoff, j.MaxRetryBackoff)
+}
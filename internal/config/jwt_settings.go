@@ -1,13 +1,54 @@
 package config
 
-import "fmt"
+import (
+	"fmt"
+	"path/filepath"
+)
 
 // JwtSettings contains the settings for the JWT token.
 type JwtSettings struct {
+	// SecretKey is the symmetric HS256 key. Tokens without kid header are verified with it.
 	SecretKey Secret   `json:"secret"`
 	Expire    Duration `json:"expire"`
+	// RefreshExpire is the lifetime of the refresh tokens.
+	RefreshExpire Duration `json:"refresh_expire"`
+	// SigningKeyID is the ID of the key the tokens are signed with. If empty, they're signed with SecretKey.
+	SigningKeyID string `json:"signing_kid"`
+	// Keys are the asymmetric keys the tokens are verified with.
+	Keys []JwtKey `json:"keys"`
 }
 
 func (js JwtSettings) String() string {
-	return fmt.Sprintf("{SecretKey: %s, Expire: %v}", js.SecretKey, js.Expire)
+	return fmt.Sprintf("{SecretKey: %s, Expire: %v, RefreshExpire: %v, SigningKeyID: %s, Keys: %v}",
+		js.SecretKey, js.Expire, js.RefreshExpire, js.SigningKeyID, js.Keys)
+}
+
+// JwtKey is an asymmetric JWT key. The paths are relative to the directory of the config file.
+type JwtKey struct {
+	ID  string `json:"kid"`
+	Alg string `json:"alg"`
+	// PrivateKeyPath is the path to the PEM encoded private key. It's omitted for the retired keys.
+	PrivateKeyPath string `json:"private_key_path"`
+	// PublicKeyPath is the path to the PEM encoded public key. If omitted, it's derived from the private key.
+	PublicKeyPath string `json:"public_key_path"`
+}
+
+func (k JwtKey) String() string {
+	return fmt.Sprintf("{ID: %s, Alg: %s, PrivateKeyPath: %s, PublicKeyPath: %s}",
+		k.ID, k.Alg, k.PrivateKeyPath, k.PublicKeyPath)
+}
+
+// resolvePaths makes the relative key paths relative to the dir directory.
+func (js *JwtSettings) resolvePaths(dir string) {
+	resolve := func(path string) string {
+		if path == "" || filepath.IsAbs(path) {
+			return path
+		}
+		return filepath.Join(dir, path)
+	}
+
+	for i := range js.Keys {
+		js.Keys[i].PrivateKeyPath = resolve(js.Keys[i].PrivateKeyPath)
+		js.Keys[i].PublicKeyPath = resolve(js.Keys[i].PublicKeyPath)
+	}
 }
@@ -0,0 +1,50 @@
+package apikey
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"banners-management/internal/lib/api"
+	"banners-management/internal/lib/api/jsn"
+	apikeydto "banners-management/internal/model/dto/apikey"
+	"banners-management/internal/service"
+	apikeysvc "banners-management/internal/service/apikey"
+)
+
+type CreateResponse struct {
+	APIKeyID int64 `json:"api_key_id,omitempty"`
+	// Key is the API key itself. It is returned only once and can't be retrieved later.
+	Key    string `json:"key,omitempty"`
+	Prefix string `json:"prefix,omitempty"`
+	api.Response
+}
+
+func NewCreateHandler(svc *apikeysvc.Service, log *slog.Logger) http.HandlerFunc {
+	const comp = "handlers.admin.apikey.create"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("comp", comp),
+			slog.String(api.RequestIDKey, api.RequestID(r)),
+		)
+
+		req := new(apikeydto.CreateDTO)
+		err := jsn.DecodeRequest(r, req, log)
+		if err != nil {
+			jsn.EncodeResponse(w, http.StatusBadRequest, api.ErrResponse(err.Error()), log)
+			return
+		}
+
+		key, k, err := svc.CreateKey(r.Context(), *req)
+		if validErr := new(service.ValidationError); errors.As(err, validErr) {
+			jsn.EncodeResponse(w, http.StatusBadRequest, api.ErrResponse(validErr.Error()), log)
+			return
+		} else if err != nil {
+			jsn.EncodeResponse(w, http.StatusInternalServerError, api.ErrResponse(err.Error()), log)
+			return
+		}
+
+		jsn.EncodeResponse(w, http.StatusCreated, CreateResponse{APIKeyID: k.ID, Key: key, Prefix: k.Prefix}, log)
+	}
+}
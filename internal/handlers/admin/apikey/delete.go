@@ -0,0 +1,42 @@
+package apikey
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"banners-management/internal/lib/api"
+	"banners-management/internal/lib/api/jsn"
+	"banners-management/internal/lib/logger/sl"
+	"banners-management/internal/service/apikey"
+)
+
+func NewDeleteHandler(svc *apikey.Service, log *slog.Logger) http.HandlerFunc {
+	const comp = "handlers.admin.apikey.delete"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("comp", comp),
+			slog.String(api.RequestIDKey, api.RequestID(r)),
+		)
+
+		var id int64
+		err := api.ParseInt64(r.PathValue("id"), "id", &id)
+		if err != nil {
+			log.Info("failed to parse id", sl.Err(err))
+			jsn.EncodeResponse(w, http.StatusBadRequest, api.ErrResponse(err.Error()), log)
+			return
+		}
+
+		err = svc.RevokeKey(r.Context(), id)
+		if errors.Is(err, apikey.ErrNotFound) {
+			jsn.EncodeResponse(w, http.StatusNotFound, api.ErrResponse(err.Error()), log)
+			return
+		} else if err != nil {
+			jsn.EncodeResponse(w, http.StatusInternalServerError, api.ErrResponse(err.Error()), log)
+			return
+		}
+
+		jsn.EncodeResponse(w, http.StatusNoContent, api.OkResponse(), log)
+	}
+}
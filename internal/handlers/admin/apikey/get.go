@@ -0,0 +1,75 @@
+package apikey
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"banners-management/internal/lib/api"
+	"banners-management/internal/lib/api/jsn"
+	"banners-management/internal/model/entity"
+	"banners-management/internal/service/apikey"
+)
+
+const (
+	limit  = "limit"
+	offset = "offset"
+)
+
+type GetResponse []GetResponseItem
+
+type GetResponseItem struct {
+	APIKeyID   int64      `json:"api_key_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Role       string     `json:"role"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+func (ri *GetResponseItem) fromEntity(k *entity.APIKey) {
+	ri.APIKeyID = k.ID
+	ri.Name = k.Name
+	ri.Prefix = k.Prefix
+	ri.Role = k.Role
+	ri.Scopes = k.Scopes
+	ri.CreatedAt = k.CreatedAt
+	ri.LastUsedAt = k.LastUsedAt
+	ri.RevokedAt = k.RevokedAt
+}
+
+func NewListHandler(svc *apikey.Service, log *slog.Logger) http.HandlerFunc {
+	const comp = "handlers.admin.apikey.list"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("comp", comp),
+			slog.String(api.RequestIDKey, api.RequestID(r)),
+		)
+
+		p := r.URL.Query()
+		li, off := new(int), new(int)
+		err := api.ParseInt(p.Get(limit), limit, li)
+		if err != nil {
+			li = nil
+		}
+		err = api.ParseInt(p.Get(offset), offset, off)
+		if err != nil {
+			off = nil
+		}
+
+		ks, err := svc.Keys(r.Context(), li, off)
+		if err != nil {
+			jsn.EncodeResponse(w, http.StatusInternalServerError, api.ErrResponse(err.Error()), log)
+			return
+		}
+
+		resp := make([]GetResponseItem, len(ks))
+		for i, k := range ks {
+			resp[i].fromEntity(k)
+		}
+		jsn.EncodeResponse(w, http.StatusOK, GetResponse(resp), log)
+	}
+}
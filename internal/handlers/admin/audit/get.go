@@ -0,0 +1,133 @@
+package audit
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"banners-management/internal/lib/api"
+	"banners-management/internal/lib/api/jsn"
+	"banners-management/internal/lib/logger/sl"
+	"banners-management/internal/model/entity"
+	"banners-management/internal/service"
+	"banners-management/internal/service/audit"
+	"banners-management/internal/storage/repo"
+)
+
+const (
+	bannerID = "banner_id"
+	actor    = "actor"
+	from     = "from"
+	to       = "to"
+	limit    = "limit"
+	offset   = "offset"
+)
+
+type GetResponse []GetResponseItem
+
+type GetResponseItem struct {
+	EventID   int64                         `json:"event_id"`
+	Action    string                        `json:"action"`
+	Actor     string                        `json:"actor"`
+	ActorRole string                        `json:"actor_role"`
+	RequestID string                        `json:"request_id"`
+	BannerID  *int64                        `json:"banner_id,omitempty"`
+	VariantID *int64                        `json:"variant_id,omitempty"`
+	FeatureID *int64                        `json:"feature_id,omitempty"`
+	TagID     *int64                        `json:"tag_id,omitempty"`
+	JobID     *string                       `json:"job_id,omitempty"`
+	Diff      map[string]entity.AuditChange `json:"diff"`
+	CreatedAt time.Time                     `json:"created_at"`
+}
+
+func (ri *GetResponseItem) fromEntity(e *entity.AuditEvent) {
+	ri.EventID = e.ID
+	ri.Action = e.Action
+	ri.Actor = e.Actor
+	ri.ActorRole = e.ActorRole
+	ri.RequestID = e.RequestID
+	ri.BannerID = e.BannerID
+	ri.VariantID = e.VariantID
+	ri.FeatureID = e.FeatureID
+	ri.TagID = e.TagID
+	ri.JobID = e.JobID
+	ri.Diff = e.Diff
+	ri.CreatedAt = e.CreatedAt
+}
+
+func NewGetHandler(svc *audit.Service, log *slog.Logger) http.HandlerFunc {
+	const comp = "handlers.admin.audit.get"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("comp", comp),
+			slog.String(api.RequestIDKey, api.RequestID(r)),
+		)
+
+		filter, err := parseFilter(r)
+		if err != nil {
+			log.Info("failed to parse filter", sl.Err(err))
+			jsn.EncodeResponse(w, http.StatusBadRequest, api.ErrResponse(err.Error()), log)
+			return
+		}
+
+		p := r.URL.Query()
+		li, off := new(int), new(int)
+		err = api.ParseInt(p.Get(limit), limit, li)
+		if err != nil {
+			li = nil
+		}
+		err = api.ParseInt(p.Get(offset), offset, off)
+		if err != nil {
+			off = nil
+		}
+
+		es, err := svc.Events(r.Context(), filter, li, off)
+		if validErr := new(service.ValidationError); errors.As(err, validErr) {
+			jsn.EncodeResponse(w, http.StatusBadRequest, api.ErrResponse(validErr.Error()), log)
+			return
+		} else if err != nil {
+			jsn.EncodeResponse(w, http.StatusInternalServerError, api.ErrResponse(err.Error()), log)
+			return
+		}
+
+		resp := make([]GetResponseItem, len(es))
+		for i, e := range es {
+			resp[i].fromEntity(e)
+		}
+		jsn.EncodeResponse(w, http.StatusOK, GetResponse(resp), log)
+	}
+}
+
+// parseFilter returns the filter by the query parameters of the request.
+// The parameters are optional, but unlike the pagination ones, they're not ignored if malformed,
+// because the unfiltered audit log would be mistaken for the filtered one.
+func parseFilter(r *http.Request) (repo.AuditFilter, error) {
+	p := r.URL.Query()
+	var filter repo.AuditFilter
+	if p.Has(bannerID) {
+		filter.BannerID = new(int64)
+		if err := api.ParseInt64(p.Get(bannerID), bannerID, filter.BannerID); err != nil {
+			return filter, err
+		}
+	}
+	if p.Has(actor) {
+		a := p.Get(actor)
+		filter.Actor = &a
+	}
+	if p.Has(from) {
+		filter.From = new(time.Time)
+		if err := api.ParseTime(p.Get(from), from, filter.From); err != nil {
+			return filter, err
+		}
+	}
+	if p.Has(to) {
+		filter.To = new(time.Time)
+		if err := api.ParseTime(p.Get(to), to, filter.To); err != nil {
+			return filter, err
+		}
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,54 @@
+package banner
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"banners-management/internal/lib/api"
+	"banners-management/internal/lib/api/jsn"
+	"banners-management/internal/lib/api/msg"
+	bannersvc "banners-management/internal/service/banner"
+)
+
+// ensureCanWrite checks if the caller can modify the banners of all the features.
+// If not, it writes 403 Forbidden response and returns false.
+func ensureCanWrite(w http.ResponseWriter, r *http.Request, log *slog.Logger, featureIDs ...int64) bool {
+	p := api.UserPrincipal(r)
+	for _, id := range featureIDs {
+		if !p.CanWriteFeature(id) {
+			log.Info("banner modification forbidden", slog.String("role", p.Role), slog.Int64("feature_id", id))
+			jsn.EncodeResponse(w, http.StatusForbidden, api.ErrResponse(msg.APIForbidden), log)
+			return false
+		}
+	}
+
+	return true
+}
+
+// ensureCanWriteBanner checks if the caller can modify the banner by id, that is going to get
+// the banners of the features in featureIDs as well. If not, it writes the error response and returns false.
+func ensureCanWriteBanner(
+	w http.ResponseWriter,
+	r *http.Request,
+	svc *bannersvc.Service,
+	log *slog.Logger,
+	id int64,
+	featureIDs ...int64,
+) bool {
+	// admins can modify any banner, there's no need to read it
+	if api.UserPrincipal(r).IsAdmin() {
+		return true
+	}
+
+	b, err := svc.Banner(r.Context(), id)
+	if errors.Is(err, bannersvc.ErrNotFound) {
+		jsn.EncodeResponse(w, http.StatusNotFound, api.ErrResponse(err.Error()), log)
+		return false
+	} else if err != nil {
+		jsn.EncodeResponse(w, http.StatusInternalServerError, api.ErrResponse(err.Error()), log)
+		return false
+	}
+
+	return ensureCanWrite(w, r, log, append(featureIDs, b.FeatureID)...)
+}
@@ -0,0 +1,103 @@
+package banner
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"banners-management/internal/lib/api"
+	"banners-management/internal/lib/api/jsn"
+	"banners-management/internal/lib/logger/sl"
+	"banners-management/internal/service"
+	bannersvc "banners-management/internal/service/banner"
+)
+
+const (
+	dryRun = "dry_run"
+
+	// maxBulkBodySize is the maximum size of the bulk create request body.
+	maxBulkBodySize = 10 << 20
+)
+
+type BulkResponse struct {
+	BannerIDs []int64        `json:"banner_ids,omitempty"`
+	Errors    []BulkRowError `json:"errors,omitempty"`
+	api.Response
+}
+
+type BulkRowError struct {
+	Row   int    `json:"row"`
+	Error string `json:"error"`
+}
+
+func newBulkResponse(res *bannersvc.ImportResult, err error) BulkResponse {
+	resp := BulkResponse{BannerIDs: res.BannerIDs, Response: api.OkResponse()}
+	for _, e := range res.Errors {
+		resp.Errors = append(resp.Errors, BulkRowError{e.Row, e.Err.Error()})
+	}
+	if err != nil {
+		resp.Response = api.ErrResponse(err.Error())
+	}
+
+	return resp
+}
+
+func NewBulkCreateHandler(svc *bannersvc.Service, log *slog.Logger) http.HandlerFunc {
+	const comp = "handlers.admin.banner.bulk"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("comp", comp),
+			slog.String(api.RequestIDKey, api.RequestID(r)),
+		)
+
+		var dry bool
+		if p := r.URL.Query(); p.Has(dryRun) {
+			if err := api.ParseBool(p.Get(dryRun), dryRun, &dry); err != nil {
+				log.Info("invalid query parameters", sl.Err(err))
+				jsn.EncodeResponse(w, http.StatusBadRequest, api.ErrResponse(err.Error()), log)
+				return
+			}
+		}
+
+		format := mediaType(r.Header.Get("Content-Type"))
+		rows, err := decodeBulkRows(http.MaxBytesReader(w, r.Body, maxBulkBodySize), format)
+		if errors.Is(err, errUnsupportedFormat) {
+			log.Info("unsupported bulk format", slog.String("format", format))
+			jsn.EncodeResponse(w, http.StatusUnsupportedMediaType, api.ErrResponse(err.Error()), log)
+			return
+		} else if err != nil {
+			log.Info("failed to decode bulk request", sl.Err(err))
+			jsn.EncodeResponse(w, http.StatusBadRequest, api.ErrResponse(err.Error()), log)
+			return
+		}
+
+		featureIDs := make([]int64, 0, len(rows))
+		for _, row := range rows {
+			if row.Err == nil {
+				featureIDs = append(featureIDs, row.DTO.FeatureID)
+			}
+		}
+		if !ensureCanWrite(w, r, log, featureIDs...) {
+			return
+		}
+
+		res, err := svc.ImportBanners(r.Context(), rows, dry)
+		if errors.Is(err, bannersvc.ErrBulkRejected) {
+			jsn.EncodeResponse(w, http.StatusUnprocessableEntity, newBulkResponse(res, err), log)
+			return
+		} else if validErr := new(service.ValidationError); errors.As(err, validErr) {
+			jsn.EncodeResponse(w, http.StatusBadRequest, api.ErrResponse(validErr.Error()), log)
+			return
+		} else if err != nil {
+			jsn.EncodeResponse(w, http.StatusInternalServerError, api.ErrResponse(err.Error()), log)
+			return
+		}
+
+		if dry {
+			jsn.EncodeResponse(w, http.StatusOK, newBulkResponse(res, nil), log)
+			return
+		}
+		jsn.EncodeResponse(w, http.StatusCreated, newBulkResponse(res, nil), log)
+	}
+}
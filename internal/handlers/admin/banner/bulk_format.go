@@ -0,0 +1,257 @@
+package banner
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"strconv"
+	"strings"
+	"time"
+
+	"banners-management/internal/lib/api"
+	"banners-management/internal/lib/api/jsn"
+	"banners-management/internal/lib/api/msg"
+	bannerdto "banners-management/internal/model/dto/banner"
+	"banners-management/internal/model/entity"
+)
+
+// Bulk import and export formats.
+const (
+	formatJSON   = "application/json"
+	formatNDJSON = "application/x-ndjson"
+	formatCSV    = "text/csv"
+
+	// maxNDJSONLine is the maximum length of a single NDJSON line.
+	maxNDJSONLine = 1 << 20
+	// csvTagsSep separates tag IDs within a single CSV field.
+	csvTagsSep = ";"
+)
+
+// CSV columns. The export has all of them, and the import requires only the ones of CreateDTO,
+// ignoring the rest, so the exported banners can be imported back.
+const (
+	colBannerID    = "banner_id"
+	colFeatureID   = "feature_id"
+	colTagIDs      = "tag_ids"
+	colTitle       = "title"
+	colText        = "text"
+	colURL         = "url"
+	colIsActive    = "is_active"
+	colPriority    = "priority"
+	colActiveFrom  = "active_from"
+	colActiveUntil = "active_until"
+	colCreatedAt   = "created_at"
+	colUpdatedAt   = "updated_at"
+)
+
+var (
+	csvColumns = []string{
+		colBannerID, colFeatureID, colTagIDs, colTitle, colText, colURL,
+		colIsActive, colPriority, colActiveFrom, colActiveUntil, colCreatedAt, colUpdatedAt,
+	}
+	csvRequiredColumns = []string{colFeatureID, colTagIDs, colTitle, colText, colURL}
+
+	errUnsupportedFormat = jsn.DecodingError(msg.APIUnsupportedMediaType)
+)
+
+// mediaType returns the media type of the Content-Type header value ct. Empty ct is treated as JSON.
+func mediaType(ct string) string {
+	if ct == "" {
+		return formatJSON
+	}
+	mt, _, err := mime.ParseMediaType(ct)
+	if err != nil {
+		return ""
+	}
+
+	return mt
+}
+
+// decodeBulkRows decodes the banners of the bulk create request body r in the format by its media type.
+// The errors of the single rows are returned within the rows, so all of them are reported at once.
+// If the body can't be decoded at all, an error is returned.
+func decodeBulkRows(r io.Reader, format string) ([]bannerdto.BulkRow, error) {
+	switch format {
+	case formatJSON:
+		return decodeJSONRows(r)
+	case formatNDJSON:
+		return decodeNDJSONRows(r)
+	case formatCSV:
+		return decodeCSVRows(r)
+	default:
+		return nil, errUnsupportedFormat
+	}
+}
+
+// decodeJSONRows decodes the JSON array of banners.
+func decodeJSONRows(r io.Reader) ([]bannerdto.BulkRow, error) {
+	var raws []json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raws); errors.Is(err, io.EOF) {
+		return nil, jsn.DecodingError(msg.APIEmptyRequest)
+	} else if err != nil {
+		return nil, jsn.DecodingError(msg.APIInvalidRequest)
+	}
+
+	rows := make([]bannerdto.BulkRow, len(raws))
+	for i, raw := range raws {
+		rows[i].Row = i + 1
+		rows[i].Err = decodeJSONRow(raw, &rows[i].DTO)
+	}
+
+	return rows, nil
+}
+
+// decodeNDJSONRows decodes the banners, one per line. The blank lines are skipped.
+func decodeNDJSONRows(r io.Reader) ([]bannerdto.BulkRow, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxNDJSONLine)
+
+	rows := make([]bannerdto.BulkRow, 0)
+	for sc.Scan() {
+		line := bytes.TrimSpace(sc.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		row := bannerdto.BulkRow{Row: len(rows) + 1}
+		row.Err = decodeJSONRow(line, &row.DTO)
+		rows = append(rows, row)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, jsn.DecodingError(msg.APIInvalidRequest)
+	}
+
+	return rows, nil
+}
+
+// decodeJSONRow decodes a single banner from data into dto.
+func decodeJSONRow(data []byte, dto *bannerdto.CreateDTO) error {
+	err := json.Unmarshal(data, dto)
+	if unmarshalErr := new(json.UnmarshalTypeError); errors.As(err, &unmarshalErr) {
+		return jsn.DecodingError(msg.ErrInvalidFieldType(unmarshalErr.Field, unmarshalErr.Value, unmarshalErr.Type.String()))
+	} else if err != nil {
+		return jsn.DecodingError(msg.APIInvalidRequest)
+	}
+
+	return nil
+}
+
+// decodeCSVRows decodes the banners from CSV with the header row. The rows are numbered without the header.
+func decodeCSVRows(r io.Reader) ([]bannerdto.BulkRow, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1 // the length of the records is checked against the header
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, jsn.DecodingError(msg.APIEmptyRequest)
+	} else if err != nil {
+		return nil, jsn.DecodingError(msg.APIInvalidRequest)
+	}
+	cols := make(map[string]int, len(header))
+	for i, name := range header {
+		cols[strings.TrimSpace(name)] = i
+	}
+	for _, name := range csvRequiredColumns {
+		if _, ok := cols[name]; !ok {
+			return nil, jsn.DecodingError(msg.APIEmptyParameter(name))
+		}
+	}
+
+	rows := make([]bannerdto.BulkRow, 0)
+	for {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		row := bannerdto.BulkRow{Row: len(rows) + 1}
+		if err != nil || len(rec) != len(header) {
+			row.Err = jsn.DecodingError(msg.APIInvalidRequest)
+		} else {
+			row.Err = decodeCSVRow(rec, cols, &row.DTO)
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// decodeCSVRow decodes a single banner from the CSV record rec into dto.
+// cols are the indices of the columns by their names. Optional columns may be missing or empty.
+func decodeCSVRow(rec []string, cols map[string]int, dto *bannerdto.CreateDTO) error {
+	field := func(name string) string {
+		if i, ok := cols[name]; ok {
+			return strings.TrimSpace(rec[i])
+		}
+		return ""
+	}
+
+	if err := api.ParseInt64(field(colFeatureID), colFeatureID, &dto.FeatureID); err != nil {
+		return err
+	}
+	for _, s := range strings.Split(field(colTagIDs), csvTagsSep) {
+		var id int64
+		if err := api.ParseInt64(strings.TrimSpace(s), colTagIDs, &id); err != nil {
+			return err
+		}
+		dto.TagIDs = append(dto.TagIDs, id)
+	}
+	dto.Content.Title = field(colTitle)
+	dto.Content.Text = field(colText)
+	dto.Content.URL = field(colURL)
+
+	if s := field(colIsActive); s != "" {
+		if err := api.ParseBool(s, colIsActive, &dto.IsActive); err != nil {
+			return err
+		}
+	}
+	if s := field(colPriority); s != "" {
+		if err := api.ParseInt(s, colPriority, &dto.Priority); err != nil {
+			return err
+		}
+	}
+	for name, t := range map[string]**time.Time{colActiveFrom: &dto.ActiveFrom, colActiveUntil: &dto.ActiveUntil} {
+		if s := field(name); s != "" {
+			*t = new(time.Time)
+			if err := api.ParseTime(s, name, *t); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// csvRecord returns the CSV record of the banner with csvColumns.
+func csvRecord(b *entity.Banner) []string {
+	tags := make([]string, len(b.TagIDs))
+	for i, id := range b.TagIDs {
+		tags[i] = strconv.FormatInt(id, 10)
+	}
+
+	return []string{
+		strconv.FormatInt(b.ID, 10),
+		strconv.FormatInt(b.FeatureID, 10),
+		strings.Join(tags, csvTagsSep),
+		b.Title,
+		b.Text,
+		b.URL,
+		strconv.FormatBool(b.IsActive),
+		strconv.Itoa(b.Priority),
+		optTime(b.ActiveFrom),
+		optTime(b.ActiveUntil),
+		b.CreatedAt.Format(time.RFC3339Nano),
+		b.UpdatedAt.Format(time.RFC3339Nano),
+	}
+}
+
+// optTime returns t in RFC 3339 format, or an empty string if t is nil.
+func optTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.Format(time.RFC3339Nano)
+}
@@ -32,9 +32,13 @@ func NewCreateHandler(svc *bannersvc.Service, log *slog.Logger) http.HandlerFunc
 			return
 		}
 
+		if !ensureCanWrite(w, r, log, req.FeatureID) {
+			return
+		}
+
 		id, err := svc.SaveBanner(r.Context(), *req)
-		if errors.Is(err, bannersvc.ErrAlreadyExists) {
-			jsn.EncodeResponse(w, http.StatusConflict, api.ErrResponse(err.Error()), log)
+		if errors.Is(err, bannersvc.ErrUnknownReference) {
+			jsn.EncodeResponse(w, http.StatusUnprocessableEntity, api.ErrResponse(err.Error()), log)
 			return
 		} else if validErr := new(service.ValidationError); errors.As(err, validErr) {
 			jsn.EncodeResponse(w, http.StatusBadRequest, api.ErrResponse(validErr.Error()), log)
@@ -0,0 +1,62 @@
+package banner
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"banners-management/internal/lib/api"
+	"banners-management/internal/lib/api/jsn"
+	"banners-management/internal/lib/logger/sl"
+	bannerdto "banners-management/internal/model/dto/banner"
+	"banners-management/internal/service"
+	bannersvc "banners-management/internal/service/banner"
+)
+
+type CreateVariantResponse struct {
+	VariantID int64 `json:"variant_id,omitempty"`
+	api.Response
+}
+
+func NewCreateVariantHandler(svc *bannersvc.Service, log *slog.Logger) http.HandlerFunc {
+	const comp = "handlers.admin.banner.create_variant"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("comp", comp),
+			slog.String(api.RequestIDKey, api.RequestID(r)),
+		)
+
+		var id int64
+		err := api.ParseInt64(r.PathValue("id"), "id", &id)
+		if err != nil {
+			log.Info("failed to parse id", sl.Err(err))
+			jsn.EncodeResponse(w, http.StatusBadRequest, api.ErrResponse(err.Error()), log)
+			return
+		}
+		req := new(bannerdto.CreateVariantDTO)
+		err = jsn.DecodeRequest(r, req, log)
+		if err != nil {
+			jsn.EncodeResponse(w, http.StatusBadRequest, api.ErrResponse(err.Error()), log)
+			return
+		}
+
+		if !ensureCanWriteBanner(w, r, svc, log, id) {
+			return
+		}
+
+		vID, err := svc.SaveBannerVariant(r.Context(), id, *req)
+		if validErr := new(service.ValidationError); errors.As(err, validErr) {
+			jsn.EncodeResponse(w, http.StatusBadRequest, api.ErrResponse(validErr.Error()), log)
+			return
+		} else if errors.Is(err, bannersvc.ErrNotFound) {
+			jsn.EncodeResponse(w, http.StatusNotFound, api.ErrResponse(err.Error()), log)
+			return
+		} else if err != nil {
+			jsn.EncodeResponse(w, http.StatusInternalServerError, api.ErrResponse(err.Error()), log)
+			return
+		}
+
+		jsn.EncodeResponse(w, http.StatusCreated, CreateVariantResponse{vID, api.OkResponse()}, log)
+	}
+}
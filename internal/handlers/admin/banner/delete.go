@@ -28,6 +28,10 @@ func NewDeleteHandler(svc *banner.Service, log *slog.Logger) http.HandlerFunc {
 			return
 		}
 
+		if !ensureCanWriteBanner(w, r, svc, log, id) {
+			return
+		}
+
 		err = svc.DeleteBanner(r.Context(), id)
 		if validErr := new(service.ValidationError); errors.As(err, validErr) {
 			jsn.EncodeResponse(w, http.StatusBadRequest, api.ErrResponse(validErr.Error()), log)
@@ -3,6 +3,8 @@ package banner
 import (
 	"banners-management/internal/lib/api"
 	"banners-management/internal/lib/api/jsn"
+	"banners-management/internal/lib/api/msg"
+	"banners-management/internal/lib/er"
 	"banners-management/internal/service"
 	"banners-management/internal/service/banner"
 	"errors"
@@ -10,6 +12,11 @@ import (
 	"net/http"
 )
 
+type DeleteByFeatureTagResponse struct {
+	JobID string `json:"job_id,omitempty"`
+	api.Response
+}
+
 func NewDeleteByFeatureTagHandler(svc *banner.Service, log *slog.Logger) http.HandlerFunc {
 	const comp = "handlers.admin.banner.delete_by_feature_tag"
 
@@ -19,18 +26,39 @@ func NewDeleteByFeatureTagHandler(svc *banner.Service, log *slog.Logger) http.Ha
 			slog.String(api.RequestIDKey, api.RequestID(r)),
 		)
 
+		// a missing id widens the deletion, so a malformed one must not be treated as missing
 		p := r.URL.Query()
-		var fID, tID = new(int64), new(int64)
-		err := api.ParseInt64(p.Get(featureID), featureID, fID)
-		if err != nil {
-			fID = nil
+		var (
+			fID, tID *int64
+			resErr   error
+		)
+		if p.Has(featureID) {
+			fID = new(int64)
+			resErr = errors.Join(resErr, api.ParseInt64(p.Get(featureID), featureID, fID))
 		}
-		err = api.ParseInt64(p.Get(tagID), tagID, tID)
-		if err != nil {
-			tID = nil
+		if p.Has(tagID) {
+			tID = new(int64)
+			resErr = errors.Join(resErr, api.ParseInt64(p.Get(tagID), tagID, tID))
+		}
+
+		if resErr != nil {
+			err := er.Unwrap(resErr)
+			log.Info("failed to parse query params", slog.String("error", err))
+			jsn.EncodeResponse(w, http.StatusBadRequest, api.ErrResponse(err), log)
+			return
+		}
+
+		if fID != nil && !ensureCanWrite(w, r, log, *fID) {
+			return
+		}
+		// the tag may be used by the banners of any feature
+		if fID == nil && tID != nil && !api.UserPrincipal(r).CanWriteAllFeatures() {
+			log.Info("banner modification forbidden", slog.String("role", api.UserPrincipal(r).Role))
+			jsn.EncodeResponse(w, http.StatusForbidden, api.ErrResponse(msg.APIForbidden), log)
+			return
 		}
 
-		err = svc.DeleteBannerByFeatureTag(r.Context(), fID, tID)
+		jobID, err := svc.DeleteBannerByFeatureTag(r.Context(), fID, tID)
 		if validErr := new(service.ValidationError); errors.As(err, validErr) {
 			jsn.EncodeResponse(w, http.StatusBadRequest, api.ErrResponse(validErr.Error()), log)
 			return
@@ -42,6 +70,6 @@ func NewDeleteByFeatureTagHandler(svc *banner.Service, log *slog.Logger) http.Ha
 			return
 		}
 
-		jsn.EncodeResponse(w, http.StatusNoContent, api.OkResponse(), log)
+		jsn.EncodeResponse(w, http.StatusAccepted, DeleteByFeatureTagResponse{JobID: jobID}, log)
 	}
 }
@@ -0,0 +1,144 @@
+package banner
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"banners-management/internal/lib/api"
+	"banners-management/internal/lib/api/jsn"
+	"banners-management/internal/lib/api/msg"
+	"banners-management/internal/lib/logger/sl"
+	"banners-management/internal/model/entity"
+	bannersvc "banners-management/internal/service/banner"
+)
+
+const (
+	format = "format"
+
+	formatNameNDJSON = "ndjson"
+	formatNameCSV    = "csv"
+
+	// exportFlushEvery is the number of banners, after which the exported data is flushed to the client.
+	exportFlushEvery = 100
+)
+
+// exportWriter writes the exported banners in a single format.
+type exportWriter interface {
+	writeHeader() error
+	write(b *entity.Banner) error
+	flush() error
+}
+
+func NewExportHandler(svc *bannersvc.Service, log *slog.Logger) http.HandlerFunc {
+	const comp = "handlers.admin.banner.export"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("comp", comp),
+			slog.String(api.RequestIDKey, api.RequestID(r)),
+		)
+
+		var (
+			ew          exportWriter
+			contentType string
+		)
+		switch f := r.URL.Query().Get(format); f {
+		case "", formatNameNDJSON:
+			ew, contentType = &ndjsonExportWriter{enc: json.NewEncoder(w)}, formatNDJSON
+		case formatNameCSV:
+			ew, contentType = &csvExportWriter{w: csv.NewWriter(w)}, formatCSV
+		default:
+			log.Info("unsupported export format", slog.String("format", f))
+			jsn.EncodeResponse(w, http.StatusBadRequest, api.ErrResponse(msg.APIUnacceptableFormat(format)), log)
+			return
+		}
+
+		// the response is started lazily, so a failure to read the banners is still reported with the status
+		started := false
+		start := func() error {
+			if started {
+				return nil
+			}
+			started = true
+			w.Header().Set("Content-Type", contentType)
+			w.WriteHeader(http.StatusOK)
+			return ew.writeHeader()
+		}
+
+		rc := http.NewResponseController(w)
+		n := 0
+		err := svc.ExportBanners(r.Context(), func(b *entity.Banner) error {
+			if err := start(); err != nil {
+				return err
+			}
+			if err := ew.write(b); err != nil {
+				return err
+			}
+			n++
+			if n%exportFlushEvery == 0 {
+				if err := ew.flush(); err != nil {
+					return err
+				}
+				return rc.Flush()
+			}
+			return nil
+		})
+		if err != nil && !started {
+			jsn.EncodeResponse(w, http.StatusInternalServerError, api.ErrResponse(err.Error()), log)
+			return
+		} else if err != nil {
+			// the status is already sent, so the client gets the truncated data
+			log.Error("banners export interrupted", sl.Err(err), slog.Int("exported", n))
+			return
+		}
+
+		if err := start(); err != nil {
+			log.Error("failed to write export header", sl.Err(err))
+			return
+		}
+		if err := ew.flush(); err != nil {
+			log.Error("failed to flush export", sl.Err(err))
+			return
+		}
+		log.Info("banners exported", slog.Int("exported", n))
+	}
+}
+
+// ndjsonExportWriter writes the banners as NDJSON of GetResponseItem.
+type ndjsonExportWriter struct {
+	enc *json.Encoder
+}
+
+func (w *ndjsonExportWriter) writeHeader() error {
+	return nil
+}
+
+func (w *ndjsonExportWriter) write(b *entity.Banner) error {
+	var ri GetResponseItem
+	ri.fromEntity(b)
+	return w.enc.Encode(ri)
+}
+
+func (w *ndjsonExportWriter) flush() error {
+	return nil
+}
+
+// csvExportWriter writes the banners as CSV with csvColumns.
+type csvExportWriter struct {
+	w *csv.Writer
+}
+
+func (w *csvExportWriter) writeHeader() error {
+	return w.w.Write(csvColumns)
+}
+
+func (w *csvExportWriter) write(b *entity.Banner) error {
+	return w.w.Write(csvRecord(b))
+}
+
+func (w *csvExportWriter) flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
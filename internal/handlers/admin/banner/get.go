@@ -8,18 +8,39 @@ import (
 
 	"banners-management/internal/lib/api"
 	"banners-management/internal/lib/api/jsn"
+	"banners-management/internal/lib/logger/sl"
+	bannerdto "banners-management/internal/model/dto/banner"
 	"banners-management/internal/model/entity"
+	"banners-management/internal/service"
 	"banners-management/internal/service/banner"
 )
 
 const (
-	featureID = "feature_id"
-	tagID     = "tag_id"
-	limit     = "limit"
-	offset    = "offset"
+	featureID       = "feature_id"
+	tagID           = "tag_id"
+	tagIDs          = "tag_ids"
+	tagMatch        = "tag_match"
+	isActive        = "is_active"
+	createdFrom     = "created_from"
+	createdTo       = "created_to"
+	updatedFrom     = "updated_from"
+	updatedTo       = "updated_to"
+	search          = "q"
+	limit           = "limit"
+	offset          = "offset"
+	useLastRevision = "use_last_revision"
+	sort            = "sort"
+	cursor          = "cursor"
+	withTotal       = "with_total"
 )
 
-type GetResponse []GetResponseItem
+// GetResponse is a page of the banners. NextCursor is set if there are banners after the page,
+// and Total is set if the total number of the banners was requested.
+type GetResponse struct {
+	Banners    []GetResponseItem `json:"banners"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+	Total      *int64            `json:"total,omitempty"`
+}
 
 type GetResponseItem struct {
 	BannerID  int64   `json:"banner_id"`
@@ -30,9 +51,12 @@ type GetResponseItem struct {
 		Text  string `json:"text"`
 		URL   string `json:"url"`
 	} `json:"content"`
-	IsActive  bool      `json:"is_active"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	IsActive    bool       `json:"is_active"`
+	Priority    int        `json:"priority"`
+	ActiveFrom  *time.Time `json:"active_from,omitempty"`
+	ActiveUntil *time.Time `json:"active_until,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
 }
 
 func (ri *GetResponseItem) fromEntity(b *entity.Banner) {
@@ -43,10 +67,19 @@ func (ri *GetResponseItem) fromEntity(b *entity.Banner) {
 	ri.Content.Text = b.Text
 	ri.Content.URL = b.URL
 	ri.IsActive = b.IsActive
+	ri.Priority = b.Priority
+	ri.ActiveFrom = b.ActiveFrom
+	ri.ActiveUntil = b.ActiveUntil
 	ri.CreatedAt = b.CreatedAt
 	ri.UpdatedAt = b.UpdatedAt
 }
 
+// NewGetHandler returns a handler, that lists the banners.
+// The list is filtered by the feature, the tags, the activity, the creation and update time ranges,
+// and the full-text search query q. It is sorted by the sort parameter
+// and paginated either by the cursor or by the offset.
+// The cursor of the next page and the total number of the banners, if requested,
+// are returned along with the banners.
 func NewGetHandler(svc *banner.Service, log *slog.Logger) http.HandlerFunc {
 	const comp = "handlers.admin.banner.get"
 
@@ -56,44 +89,113 @@ func NewGetHandler(svc *banner.Service, log *slog.Logger) http.HandlerFunc {
 			slog.String(api.RequestIDKey, api.RequestID(r)),
 		)
 
-		p := r.URL.Query()
-		var (
-			fID, tID = new(int64), new(int64)
-			li, off  = new(int), new(int)
-			uLR      = true
-		)
-		err := api.ParseInt64(p.Get(featureID), featureID, fID)
-		if err != nil {
-			fID = nil
-		}
-		err = api.ParseInt64(p.Get(tagID), tagID, tID)
-		if err != nil {
-			tID = nil
-		}
-		err = api.ParseInt(p.Get(limit), limit, li)
-		if err != nil {
-			li = nil
-		}
-		err = api.ParseInt(p.Get(offset), offset, off)
+		dto, uLR, err := parseListQuery(r)
 		if err != nil {
-			off = nil
+			log.Info("invalid query parameters", sl.Err(err))
+			jsn.EncodeResponse(w, http.StatusBadRequest, api.ErrResponse(err.Error()), log)
+			return
 		}
 
-		bs, err := svc.BannersByFeatureTag(r.Context(), fID, tID, li, off, &uLR)
-		if errors.Is(err, banner.ErrNotFound) {
-			jsn.EncodeResponse(w, http.StatusNotFound, api.ErrResponse(err.Error()), log)
+		page, rev, err := svc.Banners(r.Context(), dto, &uLR)
+		if validErr := new(service.ValidationError); errors.As(err, validErr) {
+			jsn.EncodeResponse(w, http.StatusBadRequest, api.ErrResponse(err.Error()), log)
 			return
 		} else if err != nil {
-			jsn.EncodeResponse(w, http.StatusBadRequest, api.ErrResponse(err.Error()), log)
+			jsn.EncodeResponse(w, http.StatusInternalServerError, api.ErrResponse(err.Error()), log)
 			return
 		}
 
-		resp := make([]GetResponseItem, len(bs))
-		for i, b := range bs {
-			var ri GetResponseItem
-			ri.fromEntity(b)
-			resp[i] = ri
+		api.SetRevisionHeaders(w, rev.Cached, rev.FetchedAt)
+
+		resp := GetResponse{
+			Banners:    make([]GetResponseItem, len(page.Banners)),
+			NextCursor: page.NextCursor,
+			Total:      page.Total,
 		}
-		jsn.EncodeResponse(w, http.StatusOK, GetResponse(resp), log)
+		for i, b := range page.Banners {
+			resp.Banners[i].fromEntity(b)
+		}
+		jsn.EncodeResponse(w, http.StatusOK, resp, log)
 	}
 }
+
+// parseListQuery returns the list dto and the use_last_revision flag by the query parameters of the request.
+// The parameters are optional, but if a parameter is malformed, an error is returned.
+// use_last_revision is true by default for admins.
+func parseListQuery(r *http.Request) (bannerdto.ListDTO, bool, error) {
+	p := r.URL.Query()
+	var (
+		dto bannerdto.ListDTO
+		uLR = true
+	)
+	if p.Has(featureID) {
+		dto.FeatureID = new(int64)
+		if err := api.ParseInt64(p.Get(featureID), featureID, dto.FeatureID); err != nil {
+			return dto, uLR, err
+		}
+	}
+	if p.Has(tagID) {
+		var id int64
+		if err := api.ParseInt64(p.Get(tagID), tagID, &id); err != nil {
+			return dto, uLR, err
+		}
+		dto.TagIDs = append(dto.TagIDs, id)
+	}
+	if p.Has(tagIDs) {
+		if err := api.ParseInt64s(p.Get(tagIDs), tagIDs, &dto.TagIDs); err != nil {
+			return dto, uLR, err
+		}
+	}
+	dto.TagMatch = p.Get(tagMatch)
+	if p.Has(isActive) {
+		dto.IsActive = new(bool)
+		if err := api.ParseBool(p.Get(isActive), isActive, dto.IsActive); err != nil {
+			return dto, uLR, err
+		}
+	}
+	ranges := []struct {
+		name string
+		t    **time.Time
+	}{
+		{createdFrom, &dto.CreatedFrom},
+		{createdTo, &dto.CreatedTo},
+		{updatedFrom, &dto.UpdatedFrom},
+		{updatedTo, &dto.UpdatedTo},
+	}
+	for _, rng := range ranges {
+		if !p.Has(rng.name) {
+			continue
+		}
+		*rng.t = new(time.Time)
+		if err := api.ParseTime(p.Get(rng.name), rng.name, *rng.t); err != nil {
+			return dto, uLR, err
+		}
+	}
+	dto.Q = p.Get(search)
+	if p.Has(limit) {
+		dto.Limit = new(int)
+		if err := api.ParseInt(p.Get(limit), limit, dto.Limit); err != nil {
+			return dto, uLR, err
+		}
+	}
+	if p.Has(offset) {
+		dto.Offset = new(int)
+		if err := api.ParseInt(p.Get(offset), offset, dto.Offset); err != nil {
+			return dto, uLR, err
+		}
+	}
+	if p.Has(useLastRevision) {
+		if err := api.ParseBool(p.Get(useLastRevision), useLastRevision, &uLR); err != nil {
+			return dto, uLR, err
+		}
+	}
+	if p.Has(withTotal) {
+		if err := api.ParseBool(p.Get(withTotal), withTotal, &dto.WithTotal); err != nil {
+			return dto, uLR, err
+		}
+	}
+	dto.Sort = p.Get(sort)
+	dto.Cursor = p.Get(cursor)
+
+	return dto, uLR, nil
+}
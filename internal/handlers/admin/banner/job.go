@@ -0,0 +1,59 @@
+package banner
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"banners-management/internal/lib/api"
+	"banners-management/internal/lib/api/jsn"
+	"banners-management/internal/model/entity"
+	"banners-management/internal/service/banner"
+)
+
+type JobResponse struct {
+	JobID        string     `json:"job_id"`
+	Status       string     `json:"status"`
+	ErrorMessage string     `json:"error_message,omitempty"`
+	Attempts     int64      `json:"attempts"`
+	Affected     int64      `json:"affected"`
+	CreatedAt    time.Time  `json:"created_at"`
+	StartedAt    *time.Time `json:"started_at,omitempty"`
+	FinishedAt   *time.Time `json:"finished_at,omitempty"`
+}
+
+func (jr *JobResponse) fromEntity(j *entity.Job) {
+	jr.JobID = j.ID
+	jr.Status = string(j.Status)
+	jr.ErrorMessage = j.Error
+	jr.Attempts = j.Attempts
+	jr.Affected = j.Affected
+	jr.CreatedAt = j.CreatedAt
+	jr.StartedAt = j.StartedAt
+	jr.FinishedAt = j.FinishedAt
+}
+
+func NewJobHandler(svc *banner.Service, log *slog.Logger) http.HandlerFunc {
+	const comp = "handlers.admin.banner.job"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("comp", comp),
+			slog.String(api.RequestIDKey, api.RequestID(r)),
+		)
+
+		job, err := svc.Job(r.Context(), r.PathValue("id"))
+		if errors.Is(err, banner.ErrJobNotFound) {
+			jsn.EncodeResponse(w, http.StatusNotFound, api.ErrResponse(err.Error()), log)
+			return
+		} else if err != nil {
+			jsn.EncodeResponse(w, http.StatusInternalServerError, api.ErrResponse(err.Error()), log)
+			return
+		}
+
+		resp := new(JobResponse)
+		resp.fromEntity(job)
+		jsn.EncodeResponse(w, http.StatusOK, resp, log)
+	}
+}
@@ -0,0 +1,94 @@
+package banner
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+
+	"banners-management/internal/lib/api"
+	"banners-management/internal/lib/api/jsn"
+	"banners-management/internal/lib/er"
+	"banners-management/internal/model/entity"
+	"banners-management/internal/service/banner"
+)
+
+func NewRestoreVersionHandler(svc *banner.Service, log *slog.Logger) http.HandlerFunc {
+	const comp = "handlers.admin.banner.restore_version"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("comp", comp),
+			slog.String(api.RequestIDKey, api.RequestID(r)),
+		)
+
+		var (
+			id      int64
+			version int
+			resErr  error
+		)
+		if err := api.ParseInt64(r.PathValue("id"), "id", &id); err != nil {
+			resErr = errors.Join(resErr, err)
+		}
+		if err := api.ParseInt(r.PathValue("version"), "version", &version); err != nil {
+			resErr = errors.Join(resErr, err)
+		}
+
+		if resErr != nil {
+			err := er.Unwrap(resErr)
+			log.Info("failed to parse path params", slog.String("error", err))
+			jsn.EncodeResponse(w, http.StatusBadRequest, api.ErrResponse(err), log)
+			return
+		}
+
+		if !ensureCanRestore(w, r, svc, log, id, version) {
+			return
+		}
+
+		err := svc.RestoreBannerVersion(r.Context(), id, version)
+		if errors.Is(err, banner.ErrNotFound) || errors.Is(err, banner.ErrVersionNotFound) {
+			jsn.EncodeResponse(w, http.StatusNotFound, api.ErrResponse(err.Error()), log)
+			return
+		} else if errors.Is(err, banner.ErrUnknownReference) {
+			jsn.EncodeResponse(w, http.StatusUnprocessableEntity, api.ErrResponse(err.Error()), log)
+			return
+		} else if err != nil {
+			jsn.EncodeResponse(w, http.StatusInternalServerError, api.ErrResponse(err.Error()), log)
+			return
+		}
+
+		jsn.EncodeResponse(w, http.StatusOK, api.OkResponse(), log)
+	}
+}
+
+// ensureCanRestore checks if the caller can modify the banner by id and the feature of its version.
+// If not, it writes the error response and returns false.
+func ensureCanRestore(
+	w http.ResponseWriter,
+	r *http.Request,
+	svc *banner.Service,
+	log *slog.Logger,
+	id int64,
+	version int,
+) bool {
+	if api.UserPrincipal(r).IsAdmin() {
+		return true
+	}
+
+	versions, err := svc.BannerVersions(r.Context(), id)
+	if errors.Is(err, banner.ErrNotFound) {
+		jsn.EncodeResponse(w, http.StatusNotFound, api.ErrResponse(err.Error()), log)
+		return false
+	} else if err != nil {
+		jsn.EncodeResponse(w, http.StatusInternalServerError, api.ErrResponse(err.Error()), log)
+		return false
+	}
+
+	i := slices.IndexFunc(versions, func(v *entity.BannerVersion) bool { return v.Version == version })
+	if i < 0 {
+		jsn.EncodeResponse(w, http.StatusNotFound, api.ErrResponse(banner.ErrVersionNotFound.Error()), log)
+		return false
+	}
+
+	return ensureCanWriteBanner(w, r, svc, log, id, versions[i].FeatureID)
+}
@@ -0,0 +1,98 @@
+package banner
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"banners-management/internal/lib/api"
+	"banners-management/internal/lib/api/jsn"
+	"banners-management/internal/lib/er"
+	"banners-management/internal/model/entity"
+	"banners-management/internal/service"
+	"banners-management/internal/service/banner"
+)
+
+const (
+	statsFrom = "from"
+	statsTo   = "to"
+)
+
+type StatsResponse []StatsResponseItem
+
+type StatsResponseItem struct {
+	Day         string  `json:"day"`
+	Impressions int64   `json:"impressions"`
+	Clicks      int64   `json:"clicks"`
+	CTR         float64 `json:"ctr"`
+}
+
+func (ri *StatsResponseItem) fromEntity(st *entity.BannerStats) {
+	ri.Day = st.Day.Format(time.DateOnly)
+	ri.Impressions = st.Impressions
+	ri.Clicks = st.Clicks
+	ri.CTR = st.CTR()
+}
+
+// NewStatsHandler returns a handler, that returns the daily impressions and clicks of the banner
+// within the optional from and to days (YYYY-MM-DD, UTC) inclusive.
+// The optional variant_id narrows the stats down to a single variant, and 0 means the banner content itself.
+func NewStatsHandler(svc *banner.Service, log *slog.Logger) http.HandlerFunc {
+	const comp = "handlers.admin.banner.stats"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("comp", comp),
+			slog.String(api.RequestIDKey, api.RequestID(r)),
+		)
+
+		p := r.URL.Query()
+		var (
+			id       int64
+			vID      *int64
+			from, to *time.Time
+			resErr   error
+		)
+		if err := api.ParseInt64(r.PathValue("id"), "id", &id); err != nil {
+			resErr = errors.Join(resErr, err)
+		}
+		if p.Has(variantID) {
+			vID = new(int64)
+			resErr = errors.Join(resErr, api.ParseInt64(p.Get(variantID), variantID, vID))
+		}
+		if p.Has(statsFrom) {
+			from = new(time.Time)
+			resErr = errors.Join(resErr, api.ParseDate(p.Get(statsFrom), statsFrom, from))
+		}
+		if p.Has(statsTo) {
+			to = new(time.Time)
+			resErr = errors.Join(resErr, api.ParseDate(p.Get(statsTo), statsTo, to))
+		}
+
+		if resErr != nil {
+			err := er.Unwrap(resErr)
+			log.Info("failed to parse request params", slog.String("error", err))
+			jsn.EncodeResponse(w, http.StatusBadRequest, api.ErrResponse(err), log)
+			return
+		}
+
+		stats, err := svc.BannerStats(r.Context(), id, vID, from, to)
+		if validErr := new(service.ValidationError); errors.As(err, validErr) {
+			jsn.EncodeResponse(w, http.StatusBadRequest, api.ErrResponse(err.Error()), log)
+			return
+		} else if errors.Is(err, banner.ErrNotFound) {
+			jsn.EncodeResponse(w, http.StatusNotFound, api.ErrResponse(err.Error()), log)
+			return
+		} else if err != nil {
+			jsn.EncodeResponse(w, http.StatusInternalServerError, api.ErrResponse(err.Error()), log)
+			return
+		}
+
+		resp := make([]StatsResponseItem, len(stats))
+		for i, st := range stats {
+			resp[i].fromEntity(st)
+		}
+		jsn.EncodeResponse(w, http.StatusOK, StatsResponse(resp), log)
+	}
+}
@@ -35,6 +35,14 @@ func NewUpdateHandler(svc *bannersvc.Service, log *slog.Logger) http.HandlerFunc
 			return
 		}
 
+		var newFeatureIDs []int64
+		if req.FeatureID != nil {
+			newFeatureIDs = append(newFeatureIDs, *req.FeatureID)
+		}
+		if !ensureCanWriteBanner(w, r, svc, log, id, newFeatureIDs...) {
+			return
+		}
+
 		err = svc.UpdateBanner(r.Context(), id, *req)
 		if validErr := new(service.ValidationError); errors.As(err, validErr) {
 			jsn.EncodeResponse(w, http.StatusBadRequest, api.ErrResponse(validErr.Error()), log)
@@ -42,8 +50,8 @@ func NewUpdateHandler(svc *bannersvc.Service, log *slog.Logger) http.HandlerFunc
 		} else if errors.Is(err, bannersvc.ErrNotFound) {
 			jsn.EncodeResponse(w, http.StatusNotFound, api.ErrResponse(err.Error()), log)
 			return
-		} else if errors.Is(err, bannersvc.ErrAlreadyExists) {
-			jsn.EncodeResponse(w, http.StatusConflict, api.ErrResponse(err.Error()), log)
+		} else if errors.Is(err, bannersvc.ErrUnknownReference) {
+			jsn.EncodeResponse(w, http.StatusUnprocessableEntity, api.ErrResponse(err.Error()), log)
 			return
 		} else if err != nil {
 			jsn.EncodeResponse(w, http.StatusInternalServerError, api.ErrResponse(err.Error()), log)
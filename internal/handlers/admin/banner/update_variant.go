@@ -0,0 +1,56 @@
+package banner
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"banners-management/internal/lib/api"
+	"banners-management/internal/lib/api/jsn"
+	"banners-management/internal/lib/logger/sl"
+	bannerdto "banners-management/internal/model/dto/banner"
+	"banners-management/internal/service"
+	bannersvc "banners-management/internal/service/banner"
+)
+
+func NewUpdateVariantHandler(svc *bannersvc.Service, log *slog.Logger) http.HandlerFunc {
+	const comp = "handlers.admin.banner.update_variant"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("comp", comp),
+			slog.String(api.RequestIDKey, api.RequestID(r)),
+		)
+
+		id, vID, err := parseVariantPath(r)
+		if err != nil {
+			log.Info("failed to parse path params", sl.Err(err))
+			jsn.EncodeResponse(w, http.StatusBadRequest, api.ErrResponse(err.Error()), log)
+			return
+		}
+		req := new(bannerdto.UpdateVariantDTO)
+		err = jsn.DecodeRequest(r, req, log)
+		if err != nil {
+			jsn.EncodeResponse(w, http.StatusBadRequest, api.ErrResponse(err.Error()), log)
+			return
+		}
+
+		if !ensureCanWriteBanner(w, r, svc, log, id) {
+			return
+		}
+
+		err = svc.UpdateBannerVariant(r.Context(), id, vID, *req)
+		if validErr := new(service.ValidationError); errors.As(err, validErr) {
+			jsn.EncodeResponse(w, http.StatusBadRequest, api.ErrResponse(validErr.Error()), log)
+			return
+		} else if errors.Is(err, bannersvc.ErrNotFound) || errors.Is(err, bannersvc.ErrVariantNotFound) {
+			jsn.EncodeResponse(w, http.StatusNotFound, api.ErrResponse(err.Error()), log)
+			return
+		} else if err != nil {
+			jsn.EncodeResponse(w, http.StatusInternalServerError, api.ErrResponse(err.Error()), log)
+			return
+		}
+
+		jsn.EncodeResponse(w, http.StatusOK, api.OkResponse(), log)
+	}
+}
@@ -0,0 +1,97 @@
+package banner
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"banners-management/internal/lib/api"
+	"banners-management/internal/lib/api/jsn"
+	"banners-management/internal/lib/er"
+	"banners-management/internal/lib/logger/sl"
+	"banners-management/internal/model/entity"
+	"banners-management/internal/service/banner"
+)
+
+const variantID = "variant_id"
+
+type VariantsResponse []VariantsResponseItem
+
+type VariantsResponseItem struct {
+	VariantID int64 `json:"variant_id"`
+	BannerID  int64 `json:"banner_id"`
+	Content   struct {
+		Title string `json:"title"`
+		Text  string `json:"text"`
+		URL   string `json:"url"`
+	} `json:"content"`
+	Weight    int       `json:"weight"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (ri *VariantsResponseItem) fromEntity(v *entity.BannerVariant) {
+	ri.VariantID = v.ID
+	ri.BannerID = v.BannerID
+	ri.Content.Title = v.Title
+	ri.Content.Text = v.Text
+	ri.Content.URL = v.URL
+	ri.Weight = v.Weight
+	ri.CreatedAt = v.CreatedAt
+	ri.UpdatedAt = v.UpdatedAt
+}
+
+func NewVariantsHandler(svc *banner.Service, log *slog.Logger) http.HandlerFunc {
+	const comp = "handlers.admin.banner.variants"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("comp", comp),
+			slog.String(api.RequestIDKey, api.RequestID(r)),
+		)
+
+		var id int64
+		err := api.ParseInt64(r.PathValue("id"), "id", &id)
+		if err != nil {
+			log.Info("failed to parse id", sl.Err(err))
+			jsn.EncodeResponse(w, http.StatusBadRequest, api.ErrResponse(err.Error()), log)
+			return
+		}
+
+		vs, err := svc.BannerVariants(r.Context(), id)
+		if errors.Is(err, banner.ErrNotFound) {
+			jsn.EncodeResponse(w, http.StatusNotFound, api.ErrResponse(err.Error()), log)
+			return
+		} else if err != nil {
+			jsn.EncodeResponse(w, http.StatusInternalServerError, api.ErrResponse(err.Error()), log)
+			return
+		}
+
+		resp := make([]VariantsResponseItem, len(vs))
+		for i, v := range vs {
+			resp[i].fromEntity(v)
+		}
+		jsn.EncodeResponse(w, http.StatusOK, VariantsResponse(resp), log)
+	}
+}
+
+// parseVariantPath returns the banner id and the variant id from the path of the request r.
+// The error is unwrapped, so it can be returned to the client as is.
+func parseVariantPath(r *http.Request) (int64, int64, error) {
+	var (
+		id, vID int64
+		resErr  error
+	)
+	if err := api.ParseInt64(r.PathValue("id"), "id", &id); err != nil {
+		resErr = errors.Join(resErr, err)
+	}
+	if err := api.ParseInt64(r.PathValue(variantID), variantID, &vID); err != nil {
+		resErr = errors.Join(resErr, err)
+	}
+	if resErr != nil {
+		return 0, 0, errors.New(er.Unwrap(resErr))
+	}
+
+	return id, vID, nil
+}
@@ -0,0 +1,84 @@
+package banner
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"banners-management/internal/lib/api"
+	"banners-management/internal/lib/api/jsn"
+	"banners-management/internal/lib/logger/sl"
+	"banners-management/internal/model/entity"
+	"banners-management/internal/service/banner"
+)
+
+type VersionsResponse []VersionsResponseItem
+
+type VersionsResponseItem struct {
+	Version   int     `json:"version"`
+	BannerID  int64   `json:"banner_id"`
+	TagIDs    []int64 `json:"tag_ids"`
+	FeatureID int64   `json:"feature_id"`
+	Content   struct {
+		Title string `json:"title"`
+		Text  string `json:"text"`
+		URL   string `json:"url"`
+	} `json:"content"`
+	IsActive    bool       `json:"is_active"`
+	Priority    int        `json:"priority"`
+	ActiveFrom  *time.Time `json:"active_from,omitempty"`
+	ActiveUntil *time.Time `json:"active_until,omitempty"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	ArchivedAt  time.Time  `json:"archived_at"`
+}
+
+func (ri *VersionsResponseItem) fromEntity(v *entity.BannerVersion) {
+	ri.Version = v.Version
+	ri.BannerID = v.BannerID
+	ri.TagIDs = v.TagIDs
+	ri.FeatureID = v.FeatureID
+	ri.Content.Title = v.Title
+	ri.Content.Text = v.Text
+	ri.Content.URL = v.URL
+	ri.IsActive = v.IsActive
+	ri.Priority = v.Priority
+	ri.ActiveFrom = v.ActiveFrom
+	ri.ActiveUntil = v.ActiveUntil
+	ri.UpdatedAt = v.UpdatedAt
+	ri.ArchivedAt = v.ArchivedAt
+}
+
+func NewVersionsHandler(svc *banner.Service, log *slog.Logger) http.HandlerFunc {
+	const comp = "handlers.admin.banner.versions"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("comp", comp),
+			slog.String(api.RequestIDKey, api.RequestID(r)),
+		)
+
+		var id int64
+		err := api.ParseInt64(r.PathValue("id"), "id", &id)
+		if err != nil {
+			log.Info("failed to parse id", sl.Err(err))
+			jsn.EncodeResponse(w, http.StatusBadRequest, api.ErrResponse(err.Error()), log)
+			return
+		}
+
+		vs, err := svc.BannerVersions(r.Context(), id)
+		if errors.Is(err, banner.ErrNotFound) {
+			jsn.EncodeResponse(w, http.StatusNotFound, api.ErrResponse(err.Error()), log)
+			return
+		} else if err != nil {
+			jsn.EncodeResponse(w, http.StatusInternalServerError, api.ErrResponse(err.Error()), log)
+			return
+		}
+
+		resp := make([]VersionsResponseItem, len(vs))
+		for i, v := range vs {
+			resp[i].fromEntity(v)
+		}
+		jsn.EncodeResponse(w, http.StatusOK, VersionsResponse(resp), log)
+	}
+}
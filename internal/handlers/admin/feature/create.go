@@ -0,0 +1,50 @@
+package feature
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"banners-management/internal/lib/api"
+	"banners-management/internal/lib/api/jsn"
+	featuredto "banners-management/internal/model/dto/feature"
+	"banners-management/internal/service"
+	featuresvc "banners-management/internal/service/feature"
+)
+
+type CreateResponse struct {
+	FeatureID int64 `json:"feature_id,omitempty"`
+	api.Response
+}
+
+func NewCreateHandler(svc *featuresvc.Service, log *slog.Logger) http.HandlerFunc {
+	const comp = "handlers.admin.feature.create"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("comp", comp),
+			slog.String(api.RequestIDKey, api.RequestID(r)),
+		)
+
+		req := new(featuredto.CreateDTO)
+		err := jsn.DecodeRequest(r, req, log)
+		if err != nil {
+			jsn.EncodeResponse(w, http.StatusBadRequest, api.ErrResponse(err.Error()), log)
+			return
+		}
+
+		id, err := svc.SaveFeature(r.Context(), *req)
+		if errors.Is(err, featuresvc.ErrAlreadyExists) {
+			jsn.EncodeResponse(w, http.StatusConflict, api.ErrResponse(err.Error()), log)
+			return
+		} else if validErr := new(service.ValidationError); errors.As(err, validErr) {
+			jsn.EncodeResponse(w, http.StatusBadRequest, api.ErrResponse(validErr.Error()), log)
+			return
+		} else if err != nil {
+			jsn.EncodeResponse(w, http.StatusInternalServerError, api.ErrResponse(err.Error()), log)
+			return
+		}
+
+		jsn.EncodeResponse(w, http.StatusCreated, CreateResponse{FeatureID: id}, log)
+	}
+}
@@ -0,0 +1,98 @@
+package feature
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"banners-management/internal/lib/api"
+	"banners-management/internal/lib/api/jsn"
+	"banners-management/internal/lib/logger/sl"
+	"banners-management/internal/model/entity"
+	"banners-management/internal/service/feature"
+)
+
+const (
+	limit  = "limit"
+	offset = "offset"
+)
+
+type GetResponse []GetResponseItem
+
+type GetResponseItem struct {
+	FeatureID   int64  `json:"feature_id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func (ri *GetResponseItem) fromEntity(f *entity.Feature) {
+	ri.FeatureID = f.ID
+	ri.Name = f.Name
+	ri.Description = f.Description
+}
+
+func NewGetHandler(svc *feature.Service, log *slog.Logger) http.HandlerFunc {
+	const comp = "handlers.admin.feature.get"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("comp", comp),
+			slog.String(api.RequestIDKey, api.RequestID(r)),
+		)
+
+		var id int64
+		err := api.ParseInt64(r.PathValue("id"), "id", &id)
+		if err != nil {
+			log.Info("failed to parse id", sl.Err(err))
+			jsn.EncodeResponse(w, http.StatusBadRequest, api.ErrResponse(err.Error()), log)
+			return
+		}
+
+		f, err := svc.Feature(r.Context(), id)
+		if errors.Is(err, feature.ErrNotFound) {
+			jsn.EncodeResponse(w, http.StatusNotFound, api.ErrResponse(err.Error()), log)
+			return
+		} else if err != nil {
+			jsn.EncodeResponse(w, http.StatusInternalServerError, api.ErrResponse(err.Error()), log)
+			return
+		}
+
+		resp := new(GetResponseItem)
+		resp.fromEntity(f)
+		jsn.EncodeResponse(w, http.StatusOK, resp, log)
+	}
+}
+
+func NewListHandler(svc *feature.Service, log *slog.Logger) http.HandlerFunc {
+	const comp = "handlers.admin.feature.list"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("comp", comp),
+			slog.String(api.RequestIDKey, api.RequestID(r)),
+		)
+
+		p := r.URL.Query()
+		li, off := new(int), new(int)
+		err := api.ParseInt(p.Get(limit), limit, li)
+		if err != nil {
+			li = nil
+		}
+		err = api.ParseInt(p.Get(offset), offset, off)
+		if err != nil {
+			off = nil
+		}
+
+		fs, err := svc.Features(r.Context(), li, off)
+		if err != nil {
+			jsn.EncodeResponse(w, http.StatusInternalServerError, api.ErrResponse(err.Error()), log)
+			return
+		}
+
+		resp := make([]GetResponseItem, len(fs))
+		for i, f := range fs {
+			resp[i].fromEntity(f)
+		}
+		jsn.EncodeResponse(w, http.StatusOK, GetResponse(resp), log)
+	}
+}
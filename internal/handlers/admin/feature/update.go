@@ -0,0 +1,49 @@
+package feature
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"banners-management/internal/lib/api"
+	"banners-management/internal/lib/api/jsn"
+	"banners-management/internal/lib/logger/sl"
+	featuredto "banners-management/internal/model/dto/feature"
+	featuresvc "banners-management/internal/service/feature"
+)
+
+func NewUpdateHandler(svc *featuresvc.Service, log *slog.Logger) http.HandlerFunc {
+	const comp = "handlers.admin.feature.update"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("comp", comp),
+			slog.String(api.RequestIDKey, api.RequestID(r)),
+		)
+
+		var id int64
+		err := api.ParseInt64(r.PathValue("id"), "id", &id)
+		if err != nil {
+			log.Info("failed to parse id", sl.Err(err))
+			jsn.EncodeResponse(w, http.StatusBadRequest, api.ErrResponse(err.Error()), log)
+			return
+		}
+		req := new(featuredto.UpdateDTO)
+		err = jsn.DecodeRequest(r, req, log)
+		if err != nil {
+			jsn.EncodeResponse(w, http.StatusBadRequest, api.ErrResponse(err.Error()), log)
+			return
+		}
+
+		err = svc.UpdateFeature(r.Context(), id, *req)
+		if errors.Is(err, featuresvc.ErrNotFound) {
+			jsn.EncodeResponse(w, http.StatusNotFound, api.ErrResponse(err.Error()), log)
+			return
+		} else if err != nil {
+			jsn.EncodeResponse(w, http.StatusInternalServerError, api.ErrResponse(err.Error()), log)
+			return
+		}
+
+		jsn.EncodeResponse(w, http.StatusOK, api.OkResponse(), log)
+	}
+}
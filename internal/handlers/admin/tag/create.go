@@ -0,0 +1,50 @@
+package tag
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"banners-management/internal/lib/api"
+	"banners-management/internal/lib/api/jsn"
+	tagdto "banners-management/internal/model/dto/tag"
+	"banners-management/internal/service"
+	tagsvc "banners-management/internal/service/tag"
+)
+
+type CreateResponse struct {
+	TagID int64 `json:"tag_id,omitempty"`
+	api.Response
+}
+
+func NewCreateHandler(svc *tagsvc.Service, log *slog.Logger) http.HandlerFunc {
+	const comp = "handlers.admin.tag.create"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("comp", comp),
+			slog.String(api.RequestIDKey, api.RequestID(r)),
+		)
+
+		req := new(tagdto.CreateDTO)
+		err := jsn.DecodeRequest(r, req, log)
+		if err != nil {
+			jsn.EncodeResponse(w, http.StatusBadRequest, api.ErrResponse(err.Error()), log)
+			return
+		}
+
+		id, err := svc.SaveTag(r.Context(), *req)
+		if errors.Is(err, tagsvc.ErrAlreadyExists) {
+			jsn.EncodeResponse(w, http.StatusConflict, api.ErrResponse(err.Error()), log)
+			return
+		} else if validErr := new(service.ValidationError); errors.As(err, validErr) {
+			jsn.EncodeResponse(w, http.StatusBadRequest, api.ErrResponse(validErr.Error()), log)
+			return
+		} else if err != nil {
+			jsn.EncodeResponse(w, http.StatusInternalServerError, api.ErrResponse(err.Error()), log)
+			return
+		}
+
+		jsn.EncodeResponse(w, http.StatusCreated, CreateResponse{TagID: id}, log)
+	}
+}
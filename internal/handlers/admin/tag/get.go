@@ -0,0 +1,98 @@
+package tag
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"banners-management/internal/lib/api"
+	"banners-management/internal/lib/api/jsn"
+	"banners-management/internal/lib/logger/sl"
+	"banners-management/internal/model/entity"
+	"banners-management/internal/service/tag"
+)
+
+const (
+	limit  = "limit"
+	offset = "offset"
+)
+
+type GetResponse []GetResponseItem
+
+type GetResponseItem struct {
+	TagID       int64  `json:"tag_id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func (ri *GetResponseItem) fromEntity(t *entity.Tag) {
+	ri.TagID = t.ID
+	ri.Name = t.Name
+	ri.Description = t.Description
+}
+
+func NewGetHandler(svc *tag.Service, log *slog.Logger) http.HandlerFunc {
+	const comp = "handlers.admin.tag.get"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("comp", comp),
+			slog.String(api.RequestIDKey, api.RequestID(r)),
+		)
+
+		var id int64
+		err := api.ParseInt64(r.PathValue("id"), "id", &id)
+		if err != nil {
+			log.Info("failed to parse id", sl.Err(err))
+			jsn.EncodeResponse(w, http.StatusBadRequest, api.ErrResponse(err.Error()), log)
+			return
+		}
+
+		t, err := svc.Tag(r.Context(), id)
+		if errors.Is(err, tag.ErrNotFound) {
+			jsn.EncodeResponse(w, http.StatusNotFound, api.ErrResponse(err.Error()), log)
+			return
+		} else if err != nil {
+			jsn.EncodeResponse(w, http.StatusInternalServerError, api.ErrResponse(err.Error()), log)
+			return
+		}
+
+		resp := new(GetResponseItem)
+		resp.fromEntity(t)
+		jsn.EncodeResponse(w, http.StatusOK, resp, log)
+	}
+}
+
+func NewListHandler(svc *tag.Service, log *slog.Logger) http.HandlerFunc {
+	const comp = "handlers.admin.tag.list"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("comp", comp),
+			slog.String(api.RequestIDKey, api.RequestID(r)),
+		)
+
+		p := r.URL.Query()
+		li, off := new(int), new(int)
+		err := api.ParseInt(p.Get(limit), limit, li)
+		if err != nil {
+			li = nil
+		}
+		err = api.ParseInt(p.Get(offset), offset, off)
+		if err != nil {
+			off = nil
+		}
+
+		ts, err := svc.Tags(r.Context(), li, off)
+		if err != nil {
+			jsn.EncodeResponse(w, http.StatusInternalServerError, api.ErrResponse(err.Error()), log)
+			return
+		}
+
+		resp := make([]GetResponseItem, len(ts))
+		for i, t := range ts {
+			resp[i].fromEntity(t)
+		}
+		jsn.EncodeResponse(w, http.StatusOK, GetResponse(resp), log)
+	}
+}
@@ -10,7 +10,8 @@ import (
 )
 
 type Response struct {
-	Token string `json:"token"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token,omitempty"`
 }
 
 func NewAuthHandler(j *jwt.Manager, log *slog.Logger) http.HandlerFunc {
@@ -30,7 +31,7 @@ func NewAuthHandler(j *jwt.Manager, log *slog.Logger) http.HandlerFunc {
 			return
 		}
 
-		token, err := j.GenerateToken(role)
+		token, err := j.GenerateToken(role, p["scope"]...)
 		if err != nil {
 			log.Info("failed to generate token", sl.Err(err))
 			jsn.EncodeResponse(w, http.StatusInternalServerError, api.ErrResponse("failed to generate token"), log)
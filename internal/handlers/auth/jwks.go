@@ -0,0 +1,27 @@
+package auth
+
+import (
+	"log/slog"
+	"net/http"
+
+	"banners-management/internal/lib/api"
+	"banners-management/internal/lib/api/jsn"
+	"banners-management/internal/lib/jwt"
+)
+
+type JWKSResponse struct {
+	Keys []jwt.JWK `json:"keys"`
+}
+
+func NewJWKSHandler(j *jwt.Manager, log *slog.Logger) http.HandlerFunc {
+	const comp = "handlers.auth.jwks"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("comp", comp),
+			slog.String(api.RequestIDKey, api.RequestID(r)),
+		)
+
+		jsn.EncodeResponse(w, http.StatusOK, JWKSResponse{Keys: j.PublicKeys()}, log)
+	}
+}
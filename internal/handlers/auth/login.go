@@ -0,0 +1,45 @@
+package auth
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"banners-management/internal/lib/api"
+	"banners-management/internal/lib/api/jsn"
+	authdto "banners-management/internal/model/dto/auth"
+	"banners-management/internal/service"
+	authsvc "banners-management/internal/service/auth"
+)
+
+func NewLoginHandler(svc *authsvc.Service, log *slog.Logger) http.HandlerFunc {
+	const comp = "handlers.auth.login"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("comp", comp),
+			slog.String(api.RequestIDKey, api.RequestID(r)),
+		)
+
+		req := new(authdto.LoginDTO)
+		err := jsn.DecodeRequest(r, req, log)
+		if err != nil {
+			jsn.EncodeResponse(w, http.StatusBadRequest, api.ErrResponse(err.Error()), log)
+			return
+		}
+
+		tokens, err := svc.Login(r.Context(), *req)
+		if errors.Is(err, authsvc.ErrInvalidCredentials) {
+			jsn.EncodeResponse(w, http.StatusUnauthorized, api.ErrResponse(err.Error()), log)
+			return
+		} else if validErr := new(service.ValidationError); errors.As(err, validErr) {
+			jsn.EncodeResponse(w, http.StatusBadRequest, api.ErrResponse(validErr.Error()), log)
+			return
+		} else if err != nil {
+			jsn.EncodeResponse(w, http.StatusInternalServerError, api.ErrResponse(err.Error()), log)
+			return
+		}
+
+		jsn.EncodeResponse(w, http.StatusOK, Response{Token: tokens.Access, RefreshToken: tokens.Refresh}, log)
+	}
+}
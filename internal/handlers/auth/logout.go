@@ -0,0 +1,45 @@
+package auth
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"banners-management/internal/lib/api"
+	"banners-management/internal/lib/api/jsn"
+	authdto "banners-management/internal/model/dto/auth"
+	"banners-management/internal/service"
+	authsvc "banners-management/internal/service/auth"
+)
+
+func NewLogoutHandler(svc *authsvc.Service, log *slog.Logger) http.HandlerFunc {
+	const comp = "handlers.auth.logout"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("comp", comp),
+			slog.String(api.RequestIDKey, api.RequestID(r)),
+		)
+
+		req := new(authdto.RefreshDTO)
+		err := jsn.DecodeRequest(r, req, log)
+		if err != nil {
+			jsn.EncodeResponse(w, http.StatusBadRequest, api.ErrResponse(err.Error()), log)
+			return
+		}
+
+		err = svc.Logout(r.Context(), api.UserSubject(r), api.TokenID(r), *req)
+		if validErr := new(service.ValidationError); errors.As(err, validErr) {
+			jsn.EncodeResponse(w, http.StatusBadRequest, api.ErrResponse(validErr.Error()), log)
+			return
+		} else if errors.Is(err, authsvc.ErrForeignRefresh) {
+			jsn.EncodeResponse(w, http.StatusForbidden, api.ErrResponse(err.Error()), log)
+			return
+		} else if err != nil {
+			jsn.EncodeResponse(w, http.StatusInternalServerError, api.ErrResponse(err.Error()), log)
+			return
+		}
+
+		jsn.EncodeResponse(w, http.StatusOK, api.OkResponse(), log)
+	}
+}
@@ -0,0 +1,59 @@
+package banner
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"banners-management/internal/lib/api"
+	"banners-management/internal/lib/api/jsn"
+	"banners-management/internal/lib/er"
+	"banners-management/internal/service/banner"
+)
+
+// NewClickHandler returns a handler, that counts a click of the banner content, resolved for a user
+// the same way as by NewGetHandler, and redirects the user to its URL.
+func NewClickHandler(svc *banner.Service, log *slog.Logger) http.HandlerFunc {
+	const comp = "handlers.banner.click"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("comp", comp),
+			slog.String(api.RequestIDKey, api.RequestID(r)),
+		)
+
+		p := r.URL.Query()
+		var (
+			fID, tID int64
+			resErr   error
+		)
+		if err := api.ParseInt64(p.Get(featureID), featureID, &fID); err != nil {
+			resErr = errors.Join(resErr, err)
+		}
+		if err := api.ParseInt64(p.Get(tagID), tagID, &tID); err != nil {
+			resErr = errors.Join(resErr, err)
+		}
+
+		if resErr != nil {
+			err := er.Unwrap(resErr)
+			log.Info("failed to parse query params", slog.String("error", err))
+			jsn.EncodeResponse(w, http.StatusBadRequest, api.ErrResponse(err), log)
+			return
+		}
+
+		url, err := svc.UserBannerClick(r.Context(), fID, tID, p.Get(userID))
+		if errors.Is(err, banner.ErrNotActive) {
+			jsn.EncodeResponse(w, http.StatusForbidden, api.ErrResponse(err.Error()), log)
+			return
+		} else if errors.Is(err, banner.ErrNotFound) {
+			jsn.EncodeResponse(w, http.StatusNotFound, api.ErrResponse(err.Error()), log)
+			return
+		} else if err != nil {
+			jsn.EncodeResponse(w, http.StatusInternalServerError, api.ErrResponse(err.Error()), log)
+			return
+		}
+
+		w.Header().Del("Content-Type") // the redirect body is not json
+		http.Redirect(w, r, url, http.StatusFound)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"banners-management/internal/lib/api"
 	"banners-management/internal/lib/api/jsn"
 	"banners-management/internal/lib/er"
+	"banners-management/internal/model/entity"
 	"banners-management/internal/service/banner"
 )
 
@@ -15,15 +16,31 @@ const (
 	featureID       = "feature_id"
 	tagID           = "tag_id"
 	useLastRevision = "use_last_revision"
+	userID          = "user_id"
 )
 
 type GetResponse struct {
-	Title string `json:"title,omitempty"`
-	Text  string `json:"text,omitempty"`
-	URL   string `json:"url,omitempty"`
+	Title     string `json:"title,omitempty"`
+	Text      string `json:"text,omitempty"`
+	URL       string `json:"url,omitempty"`
+	VariantID *int64 `json:"variant_id,omitempty"`
 	api.Response
 }
 
+// newGetResponse returns the content of the banner variant v, assigned to the user,
+// or the content of the banner itself, if no variant is assigned.
+func newGetResponse(b *entity.Banner, v *entity.BannerVariant) GetResponse {
+	if v == nil {
+		return GetResponse{Title: b.Title, Text: b.Text, URL: b.URL, Response: api.OkResponse()}
+	}
+
+	return GetResponse{v.Title, v.Text, v.URL, &v.ID, api.OkResponse()}
+}
+
+// NewGetHandler returns a handler, that resolves the banner of the feature for a user with the tag.
+// If the banner has variants, the user, identified by the optional user_id, is assigned one of them
+// by the weights of the variants, and always gets the same one while the weights are unchanged.
+// The impression of the returned content is counted.
 func NewGetHandler(svc *banner.Service, log *slog.Logger) http.HandlerFunc {
 	const comp = "handlers.banner.get"
 
@@ -56,7 +73,8 @@ func NewGetHandler(svc *banner.Service, log *slog.Logger) http.HandlerFunc {
 			return
 		}
 
-		b, err := svc.BannerByFeatureTag(r.Context(), fID, tID, uLR, true)
+		b, v, rev, err := svc.UserBanner(r.Context(), fID, tID, p.Get(userID), uLR)
+		api.SetRevisionHeaders(w, rev.Cached, rev.FetchedAt)
 		if errors.Is(err, banner.ErrNotActive) {
 			jsn.EncodeResponse(w, http.StatusForbidden, api.ErrResponse(err.Error()), log)
 			return
@@ -68,11 +86,6 @@ func NewGetHandler(svc *banner.Service, log *slog.Logger) http.HandlerFunc {
 			return
 		}
 
-		jsn.EncodeResponse(w, http.StatusOK, GetResponse{
-			b.Title,
-			b.Text,
-			b.URL,
-			api.OkResponse(),
-		}, log)
+		jsn.EncodeResponse(w, http.StatusOK, newGetResponse(b, v), log)
 	}
 }
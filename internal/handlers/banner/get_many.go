@@ -0,0 +1,109 @@
+package banner
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"banners-management/internal/lib/api"
+	"banners-management/internal/lib/api/jsn"
+	"banners-management/internal/lib/er"
+	"banners-management/internal/service"
+	"banners-management/internal/service/banner"
+)
+
+const (
+	featureIDs = "feature_ids"
+	tagIDs     = "tag_ids"
+)
+
+type GetManyResponse struct {
+	Banners []GetManyResponseItem `json:"banners"`
+	api.Response
+}
+
+type GetManyResponseItem struct {
+	FeatureID int64  `json:"feature_id"`
+	TagID     int64  `json:"tag_id"`
+	Title     string `json:"title,omitempty"`
+	Text      string `json:"text,omitempty"`
+	URL       string `json:"url,omitempty"`
+	VariantID *int64 `json:"variant_id,omitempty"`
+}
+
+func (ri *GetManyResponseItem) fromUserBanner(ub *banner.UserBanner) {
+	ri.FeatureID = ub.FeatureID
+	ri.TagID = ub.TagID
+	resp := newGetResponse(ub.Banner, ub.Variant)
+	ri.Title = resp.Title
+	ri.Text = resp.Text
+	ri.URL = resp.URL
+	ri.VariantID = resp.VariantID
+}
+
+// NewGetManyHandler returns a handler, that resolves the banners of several features
+// for a user with several tags at once. The features are passed by feature_id and/or comma-separated feature_ids,
+// and the tags by tag_id and/or tag_ids, in the order of their priority.
+// The banner variants are assigned to the user by user_id the same way as by NewGetHandler.
+func NewGetManyHandler(svc *banner.Service, log *slog.Logger) http.HandlerFunc {
+	const comp = "handlers.banner.get_many"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := log.With(
+			slog.String("comp", comp),
+			slog.String(api.RequestIDKey, api.RequestID(r)),
+		)
+
+		p := r.URL.Query()
+		var (
+			fIDs, tIDs []int64
+			uLR        bool
+			resErr     error
+		)
+		resErr = errors.Join(resErr, parseIDs(p.Get, featureID, featureIDs, &fIDs))
+		resErr = errors.Join(resErr, parseIDs(p.Get, tagID, tagIDs, &tIDs))
+		if err := api.ParseBool(p.Get(useLastRevision), useLastRevision, &uLR); err != nil {
+			uLR = false // no error, parameter is optional. default is false
+		}
+
+		if resErr != nil {
+			err := er.Unwrap(resErr)
+			log.Info("failed to parse query params", slog.String("error", err))
+			jsn.EncodeResponse(w, http.StatusBadRequest, api.ErrResponse(err), log)
+			return
+		}
+
+		ubs, rev, err := svc.UserBanners(r.Context(), fIDs, tIDs, p.Get(userID), uLR)
+		api.SetRevisionHeaders(w, rev.Cached, rev.FetchedAt)
+		if validErr := new(service.ValidationError); errors.As(err, validErr) {
+			jsn.EncodeResponse(w, http.StatusBadRequest, api.ErrResponse(err.Error()), log)
+			return
+		} else if err != nil {
+			jsn.EncodeResponse(w, http.StatusInternalServerError, api.ErrResponse(err.Error()), log)
+			return
+		}
+
+		resp := GetManyResponse{Banners: make([]GetManyResponseItem, len(ubs))}
+		for i, ub := range ubs {
+			resp.Banners[i].fromUserBanner(ub)
+		}
+		jsn.EncodeResponse(w, http.StatusOK, resp, log)
+	}
+}
+
+// parseIDs parses the single id parameter and the comma-separated list parameter into ids.
+// The parameters are optional, but if a parameter is present, it must be well-formed.
+func parseIDs(get func(string) string, single, list string, ids *[]int64) error {
+	if s := get(single); s != "" {
+		var id int64
+		if err := api.ParseInt64(s, single, &id); err != nil {
+			return err
+		}
+		*ids = append(*ids, id)
+	}
+	if s := get(list); s != "" {
+		return api.ParseInt64s(s, list, ids)
+	}
+
+	return nil
+}
@@ -3,12 +3,13 @@
 package msg
 
 const (
-	APIUnknownErr     = "unknown error"
-	APIInternalErr    = "internal error"
-	APIInvalidRequest = "invalid request"
-	APIEmptyRequest   = "empty request"
-	APINotAuthorized  = "only authorized users can access this resource"
-	APIForbidden      = "forbidden"
+	APIUnknownErr           = "unknown error"
+	APIInternalErr          = "internal error"
+	APIInvalidRequest       = "invalid request"
+	APIEmptyRequest         = "empty request"
+	APINotAuthorized        = "only authorized users can access this resource"
+	APIForbidden            = "forbidden"
+	APIUnsupportedMediaType = "unsupported content type"
 )
 
 // APIEmptyParameter returns pName with "empty parameter: " prefix.
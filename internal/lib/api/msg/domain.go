@@ -1,8 +1,34 @@
 package msg
 
 const (
-	BannerNotFound      = "banner was not found"
-	BannerAlreadyExists = "banner with such feature and tag already exists"
-	BannerNotUnique     = "there are multiple banners with such feature and tag"
-	BannerNotActive     = "banner is not active"
+	BannerNotFound         = "banner was not found"
+	BannerNotActive        = "banner is not active"
+	BannerUnknownReference = "banner references unknown feature or tags"
+
+	BannerBulkEmpty    = "no banners provided"
+	BannerBulkTooLarge = "too many banners in a single request"
+	BannerBulkRejected = "some banners can't be saved, so none of them were saved"
+
+	BannerVersionNotFound = "banner version was not found"
+	BannerVariantNotFound = "banner variant was not found"
+
+	JobNotFound = "job was not found"
+
+	FeatureNotFound      = "feature was not found"
+	FeatureAlreadyExists = "feature with such id already exists"
+	FeatureInUse         = "feature is used by banners"
+
+	TagNotFound      = "tag was not found"
+	TagAlreadyExists = "tag with such id already exists"
+	TagInUse         = "tag is used by banners"
+
+	UserNotFound           = "user was not found"
+	UserAlreadyExists      = "user with such login already exists"
+	UserInvalidCredentials = "invalid login or password"
+	InvalidRefreshToken    = "invalid refresh token"
+	SessionNotFound        = "session was not found"
+	ForeignRefreshToken    = "refresh token belongs to another user"
+
+	APIKeyNotFound = "api key was not found"
+	APIKeyInvalid  = "invalid api key"
 )
@@ -4,6 +4,8 @@ import (
 	"banners-management/internal/lib/api/jsn"
 	"banners-management/internal/lib/api/msg"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // ParseInt64 parses string s into an *int64 num.
@@ -22,6 +24,27 @@ func ParseInt(s, pName string, num *int) error {
 	return parse(s, pName, num, strconv.Atoi)
 }
 
+// ParseInt64s parses comma-separated string s into *[]int64 nums, appending the numbers to it.
+// pName is the name of the parameter that is being parsed.
+// If something is wrong, its name appears in the parsing error message.
+func ParseInt64s(s, pName string, nums *[]int64) error {
+	if nums == nil {
+		panic("internal.lib.parse: provided nums value is nil")
+	}
+	if len(s) == 0 {
+		return jsn.DecodingError(msg.APIEmptyParameter(pName))
+	}
+	for _, v := range strings.Split(s, ",") {
+		var num int64
+		if err := ParseInt64(strings.TrimSpace(v), pName, &num); err != nil {
+			return jsn.DecodingError(msg.APIUnacceptableFormat(pName))
+		}
+		*nums = append(*nums, num)
+	}
+
+	return nil
+}
+
 // ParseBool parses string s into a *bool b.
 // pName is the name of the parameter that is being parsed.
 // If something is wrong, its name appears in the parsing error message.
@@ -29,6 +52,24 @@ func ParseBool(s, pName string, b *bool) error {
 	return parse(s, pName, b, strconv.ParseBool)
 }
 
+// ParseTime parses RFC 3339 string s into a *time.Time t.
+// pName is the name of the parameter that is being parsed.
+// If something is wrong, its name appears in the parsing error message.
+func ParseTime(s, pName string, t *time.Time) error {
+	return parse(s, pName, t, func(s string) (time.Time, error) {
+		return time.Parse(time.RFC3339, s)
+	})
+}
+
+// ParseDate parses YYYY-MM-DD string s into a *time.Time t at the start of the day in UTC.
+// pName is the name of the parameter that is being parsed.
+// If something is wrong, its name appears in the parsing error message.
+func ParseDate(s, pName string, t *time.Time) error {
+	return parse(s, pName, t, func(s string) (time.Time, error) {
+		return time.Parse(time.DateOnly, s)
+	})
+}
+
 // parse parses string s into a *T val.
 // pName is the name of the parameter that is being parsed.
 // If something is wrong, its name appears in the parsing error message.
@@ -3,16 +3,26 @@ package api
 import (
 	"context"
 	"net/http"
+
+	"banners-management/internal/model/entity"
 )
 
 const (
 	RequestIDKey = "request-id"
 	RoleKey      = "role"
+	TokenIDKey   = "token-id"
+	ScopesKey    = "scopes"
+	SubjectKey   = "subject"
 )
 
 // RequestID returns request id, associated with the given request.
 func RequestID(r *http.Request) string {
-	return ctxValue(r.Context(), RequestIDKey)
+	return RequestIDFromContext(r.Context())
+}
+
+// RequestIDFromContext returns request id, associated with the request the context belongs to.
+func RequestIDFromContext(ctx context.Context) string {
+	return ctxValue(ctx, RequestIDKey)
 }
 
 // SetRequestID return a request with the given request id.
@@ -34,6 +44,59 @@ func SetUserRole(r *http.Request, role string) *http.Request {
 	return r.WithContext(ctx)
 }
 
+// RoleFromContext returns user role, associated with the request the context belongs to.
+func RoleFromContext(ctx context.Context) string {
+	return ctxValue(ctx, RoleKey)
+}
+
+// UserSubject returns the identity of the user, making request, e.g. the login or the API key.
+// It is empty, if the token has no subject.
+func UserSubject(r *http.Request) string {
+	return SubjectFromContext(r.Context())
+}
+
+// SubjectFromContext returns the identity of the user, making the request the context belongs to.
+func SubjectFromContext(ctx context.Context) string {
+	return ctxValue(ctx, SubjectKey)
+}
+
+// SetUserSubject return a request with the given user identity.
+// User identity can be retrieved with UserSubject function.
+func SetUserSubject(r *http.Request, subject string) *http.Request {
+	ctx := context.WithValue(r.Context(), SubjectKey, subject)
+	return r.WithContext(ctx)
+}
+
+// UserScopes returns the scopes of the user, making request.
+func UserScopes(r *http.Request) []string {
+	scopes, _ := r.Context().Value(ScopesKey).([]string)
+	return scopes
+}
+
+// SetUserScopes return a request with the given user scopes.
+// User scopes can be retrieved with UserScopes function.
+func SetUserScopes(r *http.Request, scopes []string) *http.Request {
+	ctx := context.WithValue(r.Context(), ScopesKey, scopes)
+	return r.WithContext(ctx)
+}
+
+// UserPrincipal returns the caller of the authorized request with its role and scopes.
+func UserPrincipal(r *http.Request) entity.Principal {
+	return entity.Principal{Role: UserRole(r), Scopes: UserScopes(r)}
+}
+
+// TokenID returns the ID of the access token, the request is authorized with.
+func TokenID(r *http.Request) string {
+	return ctxValue(r.Context(), TokenIDKey)
+}
+
+// SetTokenID return a request with the given access token ID.
+// Token ID can be retrieved with TokenID function.
+func SetTokenID(r *http.Request, tokenID string) *http.Request {
+	ctx := context.WithValue(r.Context(), TokenIDKey, tokenID)
+	return r.WithContext(ctx)
+}
+
 // ctxValue returns a value from the context by the given key.
 func ctxValue(ctx context.Context, key string) string {
 	if value := ctx.Value(key); value != nil {
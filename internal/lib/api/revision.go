@@ -0,0 +1,33 @@
+package api
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	CacheHeader = "X-Cache"
+	AgeHeader   = "Age"
+
+	CacheHit  = "HIT"
+	CacheMiss = "MISS"
+)
+
+// SetRevisionHeaders sets the response headers that describe how fresh the returned data is.
+// X-Cache header is set to HIT if the data was served from cache, otherwise to MISS.
+// Age header is set to the number of seconds passed since the data was read from the persistent storage.
+func SetRevisionHeaders(w http.ResponseWriter, cached bool, fetchedAt time.Time) {
+	if cached {
+		w.Header().Set(CacheHeader, CacheHit)
+	} else {
+		w.Header().Set(CacheHeader, CacheMiss)
+	}
+
+	if fetchedAt.IsZero() {
+		return
+	}
+	age := math.Max(0, time.Since(fetchedAt).Seconds())
+	w.Header().Set(AgeHeader, strconv.Itoa(int(age)))
+}
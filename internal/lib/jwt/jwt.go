@@ -1,92 +1,208 @@
 package jwt
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
 const (
-	roleKey = "role"
-	expKey  = "exp"
+	roleKey   = "role"
+	expKey    = "exp"
+	jtiKey    = "jti"
+	kidKey    = "kid"
+	scopesKey = "scopes"
+	subKey    = "sub"
+
+	tokenIDSize = 16
 )
 
 var (
-	jwtAlg       = jwt.SigningMethodHS256
-	jwtValidAlgs = []string{jwtAlg.Name}
+	secretAlg = jwt.SigningMethodHS256
 
 	ErrInvalidToken = errors.New("invalid token")
 	ErrTokenExpired = errors.New("token expired")
 )
 
+// Claims are the claims of a valid JWT token.
+type Claims struct {
+	// ID is the unique identifier of the token (jti claim). It is used to revoke the token.
+	ID string
+	// Subject identifies the owner of the token, e.g. the login of the user. It may be empty.
+	Subject string
+	Role    string
+	// Scopes narrow down the permissions of the role, e.g. "feature:42:write".
+	Scopes    []string
+	ExpiresAt time.Time
+}
+
 // Manager is a JWT token manager.
+// The tokens are signed either with the symmetric secret (HS256) or with the asymmetric signing key,
+// and are verified with the secret or with any of the keys by the kid header of the token.
 type Manager struct {
-	secretKey []byte
-	expire    time.Duration
+	secretKey  []byte
+	keys       []*Key
+	keysByID   map[string]*Key
+	signingKey *Key
+	validAlgs  []string
+	expire     time.Duration
 }
 
-// NewManager creates a new JWT token manager.
+// NewManager creates a new JWT token manager, that signs and verifies the tokens with the secret key.
 func NewManager(secretKey string, expire time.Duration) *Manager {
 	return &Manager{
 		secretKey: []byte(secretKey),
+		validAlgs: []string{secretAlg.Alg()},
 		expire:    expire,
 	}
 }
 
-// GenerateToken generates a new JWT token with the given role.
-func (m *Manager) GenerateToken(role string) (string, error) {
-	token := jwt.NewWithClaims(jwtAlg,
-		jwt.MapClaims{
-			roleKey: role,
-			expKey:  time.Now().Add(m.expire).Unix(),
-		})
+// NewKeyManager creates a new JWT token manager, that signs the tokens with the key by signingKeyID,
+// and verifies them with any of the keys, so the tokens signed with the old keys stay valid during the rotation.
+// If signingKeyID is empty, the tokens are signed with the secret key.
+// If the secret key is empty, the tokens without kid header are not accepted.
+func NewKeyManager(secretKey string, keys []*Key, signingKeyID string, expire time.Duration) (*Manager, error) {
+	m := &Manager{
+		keys:     keys,
+		keysByID: make(map[string]*Key, len(keys)),
+		expire:   expire,
+	}
+	if secretKey != "" {
+		m.secretKey = []byte(secretKey)
+		m.validAlgs = append(m.validAlgs, secretAlg.Alg())
+	}
 
-	tokenString, err := token.SignedString(m.secretKey)
-	if err != nil {
-		return "", err
+	for _, k := range keys {
+		if k.signingMethod() == nil {
+			return nil, fmt.Errorf("key %s: %w: %s", k.ID, ErrUnsupportedAlg, k.Alg)
+		}
+		if !k.validPublic() {
+			return nil, fmt.Errorf("key %s: public key doesn't match %s algorithm", k.ID, k.Alg)
+		}
+		if _, ok := m.keysByID[k.ID]; ok || k.ID == "" {
+			return nil, fmt.Errorf("key %s: key ID must be unique and not empty", k.ID)
+		}
+		m.keysByID[k.ID] = k
+		m.validAlgs = append(m.validAlgs, k.Alg)
+	}
+
+	switch {
+	case signingKeyID != "":
+		k, ok := m.keysByID[signingKeyID]
+		if !ok || k.Private == nil {
+			return nil, fmt.Errorf("key %s: %w", signingKeyID, ErrKeyNotFound)
+		}
+		m.signingKey = k
+	case m.secretKey == nil:
+		return nil, ErrKeyNotFound
 	}
 
-	return tokenString, nil
+	return m, nil
 }
 
-// VerifyToken verifies the given JWT token. It checks the token signature and expiration time.
-func (m *Manager) VerifyToken(tokenString string) error {
-	claims, err := m.getClaims(tokenString)
+// Expire returns the lifetime of the tokens, generated by the manager.
+func (m *Manager) Expire() time.Duration {
+	return m.expire
+}
+
+// GenerateToken generates a new JWT token with the given role and scopes.
+func (m *Manager) GenerateToken(role string, scopes ...string) (string, error) {
+	token, _, err := m.IssueToken("", role, scopes)
+	return token, err
+}
+
+// IssueToken generates a new JWT token with the given subject, role, scopes and a unique ID.
+// If subject is empty, the token has no sub claim. It returns the token along with its claims.
+func (m *Manager) IssueToken(subject, role string, scopes []string) (string, *Claims, error) {
+	id, err := newTokenID()
 	if err != nil {
-		return err
+		return "", nil, err
+	}
+
+	claims := &Claims{
+		ID:        id,
+		Subject:   subject,
+		Role:      role,
+		Scopes:    scopes,
+		ExpiresAt: time.Now().Add(m.expire),
+	}
+	mapClaims := jwt.MapClaims{
+		jtiKey:  claims.ID,
+		roleKey: claims.Role,
+		expKey:  claims.ExpiresAt.Unix(),
+	}
+	if len(scopes) > 0 {
+		mapClaims[scopesKey] = scopes
+	}
+	if subject != "" {
+		mapClaims[subKey] = subject
 	}
 
-	if err = m.checkExpire(claims); err != nil {
-		return err
+	var tokenString string
+	if m.signingKey != nil {
+		token := jwt.NewWithClaims(m.signingKey.signingMethod(), mapClaims)
+		token.Header[kidKey] = m.signingKey.ID
+		tokenString, err = token.SignedString(m.signingKey.Private)
+	} else {
+		tokenString, err = jwt.NewWithClaims(secretAlg, mapClaims).SignedString(m.secretKey)
+	}
+	if err != nil {
+		return "", nil, err
 	}
 
-	return nil
+	return tokenString, claims, nil
 }
 
-// GetRole extracts the role from the given JWT token. It returns a non-nil error if the token is invalid or expired.
-func (m *Manager) GetRole(tokenString string) (string, error) {
+// ParseToken verifies the given JWT token and extracts its claims.
+// It checks the token signature and expiration time. It returns a non-nil error if the token is invalid or expired.
+// Tokens without ID are accepted, so the ones issued before IDs were introduced stay valid until they expire.
+func (m *Manager) ParseToken(tokenString string) (*Claims, error) {
 	claims, err := m.getClaims(tokenString)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	if err = m.checkExpire(claims); err != nil {
-		return "", err
+	exp, err := m.checkExpire(claims)
+	if err != nil {
+		return nil, err
 	}
 
 	role, ok := claims[roleKey].(string)
 	if !ok {
-		return "", ErrInvalidToken
+		return nil, ErrInvalidToken
 	}
+	id, _ := claims[jtiKey].(string)
+	sub, _ := claims[subKey].(string)
 
-	return role, nil
+	return &Claims{ID: id, Subject: sub, Role: role, Scopes: StringList(claims[scopesKey]), ExpiresAt: exp}, nil
+}
+
+// StringList returns the string items of the claim, that is either a single string or a list.
+// Items of the other types are skipped.
+func StringList(claim interface{}) []string {
+	switch c := claim.(type) {
+	case string:
+		return []string{c}
+	case []interface{}:
+		res := make([]string, 0, len(c))
+		for _, item := range c {
+			if s, ok := item.(string); ok {
+				res = append(res, s)
+			}
+		}
+		return res
+	default:
+		return nil
+	}
 }
 
 // getClaims parses the given JWT token and returns the claims. It returns an error if the token is invalid.
 func (m *Manager) getClaims(tokenString string) (jwt.MapClaims, error) {
-	parserFunc := func(token *jwt.Token) (interface{}, error) { return m.secretKey, nil }
-	token, err := jwt.Parse(tokenString, parserFunc, jwt.WithValidMethods(jwtValidAlgs))
+	token, err := jwt.Parse(tokenString, m.verificationKey, jwt.WithValidMethods(m.validAlgs))
 	if err != nil {
 		return nil, err
 	}
@@ -103,16 +219,46 @@ func (m *Manager) getClaims(tokenString string) (jwt.MapClaims, error) {
 	return claims, nil
 }
 
-// checkExpire checks if the given JWT token is expired. It returns an error if the token is expired.
-func (m *Manager) checkExpire(claims jwt.MapClaims) error {
+// verificationKey returns the key to verify the token with. The key is chosen by the kid header of the token,
+// and the token must be signed with the algorithm of the key. Tokens without kid are verified with the secret key.
+func (m *Manager) verificationKey(token *jwt.Token) (interface{}, error) {
+	kid, hasKid := token.Header[kidKey].(string)
+	if !hasKid {
+		if m.secretKey == nil || token.Method.Alg() != secretAlg.Alg() {
+			return nil, ErrInvalidToken
+		}
+		return m.secretKey, nil
+	}
+
+	k, ok := m.keysByID[kid]
+	if !ok || token.Method.Alg() != k.Alg {
+		return nil, ErrInvalidToken
+	}
+
+	return k.Public, nil
+}
+
+// checkExpire checks if the given JWT token is expired. It returns an error if the token is expired,
+// otherwise the expiration time of the token.
+func (m *Manager) checkExpire(claims jwt.MapClaims) (time.Time, error) {
 	exp, err := claims.GetExpirationTime()
 	if err != nil || exp == nil {
-		return ErrInvalidToken
+		return time.Time{}, ErrInvalidToken
 	}
 
 	if time.Now().After(exp.UTC()) {
-		return ErrTokenExpired
+		return time.Time{}, ErrTokenExpired
+	}
+
+	return exp.Time, nil
+}
+
+// newTokenID returns a new random token ID.
+func newTokenID() (string, error) {
+	b := make([]byte, tokenIDSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
 	}
 
-	return nil
+	return hex.EncodeToString(b), nil
 }
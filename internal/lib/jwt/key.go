@@ -0,0 +1,96 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// AlgRS256 is the RSA PKCS #1 v1.5 signature with SHA-256 algorithm.
+	AlgRS256 = "RS256"
+	// AlgEdDSA is the Ed25519 signature algorithm.
+	AlgEdDSA = "EdDSA"
+)
+
+var (
+	ErrUnsupportedAlg = errors.New("unsupported signing algorithm")
+	ErrKeyNotFound    = errors.New("signing key not found")
+)
+
+// Key is an asymmetric key, that is identified in the tokens by its ID (kid header).
+// Private is nil for the keys that are only used to verify the tokens, e.g. the retired keys during the rotation.
+type Key struct {
+	ID      string
+	Alg     string
+	Private crypto.Signer
+	Public  crypto.PublicKey
+}
+
+// ParseKey parses the PEM encoded key of the alg algorithm.
+// At least one of privatePEM and publicPEM must be provided. If publicPEM is nil, the public key is
+// derived from the private one.
+func ParseKey(id, alg string, privatePEM, publicPEM []byte) (*Key, error) {
+	if privatePEM == nil && publicPEM == nil {
+		return nil, fmt.Errorf("key %s: neither private nor public key provided", id)
+	}
+
+	k := &Key{ID: id, Alg: alg}
+	var err error
+	switch alg {
+	case AlgRS256:
+		if privatePEM != nil {
+			k.Private, err = jwt.ParseRSAPrivateKeyFromPEM(privatePEM)
+		}
+		if err == nil && publicPEM != nil {
+			k.Public, err = jwt.ParseRSAPublicKeyFromPEM(publicPEM)
+		}
+	case AlgEdDSA:
+		if privatePEM != nil {
+			var pk crypto.PrivateKey
+			pk, err = jwt.ParseEdPrivateKeyFromPEM(privatePEM)
+			k.Private, _ = pk.(crypto.Signer)
+		}
+		if err == nil && publicPEM != nil {
+			k.Public, err = jwt.ParseEdPublicKeyFromPEM(publicPEM)
+		}
+	default:
+		return nil, fmt.Errorf("key %s: %w: %s", id, ErrUnsupportedAlg, alg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("key %s: %w", id, err)
+	}
+	if k.Public == nil {
+		k.Public = k.Private.Public()
+	}
+
+	return k, nil
+}
+
+// signingMethod returns the jwt.SigningMethod of the key algorithm.
+func (k *Key) signingMethod() jwt.SigningMethod {
+	switch k.Alg {
+	case AlgRS256:
+		return jwt.SigningMethodRS256
+	case AlgEdDSA:
+		return jwt.SigningMethodEdDSA
+	default:
+		return nil
+	}
+}
+
+// validPublic reports whether the public key matches the key algorithm.
+func (k *Key) validPublic() bool {
+	switch k.Public.(type) {
+	case *rsa.PublicKey:
+		return k.Alg == AlgRS256
+	case ed25519.PublicKey:
+		return k.Alg == AlgEdDSA
+	default:
+		return false
+	}
+}
@@ -0,0 +1,39 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+)
+
+// Verifier verifies a token and extracts its claims.
+// It returns a non-nil error if the token is invalid or expired.
+type Verifier interface {
+	Verify(ctx context.Context, token string) (*Claims, error)
+}
+
+// Verify verifies the token issued by the manager. It implements Verifier.
+func (m *Manager) Verify(_ context.Context, token string) (*Claims, error) {
+	return m.ParseToken(token)
+}
+
+// ChainVerifier is a Verifier, that accepts a token if any of its verifiers accepts it.
+// The verifiers are tried in order.
+type ChainVerifier []Verifier
+
+// Verify returns the claims from the first verifier, that accepted the token.
+// If no verifier accepted it, all the errors are returned.
+func (cv ChainVerifier) Verify(ctx context.Context, token string) (*Claims, error) {
+	errs := make([]error, 0, len(cv))
+	for _, v := range cv {
+		claims, err := v.Verify(ctx, token)
+		if err == nil {
+			return claims, nil
+		}
+		errs = append(errs, err)
+	}
+	if len(errs) == 0 {
+		return nil, ErrInvalidToken
+	}
+
+	return nil, errors.Join(errs...)
+}
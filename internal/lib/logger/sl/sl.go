@@ -11,3 +11,12 @@ func Err(err error) slog.Attr {
 		Value: slog.StringValue(err.Error()),
 	}
 }
+
+// OptInt64 returns slog.Attr with the provided key and value *v, or an empty string value if v is nil.
+func OptInt64(key string, v *int64) slog.Attr {
+	if v == nil {
+		return slog.String(key, "")
+	}
+
+	return slog.Int64(key, *v)
+}
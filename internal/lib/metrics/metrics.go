@@ -0,0 +1,69 @@
+// Package metrics contains the prometheus metrics of the app.
+// The metrics are registered in the default prometheus registry, that is served by promhttp.Handler.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "banners"
+
+// The kinds of the cached data.
+const (
+	CacheBanner = "banner"
+	CacheList   = "list"
+)
+
+// The results of the cache lookups.
+const (
+	// CacheHit means the data was found in the cache.
+	CacheHit = "hit"
+	// CacheNegativeHit means the cache remembers, that the data doesn't exist.
+	CacheNegativeHit = "negative_hit"
+	// CacheMiss means the data was read from the storage.
+	CacheMiss = "miss"
+)
+
+// The events of the deferred delete jobs.
+const (
+	JobScheduled    = "scheduled"
+	JobSucceeded    = "succeeded"
+	JobRetried      = "retried"
+	JobDeadLettered = "dead_lettered"
+)
+
+var (
+	// HTTPRequests counts the handled requests by method, route pattern and response status.
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Number of handled HTTP requests.",
+	}, []string{"method", "route", "status"})
+
+	// HTTPRequestDuration observes the durations of the handled requests by method, route pattern and response status.
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Duration of handled HTTP requests.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// CacheLookups counts the lookups of the cached data by the kind of the data and the result.
+	CacheLookups = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "cache",
+		Name:      "lookups_total",
+		Help:      "Number of banner cache lookups.",
+	}, []string{"cache", "result"})
+
+	// DeleteJobs counts the events of the deferred delete jobs.
+	DeleteJobs = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "jobs",
+		Name:      "delete_events_total",
+		Help:      "Number of deferred delete job events.",
+	}, []string{"event"})
+)
@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+)
+
+// poolCollector is a prometheus.Collector, that reads the stats of a connection pool on every scrape.
+type poolCollector struct {
+	descs   []*prometheus.Desc
+	collect func(ch chan<- prometheus.Metric, descs []*prometheus.Desc)
+}
+
+// Describe implements prometheus.Collector.
+func (pc *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range pc.descs {
+		ch <- d
+	}
+}
+
+// Collect implements prometheus.Collector.
+func (pc *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	pc.collect(ch, pc.descs)
+}
+
+// poolDesc returns a new description of the pool metric of the subsystem.
+func poolDesc(subsystem, name, help string) *prometheus.Desc {
+	return prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, name), help, nil, nil)
+}
+
+// NewPgxPoolCollector returns a new prometheus.Collector of the postgres connection pool stats.
+func NewPgxPoolCollector(stat func() *pgxpool.Stat) prometheus.Collector {
+	const sub = "pgxpool"
+	descs := []*prometheus.Desc{
+		poolDesc(sub, "acquired_conns", "Number of currently acquired connections."),
+		poolDesc(sub, "idle_conns", "Number of currently idle connections."),
+		poolDesc(sub, "total_conns", "Total number of connections."),
+		poolDesc(sub, "max_conns", "Maximum size of the pool."),
+		poolDesc(sub, "acquires_total", "Number of successful acquires."),
+		poolDesc(sub, "acquire_duration_seconds_total", "Total duration of successful acquires."),
+		poolDesc(sub, "empty_acquires_total", "Number of acquires, that waited for a connection."),
+		poolDesc(sub, "canceled_acquires_total", "Number of acquires canceled by a context."),
+		poolDesc(sub, "new_conns_total", "Number of new connections opened."),
+	}
+
+	return &poolCollector{descs, func(ch chan<- prometheus.Metric, d []*prometheus.Desc) {
+		s := stat()
+		ch <- prometheus.MustNewConstMetric(d[0], prometheus.GaugeValue, float64(s.AcquiredConns()))
+		ch <- prometheus.MustNewConstMetric(d[1], prometheus.GaugeValue, float64(s.IdleConns()))
+		ch <- prometheus.MustNewConstMetric(d[2], prometheus.GaugeValue, float64(s.TotalConns()))
+		ch <- prometheus.MustNewConstMetric(d[3], prometheus.GaugeValue, float64(s.MaxConns()))
+		ch <- prometheus.MustNewConstMetric(d[4], prometheus.CounterValue, float64(s.AcquireCount()))
+		ch <- prometheus.MustNewConstMetric(d[5], prometheus.CounterValue, s.AcquireDuration().Seconds())
+		ch <- prometheus.MustNewConstMetric(d[6], prometheus.CounterValue, float64(s.EmptyAcquireCount()))
+		ch <- prometheus.MustNewConstMetric(d[7], prometheus.CounterValue, float64(s.CanceledAcquireCount()))
+		ch <- prometheus.MustNewConstMetric(d[8], prometheus.CounterValue, float64(s.NewConnsCount()))
+	}}
+}
+
+// NewRedisPoolCollector returns a new prometheus.Collector of the redis client connection pool stats.
+func NewRedisPoolCollector(stats func() *redis.PoolStats) prometheus.Collector {
+	const sub = "redis_pool"
+	descs := []*prometheus.Desc{
+		poolDesc(sub, "hits_total", "Number of times a free connection was found in the pool."),
+		poolDesc(sub, "misses_total", "Number of times a free connection was not found in the pool."),
+		poolDesc(sub, "timeouts_total", "Number of times a wait for a connection timed out."),
+		poolDesc(sub, "total_conns", "Total number of connections."),
+		poolDesc(sub, "idle_conns", "Number of idle connections."),
+		poolDesc(sub, "stale_conns_total", "Number of stale connections removed from the pool."),
+	}
+
+	return &poolCollector{descs, func(ch chan<- prometheus.Metric, d []*prometheus.Desc) {
+		s := stats()
+		ch <- prometheus.MustNewConstMetric(d[0], prometheus.CounterValue, float64(s.Hits))
+		ch <- prometheus.MustNewConstMetric(d[1], prometheus.CounterValue, float64(s.Misses))
+		ch <- prometheus.MustNewConstMetric(d[2], prometheus.CounterValue, float64(s.Timeouts))
+		ch <- prometheus.MustNewConstMetric(d[3], prometheus.GaugeValue, float64(s.TotalConns))
+		ch <- prometheus.MustNewConstMetric(d[4], prometheus.GaugeValue, float64(s.IdleConns))
+		ch <- prometheus.MustNewConstMetric(d[5], prometheus.CounterValue, float64(s.StaleConns))
+	}}
+}
@@ -0,0 +1,273 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+	"golang.org/x/sync/singleflight"
+
+	"banners-management/internal/lib/jwt"
+)
+
+const (
+	discoveryPath = "/.well-known/openid-configuration"
+
+	// minRefreshInterval limits how often the keys are refetched because of an unknown kid,
+	// and how often a failed fetch is retried, so neither the tokens with random kids
+	// nor an unavailable issuer can make the verifier flood the issuer.
+	minRefreshInterval = 10 * time.Second
+	// DefaultRoleClaim is the claim the role is read from, if Config.RoleClaim is empty.
+	DefaultRoleClaim = "roles"
+)
+
+var (
+	ErrIssuerMismatch = errors.New("issuer mismatch")
+	ErrNoRole         = errors.New("no role mapped from token claims")
+
+	validAlgs = []string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512", "EdDSA"}
+)
+
+// RoleMapping maps the Value of the role claim to the Role and the Scopes of the service.
+type RoleMapping struct {
+	Value  string
+	Role   string
+	Scopes []string
+}
+
+// Config contains the settings of an OIDC identity provider.
+type Config struct {
+	// Issuer is the issuer identifier. The provider metadata is discovered from it.
+	Issuer string
+	// Audience is the value the aud claim of the tokens must contain. It must not be empty,
+	// since the aud claim is not checked then.
+	Audience string
+	// RoleClaim is the claim of the tokens, that contains a single value or a list of values.
+	RoleClaim string
+	// RoleMapping is an ordered list of the role rules. The role is taken from the first rule
+	// matching any claim value, and the scopes of all the matching rules are granted.
+	RoleMapping []RoleMapping
+	// JWKSCacheTTL is the time the public keys of the issuer are cached for.
+	JWKSCacheTTL time.Duration
+}
+
+// Verifier is a jwt.Verifier for the tokens issued by an OIDC identity provider.
+// The provider metadata is discovered on the first verification, and the public keys are cached for
+// Config.JWKSCacheTTL. The keys are also refetched, if a token is signed with an unknown key.
+// The keys are replaced as a whole, so the verifications never wait for the issuer requests,
+// unless the keys are missing or stale, and the concurrent refetches are collapsed into one.
+type Verifier struct {
+	cfg    Config
+	client *http.Client
+
+	keys  atomic.Pointer[keySet]
+	group singleflight.Group
+}
+
+// keySet is an immutable snapshot of the issuer keys.
+// If the last refetch failed, failedAt and err are set, and the keys are the ones fetched before.
+type keySet struct {
+	jwksURI   string
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+	failedAt  time.Time
+	err       error
+}
+
+// NewVerifier returns a new Verifier instance, that makes requests to the identity provider with client.
+func NewVerifier(cfg Config, client *http.Client) *Verifier {
+	cfg.Issuer = strings.TrimSuffix(cfg.Issuer, "/")
+	if cfg.RoleClaim == "" {
+		cfg.RoleClaim = DefaultRoleClaim
+	}
+
+	v := &Verifier{
+		cfg:    cfg,
+		client: client,
+	}
+	v.keys.Store(new(keySet))
+
+	return v
+}
+
+// Verify verifies the token signature, issuer, audience and expiration time,
+// and maps its role claim to the role of the service.
+func (v *Verifier) Verify(ctx context.Context, token string) (*jwt.Claims, error) {
+	const comp = "lib.oidc.Verify"
+	keyFunc := func(t *gojwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return v.key(ctx, kid)
+	}
+	parsed, err := gojwt.Parse(token, keyFunc,
+		gojwt.WithValidMethods(validAlgs),
+		gojwt.WithIssuer(v.cfg.Issuer),
+		gojwt.WithAudience(v.cfg.Audience),
+		gojwt.WithExpirationRequired(),
+	)
+	if errors.Is(err, gojwt.ErrTokenExpired) {
+		return nil, fmt.Errorf("%s: %w", comp, jwt.ErrTokenExpired)
+	} else if err != nil {
+		return nil, fmt.Errorf("%s: %w: %w", comp, jwt.ErrInvalidToken, err)
+	}
+
+	claims, ok := parsed.Claims.(gojwt.MapClaims)
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", comp, jwt.ErrInvalidToken)
+	}
+	role, scopes, ok := v.role(claims)
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", comp, ErrNoRole)
+	}
+	exp, _ := claims.GetExpirationTime()
+	id, _ := claims["jti"].(string)
+	sub, _ := claims["sub"].(string)
+
+	return &jwt.Claims{ID: id, Subject: sub, Role: role, Scopes: scopes, ExpiresAt: exp.Time}, nil
+}
+
+// role returns the role and the scopes the role claim values are mapped to.
+func (v *Verifier) role(claims gojwt.MapClaims) (string, []string, bool) {
+	values := jwt.StringList(claims[v.cfg.RoleClaim])
+
+	var (
+		role   string
+		scopes []string
+	)
+	for _, m := range v.cfg.RoleMapping {
+		if !slices.Contains(values, m.Value) {
+			continue
+		}
+		if role == "" {
+			role = m.Role
+		}
+		scopes = append(scopes, m.Scopes...)
+	}
+
+	return role, scopes, role != ""
+}
+
+// key returns the public key by its ID, fetching the keys of the issuer if needed.
+// After a failed fetch the keys are not fetched again for minRefreshInterval, and the stale keys
+// are not used meanwhile.
+func (v *Verifier) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	ks := v.keys.Load()
+	k, ok := ks.keys[kid]
+	stale := time.Since(ks.fetchedAt) > v.cfg.JWKSCacheTTL
+	refetch := stale || (!ok && time.Since(ks.fetchedAt) > minRefreshInterval)
+	if refetch && time.Since(ks.failedAt) > minRefreshInterval {
+		var err error
+		if ks, err = v.refreshKeys(ctx, ks); err != nil {
+			return nil, err
+		}
+		k, ok = ks.keys[kid]
+	} else if stale {
+		// the last fetch has just failed
+		return nil, ks.err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", jwt.ErrKeyNotFound, kid)
+	}
+
+	return k, nil
+}
+
+// refreshKeys fetches the issuer keys to replace the old ones and returns the new ones.
+// The concurrent calls share a single fetch, and if the old keys were already replaced, they are not fetched again.
+// A failed fetch is recorded along with the old keys, so it's not retried too soon, see key.
+func (v *Verifier) refreshKeys(ctx context.Context, old *keySet) (*keySet, error) {
+	// the fetch is shared, so it must not be canceled along with the request, that started it,
+	// the client timeout limits it instead
+	ctx = context.WithoutCancel(ctx)
+	res, err, _ := v.group.Do("keys", func() (any, error) {
+		if cur := v.keys.Load(); cur != old {
+			return cur, cur.err
+		}
+		ks, err := v.fetchKeys(ctx, old.jwksURI)
+		if err != nil {
+			v.keys.Store(&keySet{
+				jwksURI:   old.jwksURI,
+				keys:      old.keys,
+				fetchedAt: old.fetchedAt,
+				failedAt:  time.Now(),
+				err:       err,
+			})
+			return nil, err
+		}
+		v.keys.Store(ks)
+		return ks, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.(*keySet), nil
+}
+
+// fetchKeys discovers the issuer metadata, if jwksURI wasn't discovered yet, and fetches the issuer keys.
+// Keys that can't be decoded are skipped.
+func (v *Verifier) fetchKeys(ctx context.Context, jwksURI string) (*keySet, error) {
+	if jwksURI == "" {
+		var meta struct {
+			Issuer  string `json:"issuer"`
+			JWKSURI string `json:"jwks_uri"`
+		}
+		if err := v.getJSON(ctx, v.cfg.Issuer+discoveryPath, &meta); err != nil {
+			return nil, err
+		}
+		if strings.TrimSuffix(meta.Issuer, "/") != v.cfg.Issuer {
+			return nil, fmt.Errorf("lib.oidc.fetchKeys: %w: %s", ErrIssuerMismatch, meta.Issuer)
+		}
+		jwksURI = meta.JWKSURI
+	}
+
+	var set struct {
+		Keys []jwt.JWK `json:"keys"`
+	}
+	if err := v.getJSON(ctx, jwksURI, &set); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		if pub, err := k.PublicKey(); err == nil {
+			keys[k.Kid] = pub
+		}
+	}
+
+	return &keySet{jwksURI: jwksURI, keys: keys, fetchedAt: time.Now()}, nil
+}
+
+// getJSON makes a GET request to the url and decodes the json response into dst.
+func (v *Verifier) getJSON(ctx context.Context, url string, dst any) error {
+	const comp = "lib.oidc.getJSON"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", comp, err)
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", comp, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: unexpected status %d from %s", comp, resp.StatusCode, url)
+	}
+	if err = json.NewDecoder(resp.Body).Decode(dst); err != nil {
+		return fmt.Errorf("%s: %w", comp, err)
+	}
+
+	return nil
+}
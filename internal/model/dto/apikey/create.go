@@ -0,0 +1,27 @@
+package apikey
+
+import (
+	"time"
+
+	"banners-management/internal/model/entity"
+)
+
+// CreateDTO is expected to be received as a create API key request.
+type CreateDTO struct {
+	Name string `json:"name" validate:"required"`
+	Role string `json:"role" validate:"required,oneof=user viewer editor admin"`
+	// Scopes are the feature scopes like "feature:42:write" or "feature:*:write".
+	Scopes []string `json:"scopes" validate:"dive,scope"`
+}
+
+// ToModel returns a new entity.APIKey constructed from CreateDTO with the provided key prefix and hash.
+func (d CreateDTO) ToModel(prefix, keyHash string) *entity.APIKey {
+	return &entity.APIKey{
+		Name:      d.Name,
+		Prefix:    prefix,
+		KeyHash:   keyHash,
+		Role:      d.Role,
+		Scopes:    d.Scopes,
+		CreatedAt: time.Now(),
+	}
+}
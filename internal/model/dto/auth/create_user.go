@@ -0,0 +1,28 @@
+package auth
+
+import (
+	"time"
+
+	"banners-management/internal/model/entity"
+)
+
+// CreateUserDTO contains information about a user that's being registered.
+// The password is limited by 72 bytes, because longer ones are truncated by bcrypt.
+type CreateUserDTO struct {
+	Login    string `validate:"required"`
+	Password string `validate:"required,min=8,max=72"`
+	Role     string `validate:"required,oneof=user viewer editor admin"`
+	// Scopes are the feature scopes like "feature:42:write" or "feature:*:write".
+	Scopes []string `validate:"dive,scope"`
+}
+
+// ToModel returns a new entity.User constructed from CreateUserDTO with the provided password hash.
+func (d CreateUserDTO) ToModel(passwordHash string) *entity.User {
+	return &entity.User{
+		Login:        d.Login,
+		PasswordHash: passwordHash,
+		Role:         d.Role,
+		Scopes:       d.Scopes,
+		CreatedAt:    time.Now(),
+	}
+}
@@ -0,0 +1,7 @@
+package auth
+
+// LoginDTO is expected to be received as a login request.
+type LoginDTO struct {
+	Login    string `json:"login" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
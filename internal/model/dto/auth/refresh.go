@@ -0,0 +1,6 @@
+package auth
+
+// RefreshDTO is expected to be received as a request to refresh the tokens or to log out.
+type RefreshDTO struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
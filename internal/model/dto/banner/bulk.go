@@ -0,0 +1,9 @@
+package banner
+
+// BulkRow is a single row of the bulk create request.
+// Row is the 1-based number of the row in the request. Err is set, if the row couldn't be decoded.
+type BulkRow struct {
+	Row int
+	DTO CreateDTO
+	Err error
+}
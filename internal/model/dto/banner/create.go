@@ -1,13 +1,21 @@
 package banner
 
-import "banners-management/internal/model/entity"
+import (
+	"time"
+
+	"banners-management/internal/model/entity"
+)
 
 // CreateDTO is expected to be received as a create banner request.
+// ActiveFrom and ActiveUntil are optional, Priority is 0 by default.
 type CreateDTO struct {
-	TagIDs    []int64       `json:"tag_ids" validate:"required,gt=0,dive"`
-	FeatureID int64         `json:"feature_id" validate:"required"`
-	Content   CreateContent `json:"content" validate:"required"`
-	IsActive  bool          `json:"is_active"`
+	TagIDs      []int64       `json:"tag_ids" validate:"required,gt=0,dive"`
+	FeatureID   int64         `json:"feature_id" validate:"required"`
+	Content     CreateContent `json:"content" validate:"required"`
+	IsActive    bool          `json:"is_active"`
+	Priority    int           `json:"priority"`
+	ActiveFrom  *time.Time    `json:"active_from,omitempty"`
+	ActiveUntil *time.Time    `json:"active_until,omitempty"`
 }
 
 // CreateContent contains information about banner that's being created.
@@ -19,7 +27,7 @@ type CreateContent struct {
 
 // ToModel returns a new entity.Banner constructed from CreateDTO.
 func (d CreateDTO) ToModel() *entity.Banner {
-	return entity.NewBanner(
+	b := entity.NewBanner(
 		d.Content.Title,
 		d.Content.Text,
 		d.Content.URL,
@@ -27,4 +35,9 @@ func (d CreateDTO) ToModel() *entity.Banner {
 		d.IsActive,
 		d.TagIDs,
 	)
+	b.Priority = d.Priority
+	b.ActiveFrom = d.ActiveFrom
+	b.ActiveUntil = d.ActiveUntil
+
+	return b
 }
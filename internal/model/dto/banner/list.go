@@ -0,0 +1,28 @@
+package banner
+
+import "time"
+
+// ListDTO is expected to be received as a list banners request. Nil and empty fields are ignored.
+// TagMatch tells, whether the banners must have any of TagIDs or all of them. It's "any" by default.
+// The time ranges include their lower bounds and exclude the upper ones.
+// Q is a full-text search query over the titles and the texts of the banners.
+// Sort is one of the sorting fields, optionally prefixed with "-" for the descending order.
+// Cursor is the opaque cursor of the page, returned along with the previous page.
+// It can't be combined with Offset.
+// Limit is at most 1000.
+type ListDTO struct {
+	FeatureID   *int64
+	TagIDs      []int64 `validate:"max=100"`
+	TagMatch    string  `validate:"omitempty,oneof=any all"`
+	IsActive    *bool
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	UpdatedFrom *time.Time
+	UpdatedTo   *time.Time
+	Q           string `validate:"max=256"`
+	Limit       *int   `validate:"omitempty,min=1,max=1000"`
+	Offset      *int   `validate:"omitempty,min=0"`
+	Sort        string `validate:"omitempty,oneof=id created_at updated_at title -id -created_at -updated_at -title"`
+	Cursor      string `validate:"excluded_with=Offset"`
+	WithTotal   bool
+}
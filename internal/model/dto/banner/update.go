@@ -1,14 +1,60 @@
 package banner
 
-import "banners-management/internal/model/entity"
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"banners-management/internal/model/entity"
+)
 
 // UpdateDTO is expected to be received as an update banner request.
 // Pointer parameters are optional.
+// Activation window bounds are updated only if they're present, and removed if they're null.
 type UpdateDTO struct {
-	TagIDs    *[]int64       `json:"tag_ids"`
-	FeatureID *int64         `json:"feature_id"`
-	Content   *UpdateContent `json:"content"`
-	IsActive  *bool          `json:"is_active"`
+	TagIDs      *[]int64       `json:"tag_ids"`
+	FeatureID   *int64         `json:"feature_id"`
+	Content     *UpdateContent `json:"content"`
+	IsActive    *bool          `json:"is_active"`
+	Priority    *int           `json:"priority"`
+	ActiveFrom  NullableTime   `json:"active_from"`
+	ActiveUntil NullableTime   `json:"active_until"`
+}
+
+// NullableTime is an optional time, that can be explicitly set to null.
+// Set is true if the field was present in the request, Time is nil if it was null.
+type NullableTime struct {
+	Set  bool
+	Time *time.Time
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It's called only if the field is present.
+func (nt *NullableTime) UnmarshalJSON(data []byte) error {
+	nt.Set = true
+	if string(data) == "null" {
+		nt.Time = nil
+		return nil
+	}
+
+	nt.Time = new(time.Time)
+	return json.Unmarshal(data, nt.Time)
+}
+
+// MarshalJSON implements json.Marshaler. Note that the unset time is marshaled as null too.
+func (nt NullableTime) MarshalJSON() ([]byte, error) {
+	return json.Marshal(nt.Time)
+}
+
+// toModel returns nil if the time is not set, and sql.NullTime otherwise.
+func (nt NullableTime) toModel() *sql.NullTime {
+	if !nt.Set {
+		return nil
+	}
+	if nt.Time == nil {
+		return &sql.NullTime{}
+	}
+
+	return &sql.NullTime{Time: *nt.Time, Valid: true}
 }
 
 // UpdateContent contains information about banner that's being updated.
@@ -38,6 +84,10 @@ func (d UpdateDTO) ToModel(id int64) *entity.UpdatableBanner {
 		URL:       url,
 		FeatureID: d.FeatureID,
 		IsActive:  d.IsActive,
+		Priority:  d.Priority,
 		TagIDs:    d.TagIDs,
+
+		ActiveFrom:  d.ActiveFrom.toModel(),
+		ActiveUntil: d.ActiveUntil.toModel(),
 	}
 }
@@ -0,0 +1,60 @@
+package banner
+
+import (
+	"time"
+
+	"banners-management/internal/model/entity"
+)
+
+// DefaultVariantWeight is the weight of the created variant, if it's not provided.
+const DefaultVariantWeight = 1
+
+// CreateVariantDTO is expected to be received as a create banner variant request.
+// Weight is DefaultVariantWeight by default.
+type CreateVariantDTO struct {
+	Content CreateContent `json:"content" validate:"required"`
+	Weight  *int          `json:"weight" validate:"omitempty,min=0,max=10000"`
+}
+
+// ToModel returns a new entity.BannerVariant of the banner by bannerID constructed from CreateVariantDTO.
+func (d CreateVariantDTO) ToModel(bannerID int64) *entity.BannerVariant {
+	weight := DefaultVariantWeight
+	if d.Weight != nil {
+		weight = *d.Weight
+	}
+
+	now := time.Now()
+	return &entity.BannerVariant{
+		BannerID:  bannerID,
+		Title:     d.Content.Title,
+		Text:      d.Content.Text,
+		URL:       d.Content.URL,
+		Weight:    weight,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// UpdateVariantDTO is expected to be received as an update banner variant request.
+// Pointer parameters are optional.
+type UpdateVariantDTO struct {
+	Content *UpdateContent `json:"content"`
+	Weight  *int           `json:"weight" validate:"omitempty,min=0,max=10000"`
+}
+
+// ToModel returns a new entity.UpdatableBannerVariant by id of the banner by bannerID
+// constructed from UpdateVariantDTO.
+func (d UpdateVariantDTO) ToModel(bannerID, id int64) *entity.UpdatableBannerVariant {
+	v := &entity.UpdatableBannerVariant{
+		ID:       id,
+		BannerID: bannerID,
+		Weight:   d.Weight,
+	}
+	if d.Content != nil {
+		v.Title = d.Content.Title
+		v.Text = d.Content.Text
+		v.URL = d.Content.URL
+	}
+
+	return v
+}
@@ -0,0 +1,19 @@
+package feature
+
+import "banners-management/internal/model/entity"
+
+// CreateDTO is expected to be received as a create feature request.
+type CreateDTO struct {
+	FeatureID   int64  `json:"feature_id" validate:"required,gt=0"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// ToModel returns a new entity.Feature constructed from CreateDTO.
+func (d CreateDTO) ToModel() *entity.Feature {
+	return &entity.Feature{
+		ID:          d.FeatureID,
+		Name:        d.Name,
+		Description: d.Description,
+	}
+}
@@ -0,0 +1,19 @@
+package feature
+
+import "banners-management/internal/model/entity"
+
+// UpdateDTO is expected to be received as an update feature request.
+// Pointer parameters are optional.
+type UpdateDTO struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+}
+
+// ToModel returns a new entity.UpdatableFeature constructed from UpdateDTO.
+func (d UpdateDTO) ToModel(id int64) *entity.UpdatableFeature {
+	return &entity.UpdatableFeature{
+		ID:          id,
+		Name:        d.Name,
+		Description: d.Description,
+	}
+}
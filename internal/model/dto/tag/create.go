@@ -0,0 +1,19 @@
+package tag
+
+import "banners-management/internal/model/entity"
+
+// CreateDTO is expected to be received as a create tag request.
+type CreateDTO struct {
+	TagID       int64  `json:"tag_id" validate:"required,gt=0"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// ToModel returns a new entity.Tag constructed from CreateDTO.
+func (d CreateDTO) ToModel() *entity.Tag {
+	return &entity.Tag{
+		ID:          d.TagID,
+		Name:        d.Name,
+		Description: d.Description,
+	}
+}
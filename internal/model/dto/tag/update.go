@@ -0,0 +1,19 @@
+package tag
+
+import "banners-management/internal/model/entity"
+
+// UpdateDTO is expected to be received as an update tag request.
+// Pointer parameters are optional.
+type UpdateDTO struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+}
+
+// ToModel returns a new entity.UpdatableTag constructed from UpdateDTO.
+func (d UpdateDTO) ToModel(id int64) *entity.UpdatableTag {
+	return &entity.UpdatableTag{
+		ID:          id,
+		Name:        d.Name,
+		Description: d.Description,
+	}
+}
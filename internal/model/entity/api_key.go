@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"strconv"
+	"time"
+)
+
+// APIKey is a long-lived key, that authorizes the requests of other services with its role and scopes.
+// Only the hash of the key is stored, and the Prefix is kept to tell the keys apart.
+type APIKey struct {
+	ID         int64
+	Name       string
+	Prefix     string
+	KeyHash    string
+	Role       string
+	Scopes     []string
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+}
+
+// Principal returns the principal, the requests authorized with the key are made by.
+func (k *APIKey) Principal() Principal {
+	return Principal{Role: k.Role, Scopes: k.Scopes}
+}
+
+// Subject returns the identity of the key owner, e.g. "api_key:42".
+func (k *APIKey) Subject() string {
+	return "api_key:" + strconv.FormatInt(k.ID, 10)
+}
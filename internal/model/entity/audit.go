@@ -0,0 +1,132 @@
+package entity
+
+import (
+	"reflect"
+	"slices"
+	"time"
+)
+
+// Audit actions, that are recorded for the banner mutations.
+const (
+	AuditBannerCreate             = "banner.create"
+	AuditBannerUpdate             = "banner.update"
+	AuditBannerDelete             = "banner.delete"
+	AuditBannerDeleteByFeatureTag = "banner.delete_by_feature_tag"
+	AuditBannerRestore            = "banner.restore"
+	AuditVariantCreate            = "banner.variant.create"
+	AuditVariantUpdate            = "banner.variant.update"
+	AuditVariantDelete            = "banner.variant.delete"
+)
+
+// AuditEvent is a record of a mutation, made by the actor.
+// BannerID is set for the mutations of a single banner, VariantID is set along with it for the mutations
+// of the banner variants, and FeatureID, TagID and JobID are set for the scheduled deletions
+// of the banners by feature and/or tag. JobID is also set along with BannerID for the banners
+// affected by the scheduled deletion, when it's executed.
+type AuditEvent struct {
+	ID int64
+	// Action is one of the Audit* constants.
+	Action string
+	// Actor is the subject of the token or the API key, the mutation is authorized with. It may be empty
+	// for the tokens without a subject.
+	Actor     string
+	ActorRole string
+	RequestID string
+	BannerID  *int64
+	VariantID *int64
+	FeatureID *int64
+	TagID     *int64
+	JobID     *string
+	// Diff holds the changed fields of the banner or the variant by their names.
+	Diff      map[string]AuditChange
+	CreatedAt time.Time
+}
+
+// AuditChange is a change of a single field. Before is nil for the created banners,
+// and After is nil for the deleted ones.
+type AuditChange struct {
+	Before any `json:"before,omitempty"`
+	After  any `json:"after,omitempty"`
+}
+
+// BannerDiff returns the fields of the banner, that differ between before and after.
+// Either of them may be nil, then all the fields of the other one are returned.
+func BannerDiff(before, after *Banner) map[string]AuditChange {
+	b, a := auditFields(before), auditFields(after)
+	diff := make(map[string]AuditChange)
+	for name := range bannerAuditFields {
+		if !reflect.DeepEqual(b[name], a[name]) {
+			diff[name] = AuditChange{Before: b[name], After: a[name]}
+		}
+	}
+
+	return diff
+}
+
+// bannerAuditFields are the audited fields of the banner by their names.
+var bannerAuditFields = map[string]func(b *Banner) any{
+	"title":      func(b *Banner) any { return b.Title },
+	"text":       func(b *Banner) any { return b.Text },
+	"url":        func(b *Banner) any { return b.URL },
+	"feature_id": func(b *Banner) any { return b.FeatureID },
+	"is_active":  func(b *Banner) any { return b.IsActive },
+	"priority":   func(b *Banner) any { return b.Priority },
+	"tag_ids": func(b *Banner) any {
+		// the order of the tags is not meaningful
+		ids := slices.Clone(b.TagIDs)
+		slices.Sort(ids)
+		return ids
+	},
+	"active_from":  func(b *Banner) any { return optTime(b.ActiveFrom) },
+	"active_until": func(b *Banner) any { return optTime(b.ActiveUntil) },
+}
+
+// auditFields returns the audited fields of the banner by their names, or nil if b is nil.
+func auditFields(b *Banner) map[string]any {
+	if b == nil {
+		return nil
+	}
+	fields := make(map[string]any, len(bannerAuditFields))
+	for name, get := range bannerAuditFields {
+		fields[name] = get(b)
+	}
+
+	return fields
+}
+
+// VariantDiff returns the fields of the banner variant, that differ between before and after.
+// Either of them may be nil, then all the fields of the other one are returned.
+func VariantDiff(before, after *BannerVariant) map[string]AuditChange {
+	diff := make(map[string]AuditChange)
+	for name, get := range variantAuditFields {
+		var b, a any
+		if before != nil {
+			b = get(before)
+		}
+		if after != nil {
+			a = get(after)
+		}
+		if b != a {
+			diff[name] = AuditChange{Before: b, After: a}
+		}
+	}
+
+	return diff
+}
+
+// variantAuditFields are the audited fields of the banner variant by their names.
+var variantAuditFields = map[string]func(v *BannerVariant) any{
+	"title":  func(v *BannerVariant) any { return v.Title },
+	"text":   func(v *BannerVariant) any { return v.Text },
+	"url":    func(v *BannerVariant) any { return v.URL },
+	"weight": func(v *BannerVariant) any { return v.Weight },
+}
+
+// optTime returns the UTC time t points to, or nil, so the same instants are equal.
+func optTime(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+
+	return t.UTC()
+}
@@ -1,18 +1,34 @@
 package entity
 
-import "time"
+import (
+	"database/sql"
+	"time"
+)
 
 // Banner is a banner domain entity.
+// ActiveFrom and ActiveUntil optionally limit the period, when the banner is shown to users.
+// Several banners may share a feature and a tag, then the one with the highest Priority is shown.
+// Variants are the alternative contents of the banner ordered by ID, they're only read along with
+// the banners shown to users.
+// NextResolutionChange is the nearest bound of the activation window of any banner sharing the feature and the tag,
+// that the banner was resolved by, after the banner was read, so another banner may be shown since then.
+// It's only read along with the banners shown to users, and is nil if no window changes.
 type Banner struct {
-	ID        int64
-	Title     string
-	Text      string
-	URL       string
-	FeatureID int64
-	IsActive  bool
-	TagIDs    []int64
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID          int64
+	Title       string
+	Text        string
+	URL         string
+	FeatureID   int64
+	IsActive    bool
+	Priority    int
+	TagIDs      []int64
+	ActiveFrom  *time.Time
+	ActiveUntil *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Variants    []BannerVariant
+
+	NextResolutionChange *time.Time
 }
 
 // NewBanner returns a new Banner instance.
@@ -37,16 +53,46 @@ func NewBanner(
 	}
 }
 
+// InWindow reports whether the moment t is within the activation window of the banner.
+// A nil bound means that the window is not limited from that side.
+func (b *Banner) InWindow(t time.Time) bool {
+	if b.ActiveFrom != nil && t.Before(*b.ActiveFrom) {
+		return false
+	}
+	if b.ActiveUntil != nil && !t.Before(*b.ActiveUntil) {
+		return false
+	}
+
+	return true
+}
+
+// NextWindowChange returns the nearest bound of the activation window after the moment t,
+// or false if the window doesn't change after t.
+func (b *Banner) NextWindowChange(t time.Time) (time.Time, bool) {
+	switch {
+	case b.ActiveFrom != nil && t.Before(*b.ActiveFrom):
+		return *b.ActiveFrom, true
+	case b.ActiveUntil != nil && t.Before(*b.ActiveUntil):
+		return *b.ActiveUntil, true
+	default:
+		return time.Time{}, false
+	}
+}
+
 // UpdatableBanner is a banner domain entity, that's being used to update a main Banner entity.
 // Pointer parameters indicate that they're optional, and are not considered during update.
+// Activation window bounds with Valid set to false are removed.
 type UpdatableBanner struct {
-	ID        int64
-	Title     *string
-	Text      *string
-	URL       *string
-	FeatureID *int64
-	IsActive  *bool
-	TagIDs    *[]int64
-	CreatedAt *time.Time
-	UpdatedAt *time.Time
+	ID          int64
+	Title       *string
+	Text        *string
+	URL         *string
+	FeatureID   *int64
+	IsActive    *bool
+	Priority    *int
+	TagIDs      *[]int64
+	ActiveFrom  *sql.NullTime
+	ActiveUntil *sql.NullTime
+	CreatedAt   *time.Time
+	UpdatedAt   *time.Time
 }
@@ -0,0 +1,29 @@
+package entity
+
+import "time"
+
+// BannerStats is the number of times the banner was shown to users and clicked by them during the Day.
+// VariantID is 0 for the own content of the banner, and for the stats summed over all the contents of the banner.
+// Day is the UTC midnight the day starts with.
+type BannerStats struct {
+	BannerID    int64
+	VariantID   int64
+	Day         time.Time
+	Impressions int64
+	Clicks      int64
+}
+
+// StatsDay returns the day of the moment t, that the stats are counted for.
+func StatsDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
+
+// CTR returns the click-through rate, the share of the impressions, that were clicked.
+// It's zero if there were no impressions.
+func (s *BannerStats) CTR() float64 {
+	if s.Impressions == 0 {
+		return 0
+	}
+
+	return float64(s.Clicks) / float64(s.Impressions)
+}
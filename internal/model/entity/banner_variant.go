@@ -0,0 +1,62 @@
+package entity
+
+import (
+	"hash/fnv"
+	"strconv"
+	"time"
+)
+
+// BannerVariant is an alternative content of a Banner, that is shown to a share of users for A/B testing.
+// The share is the Weight of the variant relative to the sum of the weights of all the banner variants.
+type BannerVariant struct {
+	ID        int64
+	BannerID  int64
+	Title     string
+	Text      string
+	URL       string
+	Weight    int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// UpdatableBannerVariant is a banner variant domain entity, that's being used to update a BannerVariant entity.
+// Pointer parameters indicate that they're optional, and are not considered during update.
+type UpdatableBannerVariant struct {
+	ID       int64
+	BannerID int64
+	Title    *string
+	Text     *string
+	URL      *string
+	Weight   *int
+}
+
+// Variant returns the variant of the banner assigned to the user with the given userID,
+// or nil if the banner has no variants with a positive weight, or the user is unknown.
+// The assignment is deterministic: the same user gets the same variant, as long as the weights are unchanged.
+// Variants must be ordered by ID, that's how they're stored in Banner.Variants.
+func (b *Banner) Variant(userID string) *BannerVariant {
+	if userID == "" {
+		return nil
+	}
+
+	total := 0
+	for _, v := range b.Variants {
+		total += v.Weight
+	}
+	if total == 0 {
+		return nil
+	}
+
+	// the banner id is a part of the hash, so the users are split independently for every banner
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(strconv.FormatInt(b.ID, 10) + ":" + userID))
+	bucket := int(h.Sum64() % uint64(total))
+	for i := range b.Variants {
+		bucket -= b.Variants[i].Weight
+		if bucket < 0 {
+			return &b.Variants[i]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,20 @@
+package entity
+
+import "time"
+
+// BannerVersion is a snapshot of a Banner state, that was current before the banner was changed.
+type BannerVersion struct {
+	BannerID    int64
+	Version     int
+	Title       string
+	Text        string
+	URL         string
+	FeatureID   int64
+	IsActive    bool
+	Priority    int
+	TagIDs      []int64
+	ActiveFrom  *time.Time
+	ActiveUntil *time.Time
+	UpdatedAt   time.Time
+	ArchivedAt  time.Time
+}
@@ -2,5 +2,15 @@ package entity
 
 // Feature is a feature domain entity.
 type Feature struct {
-	ID int64
+	ID          int64
+	Name        string
+	Description string
+}
+
+// UpdatableFeature is a feature domain entity, that's being used to update a main Feature entity.
+// Pointer parameters indicate that they're optional, and are not considered during update.
+type UpdatableFeature struct {
+	ID          int64
+	Name        *string
+	Description *string
 }
@@ -0,0 +1,38 @@
+package entity
+
+import "time"
+
+// JobStatus is a status of a deferred Job.
+type JobStatus string
+
+const (
+	// JobPending status indicates that a job is waiting to be executed or retried.
+	JobPending JobStatus = "pending"
+	// JobRunning status indicates that a job is being executed.
+	JobRunning JobStatus = "running"
+	// JobSucceeded status indicates that a job was executed successfully.
+	JobSucceeded JobStatus = "succeeded"
+	// JobFailed status indicates that a job failed and won't be retried anymore.
+	JobFailed JobStatus = "failed"
+)
+
+// Job is a deferred operation domain entity.
+type Job struct {
+	ID         string
+	Status     JobStatus
+	Error      string
+	Attempts   int64
+	Affected   int64
+	CreatedAt  time.Time
+	StartedAt  *time.Time
+	FinishedAt *time.Time
+}
+
+// NewJob returns a new pending Job instance.
+func NewJob(id string) *Job {
+	return &Job{
+		ID:        id,
+		Status:    JobPending,
+		CreatedAt: time.Now(),
+	}
+}
@@ -0,0 +1,61 @@
+package entity
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// AllFeaturesWriteScope grants modification of the banners of every feature.
+const AllFeaturesWriteScope = "feature:*:write"
+
+// FeatureWriteScope returns the scope, that grants modification of the banners of the feature.
+func FeatureWriteScope(featureID int64) string {
+	return "feature:" + strconv.FormatInt(featureID, 10) + ":write"
+}
+
+// ValidScope reports whether the scope is AllFeaturesWriteScope or the FeatureWriteScope of a feature.
+// The feature ID must be written the same way FeatureWriteScope writes it, otherwise the scope never matches.
+func ValidScope(scope string) bool {
+	if scope == AllFeaturesWriteScope {
+		return true
+	}
+
+	id, ok := strings.CutPrefix(scope, "feature:")
+	id, hasSuffix := strings.CutSuffix(id, ":write")
+	featureID, err := strconv.ParseInt(id, 10, 64)
+
+	return ok && hasSuffix && err == nil && featureID > 0 && FeatureWriteScope(featureID) == scope
+}
+
+// Principal is the authenticated caller with its role and scopes.
+type Principal struct {
+	Role   string
+	Scopes []string
+}
+
+// IsAdmin reports whether the principal has full access to the banners management.
+func (p Principal) IsAdmin() bool {
+	return p.Role == RoleAdmin
+}
+
+// CanRead reports whether the principal can read the banners management data.
+func (p Principal) CanRead() bool {
+	return p.Role == RoleViewer || p.Role == RoleEditor || p.Role == RoleAdmin
+}
+
+// CanWriteFeature reports whether the principal can modify the banners of the feature.
+// Admins can modify any banner, editors only the ones granted by their scopes.
+func (p Principal) CanWriteFeature(featureID int64) bool {
+	if p.IsAdmin() {
+		return true
+	}
+
+	return p.Role == RoleEditor &&
+		(slices.Contains(p.Scopes, AllFeaturesWriteScope) || slices.Contains(p.Scopes, FeatureWriteScope(featureID)))
+}
+
+// CanWriteAllFeatures reports whether the principal can modify the banners of every feature.
+func (p Principal) CanWriteAllFeatures() bool {
+	return p.IsAdmin() || (p.Role == RoleEditor && slices.Contains(p.Scopes, AllFeaturesWriteScope))
+}
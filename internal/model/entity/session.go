@@ -0,0 +1,12 @@
+package entity
+
+import "time"
+
+// Session is a login session of a user, that is identified by its refresh token.
+// AccessTokenID is the ID of the last access token issued within the session.
+type Session struct {
+	UserID        int64     `json:"user_id"`
+	Login         string    `json:"login"`
+	AccessTokenID string    `json:"access_token_id"`
+	CreatedAt     time.Time `json:"created_at"`
+}
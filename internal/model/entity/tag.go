@@ -2,5 +2,15 @@ package entity
 
 // Tag is a tag domain entity.
 type Tag struct {
-	ID int64
+	ID          int64
+	Name        string
+	Description string
+}
+
+// UpdatableTag is a tag domain entity, that's being used to update a main Tag entity.
+// Pointer parameters indicate that they're optional, and are not considered during update.
+type UpdatableTag struct {
+	ID          int64
+	Name        *string
+	Description *string
 }
@@ -0,0 +1,25 @@
+package entity
+
+import "time"
+
+const (
+	// RoleUser is a role of a user, that can only get banners.
+	RoleUser = "user"
+	// RoleViewer is a role of a user, that can read all the banners management data, but can't modify it.
+	RoleViewer = "viewer"
+	// RoleEditor is a role of a user, that can read all the banners management data,
+	// and modify the banners of the features granted by the scopes.
+	RoleEditor = "editor"
+	// RoleAdmin is a role of a user, that has full access to the banners management.
+	RoleAdmin = "admin"
+)
+
+// User is a registered account domain entity.
+type User struct {
+	ID           int64
+	Login        string
+	PasswordHash string
+	Role         string
+	Scopes       []string
+	CreatedAt    time.Time
+}
@@ -0,0 +1,175 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+
+	"banners-management/internal/cache/memory"
+	"banners-management/internal/lib/api/msg"
+	"banners-management/internal/lib/logger/sl"
+	"banners-management/internal/model/dto/apikey"
+	"banners-management/internal/model/entity"
+	"banners-management/internal/service"
+	"banners-management/internal/storage/repo"
+)
+
+const (
+	// keyPrefix marks the API keys of the service, so they're easy to find in the leaked secrets.
+	keyPrefix = "bnr_"
+	keySize   = 32
+	// displayPrefixLen is the length of the key prefix, that is stored as is to tell the keys apart.
+	displayPrefixLen = len(keyPrefix) + 8
+
+	// CacheTTL is the time an authenticated key is cached for. A key revoked on another app instance
+	// is accepted by this one for at most CacheTTL.
+	CacheTTL  = 10 * time.Second
+	cacheSize = 1000
+	// touchInterval is the accuracy of the last-used timestamps, so not every request writes to the storage.
+	touchInterval = time.Minute
+)
+
+var (
+	ErrNotFound = errors.New(msg.APIKeyNotFound)
+	ErrInvalid  = errors.New(msg.APIKeyInvalid)
+	ErrUnknown  = errors.New(msg.ErrUnknown)
+)
+
+var (
+	validatr = service.NewValidator()
+)
+
+// Service is a service for API keys management and authentication.
+type Service struct {
+	keys   repo.APIKeyRepo
+	cache  *memory.Cache[string, *entity.APIKey]
+	logger *slog.Logger
+}
+
+// NewService returns a new Service instance.
+func NewService(keys repo.APIKeyRepo, log *slog.Logger) *Service {
+	return &Service{
+		keys,
+		memory.NewCache[string, *entity.APIKey](cacheSize),
+		log.With(slog.String("comp", "service.apikey")),
+	}
+}
+
+// CreateKey generates a new API key and saves its hash to the storage.
+// It returns the key itself, that is never available again, along with the saved entity.
+func (s *Service) CreateKey(ctx context.Context, dto apikey.CreateDTO) (string, *entity.APIKey, error) {
+	if err := validatr.Struct(dto); err != nil {
+		var validErrs validator.ValidationErrors
+		errors.As(err, &validErrs)
+		s.logger.Info("request validation failed", sl.Err(err))
+		return "", nil, service.ValidationErr(validErrs)
+	}
+
+	key, err := newKey()
+	if err != nil {
+		s.logger.Error("failed to generate api key", sl.Err(err))
+		return "", nil, ErrUnknown
+	}
+
+	model := dto.ToModel(key[:displayPrefixLen], hashKey(key))
+	s.logger.Info("saving api key", slog.String("name", model.Name), slog.String("role", model.Role))
+	model.ID, err = s.keys.SaveAPIKey(ctx, model)
+	if err != nil {
+		s.logger.Error("failed to save api key", sl.Err(err))
+		return "", nil, ErrUnknown
+	}
+
+	return key, model, nil
+}
+
+// Keys returns a list of API keys, including the revoked ones. It respects the limit and offset parameters.
+func (s *Service) Keys(ctx context.Context, limit, offset *int) ([]*entity.APIKey, error) {
+	keys, err := s.keys.APIKeys(ctx, limit, offset)
+	if err != nil {
+		s.logger.Error("failed to get api keys", sl.Err(err))
+		return nil, ErrUnknown
+	}
+
+	return keys, nil
+}
+
+// RevokeKey revokes an API key by the ID. The key is not accepted anymore.
+// If the key was not found, it returns an error.
+func (s *Service) RevokeKey(ctx context.Context, id int64) error {
+	s.logger.Info("revoking api key", slog.Int64("id", id))
+	err := s.keys.RevokeAPIKey(ctx, id)
+	if errors.Is(err, repo.ErrAPIKeyNotFound) {
+		s.logger.Info("api key not found", sl.Err(err))
+		return ErrNotFound
+	} else if err != nil {
+		s.logger.Error("failed to revoke api key", sl.Err(err))
+		return ErrUnknown
+	}
+	// the cache is keyed by the hashes, so the revoked key can't be found in it by id
+	s.cache.Clear()
+
+	return nil
+}
+
+// Authenticate returns the API key entity of the key and records the moment it was used.
+// If the key is unknown or revoked, ErrInvalid is returned.
+func (s *Service) Authenticate(ctx context.Context, key string) (*entity.APIKey, error) {
+	h := hashKey(key)
+	k, ok := s.cache.Get(h)
+	if !ok {
+		var err error
+		k, err = s.keys.APIKeyByHash(ctx, h)
+		if errors.Is(err, repo.ErrAPIKeyNotFound) {
+			s.logger.Info("api key not found")
+			return nil, ErrInvalid
+		} else if err != nil {
+			s.logger.Error("failed to get api key", sl.Err(err))
+			return nil, ErrUnknown
+		}
+		s.cache.Set(h, k, CacheTTL)
+	}
+
+	if k.RevokedAt != nil {
+		s.logger.Info("api key revoked", slog.Int64("id", k.ID))
+		return nil, ErrInvalid
+	}
+
+	now := time.Now()
+	if k.LastUsedAt == nil || now.Sub(*k.LastUsedAt) > touchInterval {
+		// a failed usage record must not deny the access, so the error is only logged
+		if err := s.keys.TouchAPIKey(ctx, k.ID, now); err != nil {
+			s.logger.Error("failed to record api key usage", sl.Err(err), slog.Int64("id", k.ID))
+		} else {
+			// the cached entity is shared, so it's replaced instead of being modified
+			touched := *k
+			touched.LastUsedAt = &now
+			s.cache.Set(h, &touched, CacheTTL)
+		}
+	}
+
+	return k, nil
+}
+
+// newKey returns a new random API key.
+func newKey() (string, error) {
+	b := make([]byte, keySize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return keyPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashKey returns the hash of the key, that is stored instead of the key.
+// The keys are random and long, so a fast hash is enough, and it allows to find the key by its hash.
+func hashKey(key string) string {
+	h := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(h[:])
+}
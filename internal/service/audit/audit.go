@@ -0,0 +1,52 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"banners-management/internal/lib/api/msg"
+	"banners-management/internal/lib/logger/sl"
+	"banners-management/internal/model/entity"
+	"banners-management/internal/service"
+	"banners-management/internal/storage/repo"
+)
+
+var (
+	ErrUnknown = errors.New(msg.ErrUnknown)
+)
+
+// Service is a service for reading the audit log. The events are recorded by the services, that make mutations.
+type Service struct {
+	events repo.AuditReader
+	logger *slog.Logger
+}
+
+// NewService returns a new Service instance.
+func NewService(events repo.AuditReader, log *slog.Logger) *Service {
+	return &Service{
+		events,
+		log.With(slog.String("comp", "service.audit")),
+	}
+}
+
+// Events returns a list of audit events, that match the filter, starting from the most recent one.
+// It respects the limit and offset parameters.
+// If the time range of the filter is empty, a new service.ValidationError is returned.
+func (s *Service) Events(
+	ctx context.Context,
+	filter repo.AuditFilter,
+	limit, offset *int,
+) ([]*entity.AuditEvent, error) {
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, service.ValidationError("to must be after from")
+	}
+
+	events, err := s.events.AuditEvents(ctx, filter, limit, offset)
+	if err != nil {
+		s.logger.Error("failed to get audit events", sl.Err(err))
+		return nil, ErrUnknown
+	}
+
+	return events, nil
+}
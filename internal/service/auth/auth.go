@@ -0,0 +1,277 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"golang.org/x/crypto/bcrypt"
+
+	"banners-management/internal/lib/api/msg"
+	"banners-management/internal/lib/jwt"
+	"banners-management/internal/lib/logger/sl"
+	"banners-management/internal/model/dto/auth"
+	"banners-management/internal/model/entity"
+	"banners-management/internal/service"
+	"banners-management/internal/storage/repo"
+)
+
+var (
+	ErrInvalidCredentials = errors.New(msg.UserInvalidCredentials)
+	ErrInvalidRefresh     = errors.New(msg.InvalidRefreshToken)
+	ErrForeignRefresh     = errors.New(msg.ForeignRefreshToken)
+	ErrAlreadyExists      = errors.New(msg.UserAlreadyExists)
+	ErrUnknown            = errors.New(msg.ErrUnknown)
+)
+
+var (
+	validatr = service.NewValidator()
+
+	// dummyHash is compared with the password of a login attempt for an unknown user,
+	// so the response time doesn't reveal whether the user exists.
+	dummyHash = sync.OnceValue(func() []byte {
+		h, _ := bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+		return h
+	})
+)
+
+const refreshTokenSize = 32
+
+// Tokens is a pair of tokens issued to a user.
+// The access token authorizes the requests, and the refresh token is used to get a new pair.
+type Tokens struct {
+	Access  string
+	Refresh string
+}
+
+// Service is a service for the registered users authentication.
+type Service struct {
+	users      repo.UserRepo
+	tokens     repo.TokenStore
+	jwtManager *jwt.Manager
+	refreshTTL time.Duration
+	logger     *slog.Logger
+}
+
+// NewService returns a new Service instance. The refresh tokens are valid for the refreshTTL duration.
+func NewService(
+	users repo.UserRepo,
+	tokens repo.TokenStore,
+	jwtManager *jwt.Manager,
+	refreshTTL time.Duration,
+	log *slog.Logger,
+) *Service {
+	return &Service{
+		users,
+		tokens,
+		jwtManager,
+		refreshTTL,
+		log.With(slog.String("comp", "service.auth")),
+	}
+}
+
+// Login checks the credentials of a registered user and returns a new pair of tokens with the role of the user.
+// If the user doesn't exist or the password doesn't match, ErrInvalidCredentials is returned.
+func (s *Service) Login(ctx context.Context, dto auth.LoginDTO) (*Tokens, error) {
+	if err := validatr.Struct(dto); err != nil {
+		var validErrs validator.ValidationErrors
+		errors.As(err, &validErrs)
+		s.logger.Info("request validation failed", sl.Err(err))
+		return nil, service.ValidationErr(validErrs)
+	}
+
+	u, err := s.users.UserByLogin(ctx, dto.Login)
+	if errors.Is(err, repo.ErrUserNotFound) {
+		_ = bcrypt.CompareHashAndPassword(dummyHash(), []byte(dto.Password))
+		s.logger.Info("user not found", slog.String("login", dto.Login))
+		return nil, ErrInvalidCredentials
+	} else if err != nil {
+		s.logger.Error("failed to get user", sl.Err(err), slog.String("login", dto.Login))
+		return nil, ErrUnknown
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(dto.Password))
+	if err != nil {
+		s.logger.Info("password mismatch", slog.String("login", dto.Login))
+		return nil, ErrInvalidCredentials
+	}
+
+	return s.issueTokens(ctx, u)
+}
+
+// Refresh exchanges the refresh token for a new pair of tokens. The refresh token can be used only once,
+// and the last access token issued within its session is revoked.
+// The role of the new access token is read from the user store, so the role changes are applied.
+// If the refresh token is unknown or expired, ErrInvalidRefresh is returned.
+// The session is only taken after the user is loaded, so a failed lookup leaves the refresh token usable.
+func (s *Service) Refresh(ctx context.Context, dto auth.RefreshDTO) (*Tokens, error) {
+	if err := validatr.Struct(dto); err != nil {
+		var validErrs validator.ValidationErrors
+		errors.As(err, &validErrs)
+		s.logger.Info("request validation failed", sl.Err(err))
+		return nil, service.ValidationErr(validErrs)
+	}
+
+	session, err := s.tokens.Session(ctx, dto.RefreshToken)
+	if errors.Is(err, repo.ErrSessionNotFound) {
+		s.logger.Info("session not found")
+		return nil, ErrInvalidRefresh
+	} else if err != nil {
+		s.logger.Error("failed to get session", sl.Err(err))
+		return nil, ErrUnknown
+	}
+
+	u, err := s.users.UserByLogin(ctx, session.Login)
+	if errors.Is(err, repo.ErrUserNotFound) {
+		s.logger.Info("user of the session not found", slog.String("login", session.Login))
+		return nil, ErrInvalidRefresh
+	} else if err != nil {
+		s.logger.Error("failed to get user", sl.Err(err), slog.String("login", session.Login))
+		return nil, ErrUnknown
+	}
+
+	// the session of the refresh token can only be taken by someone else in between, never replaced
+	_, err = s.tokens.TakeSession(ctx, dto.RefreshToken)
+	if errors.Is(err, repo.ErrSessionNotFound) {
+		s.logger.Info("session already taken")
+		return nil, ErrInvalidRefresh
+	} else if err != nil {
+		s.logger.Error("failed to take session", sl.Err(err))
+		return nil, ErrUnknown
+	}
+
+	if err = s.revokeTokens(ctx, session.AccessTokenID); err != nil {
+		return nil, err
+	}
+
+	return s.issueTokens(ctx, u)
+}
+
+// Logout revokes the access token with the provided ID, and the session of the refresh token
+// along with the last access token issued within it.
+// An unknown refresh token is ignored, so the logout can be safely repeated.
+// If the session belongs to a user other than the one with the provided login,
+// nothing is revoked and ErrForeignRefresh is returned.
+func (s *Service) Logout(ctx context.Context, login, accessTokenID string, dto auth.RefreshDTO) error {
+	if err := validatr.Struct(dto); err != nil {
+		var validErrs validator.ValidationErrors
+		errors.As(err, &validErrs)
+		s.logger.Info("request validation failed", sl.Err(err))
+		return service.ValidationErr(validErrs)
+	}
+
+	ids := []string{accessTokenID}
+	session, err := s.tokens.Session(ctx, dto.RefreshToken)
+	if err == nil && session.Login != login {
+		s.logger.Info("session of another user", slog.String("login", login), slog.String("owner", session.Login))
+		return ErrForeignRefresh
+	} else if err == nil {
+		// the session of the refresh token can only be taken by someone else in between, never replaced
+		session, err = s.tokens.TakeSession(ctx, dto.RefreshToken)
+	}
+	if err == nil {
+		ids = append(ids, session.AccessTokenID)
+	} else if !errors.Is(err, repo.ErrSessionNotFound) {
+		s.logger.Error("failed to take session", sl.Err(err))
+		return ErrUnknown
+	}
+
+	return s.revokeTokens(ctx, ids...)
+}
+
+// revokeTokens revokes the access tokens with the provided IDs. Empty IDs are skipped.
+func (s *Service) revokeTokens(ctx context.Context, ids ...string) error {
+	for _, id := range ids {
+		if id == "" {
+			continue
+		}
+		// access tokens are never valid longer than the manager issues them for
+		err := s.tokens.RevokeToken(ctx, id, s.jwtManager.Expire())
+		if err != nil {
+			s.logger.Error("failed to revoke token", sl.Err(err), slog.String("token_id", id))
+			return ErrUnknown
+		}
+	}
+
+	return nil
+}
+
+// TokenRevoked reports whether the access token with the provided ID was revoked.
+func (s *Service) TokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	revoked, err := s.tokens.TokenRevoked(ctx, tokenID)
+	if err != nil {
+		s.logger.Error("failed to check token revocation", sl.Err(err), slog.String("token_id", tokenID))
+		return false, ErrUnknown
+	}
+
+	return revoked, nil
+}
+
+// CreateUser registers a new user with the bcrypt hash of the provided password.
+// It validates the input data and returns an error if the data is invalid.
+func (s *Service) CreateUser(ctx context.Context, dto auth.CreateUserDTO) (int64, error) {
+	if err := validatr.Struct(dto); err != nil {
+		var validErrs validator.ValidationErrors
+		errors.As(err, &validErrs)
+		s.logger.Info("request validation failed", sl.Err(err))
+		return 0, service.ValidationErr(validErrs)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(dto.Password), bcrypt.DefaultCost)
+	if err != nil {
+		s.logger.Error("failed to hash password", sl.Err(err))
+		return 0, ErrUnknown
+	}
+
+	s.logger.Info("saving user", slog.String("login", dto.Login), slog.String("role", dto.Role))
+	id, err := s.users.SaveUser(ctx, dto.ToModel(string(hash)))
+	if errors.Is(err, repo.ErrUserAlreadyExists) {
+		s.logger.Info("user already exists", sl.Err(err))
+		return 0, ErrAlreadyExists
+	} else if err != nil {
+		s.logger.Error("failed to save user", sl.Err(err))
+		return 0, ErrUnknown
+	}
+
+	return id, nil
+}
+
+// issueTokens returns a new pair of tokens for the user and starts a new session for the refresh token.
+func (s *Service) issueTokens(ctx context.Context, u *entity.User) (*Tokens, error) {
+	log := s.logger.With(slog.String("login", u.Login))
+	access, claims, err := s.jwtManager.IssueToken(u.Login, u.Role, u.Scopes)
+	if err != nil {
+		log.Error("failed to generate token", sl.Err(err))
+		return nil, ErrUnknown
+	}
+
+	refresh, err := newRefreshToken()
+	if err != nil {
+		log.Error("failed to generate refresh token", sl.Err(err))
+		return nil, ErrUnknown
+	}
+
+	session := &entity.Session{UserID: u.ID, Login: u.Login, AccessTokenID: claims.ID, CreatedAt: time.Now()}
+	err = s.tokens.SaveSession(ctx, refresh, session, s.refreshTTL)
+	if err != nil {
+		log.Error("failed to save session", sl.Err(err))
+		return nil, ErrUnknown
+	}
+
+	return &Tokens{Access: access, Refresh: refresh}, nil
+}
+
+// newRefreshToken returns a new random opaque refresh token.
+func newRefreshToken() (string, error) {
+	b := make([]byte, refreshTokenSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"banners-management/internal/cache/redis"
+	"banners-management/internal/model/entity"
+	"banners-management/internal/storage/repo"
+)
+
+// RedisTokenStore is a repo.TokenStore, that keeps the sessions and the revoked access token IDs in redis.
+// The refresh tokens are never stored as is, only their hashes are used as keys.
+type RedisTokenStore struct {
+	cache *redis.Cache
+}
+
+// NewRedisTokenStore returns a new RedisTokenStore instance.
+func NewRedisTokenStore(cache *redis.Cache) *RedisTokenStore {
+	return &RedisTokenStore{cache: cache}
+}
+
+// SaveSession stores the session by its refresh token for the ttl duration.
+func (ts *RedisTokenStore) SaveSession(
+	ctx context.Context,
+	refreshToken string,
+	s *entity.Session,
+	ttl time.Duration,
+) error {
+	err := redis.Set(ts.cache, ctx, sessionKey(refreshToken), redis.NewCacheItem(s, redis.StatusExists), ttl)
+	if err != nil {
+		return fmt.Errorf("service.auth.token_store.SaveSession: %w", err)
+	}
+
+	return nil
+}
+
+// Session returns the session stored by the refresh token.
+// If there is no such session, repo.ErrSessionNotFound is returned.
+func (ts *RedisTokenStore) Session(ctx context.Context, refreshToken string) (*entity.Session, error) {
+	const comp = "service.auth.token_store.Session"
+	v, err := redis.Get[*entity.Session](ts.cache, ctx, sessionKey(refreshToken))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", comp, err)
+	}
+	if v.Status != redis.StatusExists || v.Value == nil {
+		return nil, fmt.Errorf("%s: %w", comp, repo.ErrSessionNotFound)
+	}
+
+	return v.Value, nil
+}
+
+// TakeSession returns the session stored by the refresh token and removes it.
+// If there is no such session, repo.ErrSessionNotFound is returned.
+func (ts *RedisTokenStore) TakeSession(ctx context.Context, refreshToken string) (*entity.Session, error) {
+	const comp = "service.auth.token_store.TakeSession"
+	v, err := redis.GetDel[*entity.Session](ts.cache, ctx, sessionKey(refreshToken))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", comp, err)
+	}
+	if v.Status != redis.StatusExists || v.Value == nil {
+		return nil, fmt.Errorf("%s: %w", comp, repo.ErrSessionNotFound)
+	}
+
+	return v.Value, nil
+}
+
+// RevokeToken adds the access token ID to the revocation list for the ttl duration.
+// The ttl is expected to be not less than the remaining lifetime of the token.
+func (ts *RedisTokenStore) RevokeToken(ctx context.Context, tokenID string, ttl time.Duration) error {
+	err := redis.Set(ts.cache, ctx, revokedKey(tokenID), redis.NewCacheItem(true, redis.StatusExists), ttl)
+	if err != nil {
+		return fmt.Errorf("service.auth.token_store.RevokeToken: %w", err)
+	}
+
+	return nil
+}
+
+// TokenRevoked reports whether the access token ID is in the revocation list.
+func (ts *RedisTokenStore) TokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	revoked, err := ts.cache.Exists(ctx, revokedKey(tokenID))
+	if err != nil {
+		return false, fmt.Errorf("service.auth.token_store.TokenRevoked: %w", err)
+	}
+
+	return revoked, nil
+}
+
+// sessionKey returns the redis key of the session of the refresh token.
+func sessionKey(refreshToken string) string {
+	h := sha256.Sum256([]byte(refreshToken))
+	return "session:" + hex.EncodeToString(h[:])
+}
+
+// revokedKey returns the redis key of the revoked access token ID.
+func revokedKey(tokenID string) string {
+	return "revoked:" + tokenID
+}
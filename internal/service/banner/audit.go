@@ -0,0 +1,64 @@
+package banner
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"banners-management/internal/lib/api"
+	"banners-management/internal/lib/logger/sl"
+	"banners-management/internal/model/entity"
+)
+
+// record saves the audit event of the mutation, made by the caller of the request ctx belongs to.
+// The mutation is already done, so a failure to record it is only logged.
+func (s *Service) record(ctx context.Context, e *entity.AuditEvent) {
+	e.Actor = api.SubjectFromContext(ctx)
+	e.ActorRole = api.RoleFromContext(ctx)
+	e.RequestID = api.RequestIDFromContext(ctx)
+	e.CreatedAt = time.Now()
+
+	// the event must be recorded, even if the caller has gone
+	_, err := s.audit.SaveAuditEvent(context.WithoutCancel(ctx), e)
+	if err != nil {
+		s.logger.Error("failed to record audit event",
+			sl.Err(err),
+			slog.String("action", e.Action),
+			sl.OptInt64("bannerID", e.BannerID),
+			slog.String("actor", e.Actor),
+		)
+	}
+}
+
+// recordBanner saves the audit event of the mutation of the banner by id with the diff of its fields.
+func (s *Service) recordBanner(ctx context.Context, action string, id int64, diff map[string]entity.AuditChange) {
+	s.record(ctx, &entity.AuditEvent{Action: action, BannerID: &id, Diff: diff})
+}
+
+// recordVariant saves the audit event of the mutation of the banner variant by variantID
+// with the diff of its fields.
+func (s *Service) recordVariant(
+	ctx context.Context,
+	action string,
+	id, variantID int64,
+	diff map[string]entity.AuditChange,
+) {
+	s.record(ctx, &entity.AuditEvent{Action: action, BannerID: &id, VariantID: &variantID, Diff: diff})
+}
+
+// variantDiffWithCurrent returns the diff between the banner variant snapshot before the mutation
+// and the current state of the variant of the banner by id.
+// If the current state can't be read, nil is returned, so the mutation is still recorded, but without its diff.
+func (s *Service) variantDiffWithCurrent(
+	ctx context.Context,
+	id int64,
+	before *entity.BannerVariant,
+) map[string]entity.AuditChange {
+	after, err := s.bannerVariant(ctx, id, before.ID)
+	if err != nil {
+		s.logger.Error("failed to read banner variant for audit", sl.Err(err), slog.Int64("variantID", before.ID))
+		return nil
+	}
+
+	return entity.VariantDiff(before, after)
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"log/slog"
 	"strconv"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 
@@ -17,12 +18,16 @@ import (
 )
 
 var (
-	ErrNotSaved      = errors.New(msg.ErrSavingBanner)
-	ErrAlreadyExists = errors.New(msg.BannerAlreadyExists)
-	ErrNotFound      = errors.New(msg.BannerNotFound)
-	ErrNotActive     = errors.New(msg.BannerNotActive)
-	ErrUnknown       = errors.New(msg.ErrUnknown)
-	ErrNotUnique     = errors.New(msg.BannerNotUnique)
+	ErrNotSaved  = errors.New(msg.ErrSavingBanner)
+	ErrNotFound  = errors.New(msg.BannerNotFound)
+	ErrNotActive = errors.New(msg.BannerNotActive)
+	ErrUnknown   = errors.New(msg.ErrUnknown)
+
+	ErrUnknownReference = errors.New(msg.BannerUnknownReference)
+
+	ErrVersionNotFound = errors.New(msg.BannerVersionNotFound)
+	ErrVariantNotFound = errors.New(msg.BannerVariantNotFound)
+	ErrJobNotFound     = errors.New(msg.JobNotFound)
 )
 
 var (
@@ -31,31 +36,52 @@ var (
 
 // Service is a service for banner CRUD operations.
 type Service struct {
-	reader  repo.BannerReader
-	saver   repo.BannerSaver
-	deleter repo.BannerDeleter
-	updater repo.BannerUpdater
-	logger  *slog.Logger
+	reader    repo.BannerReader
+	byID      repo.BannerByIDReader
+	exporter  repo.BannerExporter
+	saver     repo.BannerSaver
+	deleter   repo.BannerDeleter
+	updater   repo.BannerUpdater
+	versioner repo.BannerVersioner
+	variants  repo.BannerVariantStore
+	jobs      repo.BannerJobs
+	stats     repo.BannerStats
+	audit     repo.AuditSaver
+	logger    *slog.Logger
 }
 
 // NewService returns a new Service instance.
 func NewService(
 	reader repo.BannerReader,
+	byID repo.BannerByIDReader,
+	exporter repo.BannerExporter,
 	saver repo.BannerSaver,
 	deleter repo.BannerDeleter,
 	updater repo.BannerUpdater,
+	versioner repo.BannerVersioner,
+	variants repo.BannerVariantStore,
+	jobs repo.BannerJobs,
+	stats repo.BannerStats,
+	audit repo.AuditSaver,
 	log *slog.Logger,
 ) *Service {
 	return &Service{
 		reader,
+		byID,
+		exporter,
 		saver,
 		deleter,
 		updater,
+		versioner,
+		variants,
+		jobs,
+		stats,
+		audit,
 		log.With(slog.String("comp", "service.banner")),
 	}
 }
 
-// SaveBanner saves a new banner to the storage.
+// SaveBanner saves a new banner to the storage and records it to the audit log.
 // It validates the input data and returns an error if the data is invalid.
 func (s *Service) SaveBanner(ctx context.Context, dto banner.CreateDTO) (int64, error) {
 	if err := validatr.Struct(dto); err != nil {
@@ -65,71 +91,77 @@ func (s *Service) SaveBanner(ctx context.Context, dto banner.CreateDTO) (int64,
 		return 0, service.ValidationErr(validErrs)
 	}
 
+	if err := validateWindow(dto.ActiveFrom, dto.ActiveUntil); err != nil {
+		s.logger.Info("request validation failed", sl.Err(err))
+		return 0, err
+	}
+
 	model := dto.ToModel()
 	s.logger.Info("saving banner", slog.String("title", model.Title))
 	id, err := s.saver.SaveBanner(ctx, model)
-	if errors.Is(err, repo.ErrBannerAlreadyExists) {
-		s.logger.Info("banner already exists", sl.Err(err))
-		return 0, ErrAlreadyExists
+	if errors.Is(err, repo.ErrBannerUnknownReference) {
+		s.logger.Info("banner references unknown feature or tags", sl.Err(err))
+		return 0, ErrUnknownReference
 	} else if err != nil {
 		s.logger.Error("failed to save banner", sl.Err(err))
 		return 0, ErrNotSaved
 	}
+	s.recordBanner(ctx, entity.AuditBannerCreate, id, entity.BannerDiff(nil, model))
 
 	return id, nil
 }
 
 // BannerByFeatureTag returns a banner by the feature and tag ID.
-// It also respects the limit and offset parameters.
+// If useLastRevision is false, the banner may be up to CacheTTL outdated.
+// The returned repo.Revision tells how fresh the banner is.
+// If asUser is true, and the banner is not active or out of its activation window, ErrNotActive is returned.
 func (s *Service) BannerByFeatureTag(
 	ctx context.Context,
 	featureID, tagID int64,
 	useLastRevision, asUser bool,
-) (*entity.Banner, error) {
-	b, err := s.reader.BannerByFeatureTag(ctx, featureID, tagID, useLastRevision)
+) (*entity.Banner, repo.Revision, error) {
+	b, rev, err := s.reader.BannerByFeatureTag(ctx, featureID, tagID, useLastRevision)
 	if err != nil || b == nil {
 		if errors.Is(err, repo.ErrBannerNotFound) {
 			s.logger.Info("banner not found",
 				slog.Int64("featureID", featureID), slog.Int64("tagID", tagID))
-			return nil, ErrNotFound
-		} else if errors.Is(err, repo.ErrBannerNotUnique) {
-			s.logger.Info("banner not unique",
-				slog.Int64("featureID", featureID), slog.Int64("tagID", tagID))
-			return nil, ErrNotUnique
+			return nil, rev, ErrNotFound
 		}
 		s.logger.Error("failed to get banner by feature and tag",
 			sl.Err(err), slog.Int64("featureID", featureID), slog.Int64("tagID", tagID))
-		return nil, ErrUnknown
+		return nil, rev, ErrUnknown
 	}
 
 	if asUser {
 		if !b.IsActive {
 			s.logger.Info("banner not active, restricting user access", slog.Int64("id", b.ID))
-			return nil, ErrNotActive
+			return nil, rev, ErrNotActive
+		}
+		if !b.InWindow(time.Now()) {
+			s.logger.Info("banner out of activation window, restricting user access", slog.Int64("id", b.ID))
+			return nil, rev, ErrNotActive
 		}
 	}
 
-	return b, nil
+	return b, rev, nil
 }
 
-// BannersByFeatureTag returns a list of banners by the feature and tag ID.
-// It also respects the limit and offset parameters.
-func (s *Service) BannersByFeatureTag(
-	ctx context.Context,
-	featureID, tagID *int64,
-	limit, offset *int,
-	useLastRevision *bool,
-) ([]*entity.Banner, error) {
-	banners, err := s.reader.BannersByFeatureTag(ctx, featureID, tagID, limit, offset, useLastRevision)
-	if err != nil {
-		s.logger.Error("failed to get banner by feature and tag", sl.Err(err))
+// Banner returns the current revision of the banner by the ID.
+// If the banner was not found, it returns an error.
+func (s *Service) Banner(ctx context.Context, id int64) (*entity.Banner, error) {
+	b, err := s.byID.BannerByID(ctx, id)
+	if errors.Is(err, repo.ErrBannerNotFound) {
+		s.logger.Info("banner not found", slog.Int64("id", id))
+		return nil, ErrNotFound
+	} else if err != nil {
+		s.logger.Error("failed to get banner", sl.Err(err), slog.Int64("id", id))
 		return nil, ErrUnknown
 	}
 
-	return banners, nil
+	return b, nil
 }
 
-// DeleteBanner deletes a banner by the ID.
+// DeleteBanner deletes a banner by the ID and records it to the audit log.
 // If the banner was not found, it returns an error.
 func (s *Service) DeleteBanner(ctx context.Context, id int64) error {
 	if err := validatr.Var(id, "required"); err != nil {
@@ -139,7 +171,7 @@ func (s *Service) DeleteBanner(ctx context.Context, id int64) error {
 		return service.ValidationErr(validErrs)
 	}
 
-	err := s.deleter.DeleteBanner(ctx, id)
+	before, err := s.deleter.DeleteBanner(ctx, id)
 	if errors.Is(err, repo.ErrBannerNotFound) {
 		s.logger.Info("banner not found", sl.Err(err))
 		return ErrNotFound
@@ -147,11 +179,12 @@ func (s *Service) DeleteBanner(ctx context.Context, id int64) error {
 		s.logger.Error("failed to delete banner", sl.Err(err))
 		return ErrUnknown
 	}
+	s.recordBanner(ctx, entity.AuditBannerDelete, id, entity.BannerDiff(before, nil))
 
 	return nil
 }
 
-// UpdateBanner updates a banner by the ID.
+// UpdateBanner updates a banner by the ID and records the changed fields to the audit log.
 // If the banner was not found, it returns an error.
 func (s *Service) UpdateBanner(ctx context.Context, id int64, dto banner.UpdateDTO) error {
 	if err := validatr.Struct(dto); err != nil {
@@ -161,38 +194,78 @@ func (s *Service) UpdateBanner(ctx context.Context, id int64, dto banner.UpdateD
 		return service.ValidationErr(validErrs)
 	}
 
+	current, err := s.Banner(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// the bound missing from the request is kept, so it must be checked against the provided one
+	from, until := current.ActiveFrom, current.ActiveUntil
+	if dto.ActiveFrom.Set {
+		from = dto.ActiveFrom.Time
+	}
+	if dto.ActiveUntil.Set {
+		until = dto.ActiveUntil.Time
+	}
+	if err := validateWindow(from, until); err != nil {
+		s.logger.Info("request validation failed", sl.Err(err))
+		return err
+	}
+
 	model := dto.ToModel(id)
 	s.logger.Info("updating banner", slog.String("id", strconv.FormatInt(id, 10)))
-	err := s.updater.UpdateBanner(ctx, model)
+	before, after, err := s.updater.UpdateBanner(ctx, model)
 	if errors.Is(err, repo.ErrBannerNotFound) {
 		s.logger.Info("banner not found", sl.Err(err))
 		return ErrNotFound
-	} else if errors.Is(err, repo.ErrBannerAlreadyExists) {
-		s.logger.Info("unable to update banner", sl.Err(err))
-		return ErrAlreadyExists
+	} else if errors.Is(err, repo.ErrBannerUnknownReference) {
+		s.logger.Info("banner references unknown feature or tags", sl.Err(err))
+		return ErrUnknownReference
 	} else if err != nil {
 		s.logger.Error("failed to update banner", sl.Err(err))
 		return ErrUnknown
 	}
+	s.recordBanner(ctx, entity.AuditBannerUpdate, id, entity.BannerDiff(before, after))
 
 	return nil
 }
 
-// DeleteBannerByFeatureTag deletes a banner by provided featureID and tagID.
-// If featureID and/or tagID are nil, a new service.ValidationError is returned.
-func (s *Service) DeleteBannerByFeatureTag(ctx context.Context, featureID, tagID *int64) error {
-	if featureID == nil || tagID == nil {
-		return service.ValidationError("featureID or tagID or both not provided")
+// DeleteBannerByFeatureTag schedules deletion of banners by provided featureID and/or tagID.
+// If both are provided, all the banners of the feature with the tag are deleted. If only featureID is provided,
+// all the banners of the feature are deleted. If only tagID is provided, the tag is removed from
+// all the banners, and the banners left with no tags are deleted.
+// It returns the ID of the job, that can be used to track the deletion state.
+// The scheduling is recorded to the audit log, and the affected banners are recorded by the job, when it's executed.
+// If both featureID and tagID are nil, a new service.ValidationError is returned.
+func (s *Service) DeleteBannerByFeatureTag(ctx context.Context, featureID, tagID *int64) (string, error) {
+	if featureID == nil && tagID == nil {
+		return "", service.ValidationError("neither featureID nor tagID provided")
 	}
 
-	err := s.deleter.DeleteByFeatureTag(ctx, *featureID, *tagID)
+	jobID, err := s.jobs.ScheduleDelete(ctx, featureID, tagID)
 	if err != nil {
-		s.logger.Error("unable to delete banner by feature & tag",
-			slog.Int64("featureID", *featureID),
-			slog.Int64("tagID", *tagID),
+		s.logger.Error("unable to schedule banners deletion",
+			sl.OptInt64("featureID", featureID),
+			sl.OptInt64("tagID", tagID),
 			sl.Err(err),
 		)
-		return ErrUnknown
+		return "", ErrUnknown
+	}
+	s.record(ctx, &entity.AuditEvent{
+		Action:    entity.AuditBannerDeleteByFeatureTag,
+		FeatureID: featureID,
+		TagID:     tagID,
+		JobID:     &jobID,
+	})
+
+	return jobID, nil
+}
+
+// validateWindow returns a new service.ValidationError if both activation window bounds are provided,
+// and the window is empty.
+func validateWindow(from, until *time.Time) error {
+	if from != nil && until != nil && !from.Before(*until) {
+		return service.ValidationError("active_until must be after active_from")
 	}
 
 	return nil
@@ -0,0 +1,136 @@
+package banner
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+
+	"github.com/go-playground/validator/v10"
+
+	"banners-management/internal/lib/api/msg"
+	"banners-management/internal/lib/logger/sl"
+	"banners-management/internal/model/dto/banner"
+	"banners-management/internal/model/entity"
+	"banners-management/internal/service"
+	"banners-management/internal/storage/repo"
+)
+
+// MaxBulkRows is the maximum number of banners, that can be imported at once.
+const MaxBulkRows = 1000
+
+var (
+	ErrBulkRejected = errors.New(msg.BannerBulkRejected)
+)
+
+// RowError is an error of a single row of the bulk import.
+type RowError struct {
+	Row int
+	Err error
+}
+
+// ImportResult is a result of the bulk import.
+// BannerIDs are the IDs of the created banners in the order of the rows, they're nil in the dry-run mode.
+// Errors are the errors of the failed rows ordered by the row numbers.
+type ImportResult struct {
+	BannerIDs []int64
+	Errors    []RowError
+}
+
+// ImportBanners validates every row with the same rules as SaveBanner does, and saves all the banners
+// in a single transaction. If any of the rows fails, none of the banners are saved, and ErrBulkRejected
+// is returned along with the errors of all the failed rows.
+// If dryRun is true, nothing is saved, and the errors are reported without ErrBulkRejected.
+// The created banners are recorded to the audit log.
+func (s *Service) ImportBanners(ctx context.Context, rows []banner.BulkRow, dryRun bool) (*ImportResult, error) {
+	if len(rows) == 0 {
+		return nil, service.ValidationError(msg.BannerBulkEmpty)
+	}
+	if len(rows) > MaxBulkRows {
+		return nil, service.ValidationError(msg.BannerBulkTooLarge)
+	}
+
+	res := new(ImportResult)
+	models := make([]*entity.Banner, 0, len(rows))
+	modelRows := make([]int, 0, len(rows))
+	for _, r := range rows {
+		if err := validateRow(r); err != nil {
+			res.Errors = append(res.Errors, RowError{r.Row, err})
+			continue
+		}
+		models = append(models, r.DTO.ToModel())
+		modelRows = append(modelRows, r.Row)
+	}
+
+	s.logger.Info("importing banners", slog.Int("rows", len(rows)), slog.Int("invalid", len(res.Errors)))
+	var ids []int64
+	if len(models) > 0 {
+		var err error
+		// the valid rows are still checked against the storage to report all the errors at once
+		ids, err = s.saver.SaveBanners(ctx, models, dryRun || len(res.Errors) > 0)
+		var bulkErr repo.BulkError
+		if errors.As(err, &bulkErr) {
+			for i, err := range bulkErr {
+				res.Errors = append(res.Errors, RowError{modelRows[i], storageErr(err)})
+			}
+		} else if err != nil {
+			s.logger.Error("failed to save banners", sl.Err(err))
+			return nil, ErrNotSaved
+		}
+	}
+
+	slices.SortFunc(res.Errors, func(a, b RowError) int { return a.Row - b.Row })
+	if len(res.Errors) > 0 {
+		s.logger.Info("banners import rejected", slog.Int("failed", len(res.Errors)), slog.Bool("dryRun", dryRun))
+		if dryRun {
+			return res, nil
+		}
+		return res, ErrBulkRejected
+	}
+	if dryRun {
+		return res, nil
+	}
+
+	res.BannerIDs = ids
+	for i, id := range ids {
+		s.recordBanner(ctx, entity.AuditBannerCreate, id, entity.BannerDiff(nil, models[i]))
+	}
+
+	return res, nil
+}
+
+// validateRow returns an error, if the row couldn't be decoded, or its banner is invalid.
+func validateRow(r banner.BulkRow) error {
+	if r.Err != nil {
+		return r.Err
+	}
+	if err := validatr.Struct(r.DTO); err != nil {
+		var validErrs validator.ValidationErrors
+		errors.As(err, &validErrs)
+		return service.ValidationErr(validErrs)
+	}
+
+	return validateWindow(r.DTO.ActiveFrom, r.DTO.ActiveUntil)
+}
+
+// storageErr returns the service error by the storage error of a single banner.
+func storageErr(err error) error {
+	if errors.Is(err, repo.ErrBannerUnknownReference) {
+		return ErrUnknownReference
+	}
+
+	return ErrNotSaved
+}
+
+// ExportBanners calls fn for every banner ordered by id, while reading them from the storage,
+// so the banners can be streamed to the client. The banners are always of the last revision.
+// If fn returns an error, the export is stopped and ErrUnknown is returned.
+func (s *Service) ExportBanners(ctx context.Context, fn func(b *entity.Banner) error) error {
+	err := s.exporter.EachBanner(ctx, fn)
+	if err != nil {
+		s.logger.Error("failed to export banners", sl.Err(err))
+		return ErrUnknown
+	}
+
+	return nil
+}
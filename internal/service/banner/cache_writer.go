@@ -0,0 +1,273 @@
+package banner
+
+import (
+	"context"
+	"log/slog"
+
+	"banners-management/internal/cache/redis"
+	"banners-management/internal/lib/logger/sl"
+	"banners-management/internal/model/entity"
+	"banners-management/internal/storage/repo"
+)
+
+// WriteStorage is a storage that is decorated by CacheWriter.
+type WriteStorage interface {
+	repo.BannerReader
+	repo.BannerByIDReader
+	repo.BannerSaver
+	repo.BannerUpdater
+	repo.BannerDeleter
+	repo.BannerVersioner
+	repo.BannerVariantStore
+}
+
+// CacheWriter is a decorator for repo.BannerSaver, repo.BannerUpdater, repo.BannerDeleter,
+// repo.BannerVersioner and repo.BannerVariantStore that evicts all the redis keys affected by a successful
+// write operation, so the cached data, populated by CacheReader, is never outdated after the write.
+// The keys are also invalidated in the in-process LocalCache of every app instance.
+type CacheWriter struct {
+	storage WriteStorage
+	cache   *redis.Cache
+	local   *LocalCache
+	logger  *slog.Logger
+}
+
+// NewCacheWriter returns a new CacheWriter instance. If local is nil, the in-process cache is not used.
+func NewCacheWriter(storage WriteStorage, cache *redis.Cache, local *LocalCache, logger *slog.Logger) *CacheWriter {
+	return &CacheWriter{
+		storage: storage,
+		cache:   cache,
+		local:   local,
+		logger:  logger,
+	}
+}
+
+// SaveBanner saves the banner with the decorated repo.BannerSaver and evicts all the keys
+// of its feature and tags, including the negatively cached ones.
+func (cw *CacheWriter) SaveBanner(ctx context.Context, b *entity.Banner) (int64, error) {
+	id, err := cw.storage.SaveBanner(ctx, b)
+	if err != nil {
+		return id, err
+	}
+
+	cw.evict(ctx, bannerCacheKeys(b.FeatureID, b.TagIDs))
+
+	return id, nil
+}
+
+// SaveBanners saves the banners with the decorated repo.BannerSaver and evicts all the keys
+// of their features and tags. Nothing is evicted in the dry-run mode.
+func (cw *CacheWriter) SaveBanners(ctx context.Context, bs []*entity.Banner, dryRun bool) ([]int64, error) {
+	ids, err := cw.storage.SaveBanners(ctx, bs, dryRun)
+	if err != nil || dryRun {
+		return ids, err
+	}
+
+	cw.evict(ctx, bannersCacheKeys(bs))
+
+	return ids, nil
+}
+
+// UpdateBanner updates the banner with the decorated repo.BannerUpdater and evicts all the keys
+// of both its old and new feature and tags.
+func (cw *CacheWriter) UpdateBanner(
+	ctx context.Context,
+	b *entity.UpdatableBanner,
+) (before, after *entity.Banner, err error) {
+	before, after, err = cw.storage.UpdateBanner(ctx, b)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cw.evict(ctx, bannersCacheKeys([]*entity.Banner{before, after}))
+
+	return before, after, nil
+}
+
+// DeleteBanner deletes the banner with the decorated repo.BannerDeleter and evicts all the keys
+// of its feature and tags.
+func (cw *CacheWriter) DeleteBanner(ctx context.Context, bannerID int64) (*entity.Banner, error) {
+	deleted, err := cw.storage.DeleteBanner(ctx, bannerID)
+	if err != nil {
+		return nil, err
+	}
+
+	cw.evict(ctx, bannerCacheKeys(deleted.FeatureID, deleted.TagIDs))
+
+	return deleted, nil
+}
+
+// DeleteByFeatureTag deletes the banners of the feature and tag with the decorated repo.BannerDeleter
+// and evicts all the keys of the deleted banners.
+func (cw *CacheWriter) DeleteByFeatureTag(ctx context.Context, featureID, tagID int64) ([]*entity.Banner, error) {
+	deleted, err := cw.storage.DeleteByFeatureTag(ctx, featureID, tagID)
+	if err != nil {
+		return nil, err
+	}
+
+	cw.evict(ctx, bannersCacheKeys(deleted))
+
+	return deleted, nil
+}
+
+// DeleteByFeature deletes the banners of the feature with the decorated repo.BannerDeleter and evicts
+// all the keys of the deleted banners.
+func (cw *CacheWriter) DeleteByFeature(ctx context.Context, featureID int64) ([]*entity.Banner, error) {
+	deleted, err := cw.storage.DeleteByFeature(ctx, featureID)
+	if err != nil {
+		return nil, err
+	}
+
+	cw.evict(ctx, bannersCacheKeys(deleted))
+
+	return deleted, nil
+}
+
+// DeleteByTag removes the tag from the banners with the decorated repo.BannerDeleter and evicts
+// all the keys of the banners that had the tag before the deletion.
+func (cw *CacheWriter) DeleteByTag(ctx context.Context, tagID int64) ([]*entity.Banner, error) {
+	affected, err := cw.storage.DeleteByTag(ctx, tagID)
+	if err != nil {
+		return nil, err
+	}
+
+	cw.evict(ctx, bannersCacheKeys(affected))
+
+	return affected, nil
+}
+
+// BannerVersions does nothing and just proxies the request to the decorated repo.BannerVersioner.
+func (cw *CacheWriter) BannerVersions(ctx context.Context, bannerID int64) ([]*entity.BannerVersion, error) {
+	return cw.storage.BannerVersions(ctx, bannerID)
+}
+
+// RestoreBannerVersion restores the banner version with the decorated repo.BannerVersioner and evicts
+// all the keys of both its replaced and restored feature and tags.
+func (cw *CacheWriter) RestoreBannerVersion(
+	ctx context.Context,
+	bannerID int64,
+	version int,
+) (before, after *entity.Banner, err error) {
+	before, after, err = cw.storage.RestoreBannerVersion(ctx, bannerID, version)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cw.evict(ctx, bannersCacheKeys([]*entity.Banner{before, after}))
+
+	return before, after, nil
+}
+
+// BannerVariants does nothing and just proxies the request to the decorated repo.BannerVariantStore.
+func (cw *CacheWriter) BannerVariants(ctx context.Context, bannerID int64) ([]*entity.BannerVariant, error) {
+	return cw.storage.BannerVariants(ctx, bannerID)
+}
+
+// SaveBannerVariant saves the variant with the decorated repo.BannerVariantStore and evicts all the keys
+// of its banner, since the variants are cached along with the banner.
+func (cw *CacheWriter) SaveBannerVariant(ctx context.Context, v *entity.BannerVariant) (int64, error) {
+	id, err := cw.storage.SaveBannerVariant(ctx, v)
+	if err != nil {
+		return id, err
+	}
+
+	cw.evictBanner(ctx, v.BannerID)
+
+	return id, nil
+}
+
+// UpdateBannerVariant updates the variant with the decorated repo.BannerVariantStore and evicts all the keys
+// of its banner.
+func (cw *CacheWriter) UpdateBannerVariant(ctx context.Context, v *entity.UpdatableBannerVariant) error {
+	err := cw.storage.UpdateBannerVariant(ctx, v)
+	if err != nil {
+		return err
+	}
+
+	cw.evictBanner(ctx, v.BannerID)
+
+	return nil
+}
+
+// DeleteBannerVariant deletes the variant with the decorated repo.BannerVariantStore and evicts all the keys
+// of its banner.
+func (cw *CacheWriter) DeleteBannerVariant(ctx context.Context, bannerID, variantID int64) error {
+	err := cw.storage.DeleteBannerVariant(ctx, bannerID, variantID)
+	if err != nil {
+		return err
+	}
+
+	cw.evictBanner(ctx, bannerID)
+
+	return nil
+}
+
+// evictBanner evicts all the keys of the feature and tags of the banner by id.
+// The write operation has already succeeded at this point, so the errors are only logged.
+func (cw *CacheWriter) evictBanner(ctx context.Context, bannerID int64) {
+	b, err := cw.storage.BannerByID(ctx, bannerID)
+	if err != nil {
+		cw.logger.Error("unable to read banner, its keys are not evicted", sl.Err(err), slog.Int64("id", bannerID))
+		return
+	}
+
+	cw.evict(ctx, bannerCacheKeys(b.FeatureID, b.TagIDs))
+}
+
+// evict removes from redis cache the provided keys, and invalidates them in the in-process cache.
+// The versions of the keys are bumped first, so the reads, that started before the write operation
+// and finish after the eviction, don't put the outdated data back, see CacheReader.
+// The cached lists are invalidated by bumping their shared version, that is a part of their keys,
+// and the outdated ones expire on their own.
+// The write operation has already succeeded at this point, so the errors are only logged.
+func (cw *CacheWriter) evict(ctx context.Context, keys []string) {
+	const comp = "service.banner.cache_writer.evict"
+	log := cw.logger.With(slog.String("comp", comp))
+
+	// the list version never expires, otherwise it could start over and reach the keys of the outdated lists
+	err := cw.cache.BumpVersions(ctx, 0, listVersionKey)
+	if err != nil {
+		log.Error("unable to bump banner lists version", sl.Err(err))
+	}
+
+	versionKeys := make([]string, len(keys))
+	for i, k := range keys {
+		versionKeys[i] = redis.VersionKey(k)
+	}
+	err = cw.cache.BumpVersions(ctx, cacheVersionTTL, versionKeys...)
+	if err != nil {
+		log.Error("unable to bump banner keys versions", sl.Err(err), slog.Any("keys", keys))
+	}
+
+	err = cw.cache.Delete(ctx, keys...)
+	if err != nil {
+		log.Error("unable to evict banner keys", sl.Err(err), slog.Any("keys", keys))
+	}
+
+	if cw.local != nil {
+		err = cw.local.Invalidate(ctx, keys)
+		if err != nil {
+			log.Error("unable to broadcast local cache invalidation", sl.Err(err), slog.Any("keys", keys))
+		}
+	}
+}
+
+// bannerCacheKeys returns the redis keys of the feature with each of the tags.
+func bannerCacheKeys(featureID int64, tagIDs []int64) []string {
+	keys := make([]string, len(tagIDs))
+	for i, tagID := range tagIDs {
+		keys[i] = CacheKey{featureID, tagID}.ToRedisKeyFormat()
+	}
+
+	return keys
+}
+
+// bannersCacheKeys returns the redis keys of all the provided banners.
+func bannersCacheKeys(bs []*entity.Banner) []string {
+	keys := make([]string, 0, len(bs))
+	for _, b := range bs {
+		keys = append(keys, bannerCacheKeys(b.FeatureID, b.TagIDs)...)
+	}
+
+	return keys
+}
@@ -2,24 +2,42 @@ package banner
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"log/slog"
 	"strconv"
+	"sync"
 	"time"
 
+	"golang.org/x/sync/singleflight"
+
 	"banners-management/internal/cache/redis"
 	"banners-management/internal/lib/logger/sl"
+	"banners-management/internal/lib/metrics"
 	"banners-management/internal/model/entity"
 	"banners-management/internal/storage/repo"
 )
 
 const (
 	// CacheTTL is time for a single redis.CacheItem to be stored in redis cache.
+	// For single banners it is the time the item is considered fresh.
 	CacheTTL = 5 * time.Minute
+	// CacheStaleTTL is time for a single banner to be served from cache after it became stale,
+	// while it's being refreshed in the background.
+	CacheStaleTTL = time.Minute
 
 	cacheSetOpTimeout = 20 * time.Second
+	// cacheVersionTTL is time for the version of a redis key to be stored after it was bumped.
+	// It only has to outlive the reads, that were started before the bump.
+	cacheVersionTTL = time.Hour
 )
 
+// listVersionKey is the key of the version shared by all the lists, since they're all evicted at once.
+// The version is a part of ListCacheKey, so the lists cached before the eviction are never read after it.
+var listVersionKey = redis.VersionKey("list")
+
 // CacheKey is a composite redis key.
 type CacheKey struct {
 	featureID, tagID int64
@@ -30,39 +48,101 @@ func (ck CacheKey) ToRedisKeyFormat() string {
 	return strconv.FormatInt(ck.featureID, 10) + ":" + strconv.FormatInt(ck.tagID, 10)
 }
 
+// ListCacheKey is a composite redis key for a list of banners by the query and the version of the lists.
+type ListCacheKey struct {
+	q       repo.BannerQuery
+	version int64
+}
+
+// ToRedisKeyFormat returns a string that can be used as redis key.
+// The query has too many parameters, including the free-form search, to be written to the key as is,
+// so the key contains the hash of the query.
+func (lck ListCacheKey) ToRedisKeyFormat() string {
+	data, _ := json.Marshal(lck.q) //nolint:errchkjson // the query is always encodable
+	sum := sha256.Sum256(data)
+
+	return "list:" + strconv.FormatInt(lck.version, 10) + ":" + hex.EncodeToString(sum[:])
+}
+
 // CacheReader is a decorator for repo.BannerReader that caches all recent read results in redis cache.
+// Single banners are also cached in the optional in-process LocalCache in front of redis.
+// Concurrent cache misses for the same banner are collapsed into a single read from repo.BannerReader,
+// and a stale banner is served while a single background refresh for it is running.
 type CacheReader struct {
-	reader repo.BannerReader
-	cache  *redis.Cache
-	logger *slog.Logger
+	reader     repo.BannerReader
+	cache      *redis.Cache
+	local      *LocalCache
+	group      singleflight.Group
+	refreshing sync.Map
+	logger     *slog.Logger
 }
 
-// NewCacheReader returns a new CacheReader instance.
-func NewCacheReader(reader repo.BannerReader, cache *redis.Cache, logger *slog.Logger) *CacheReader {
+// NewCacheReader returns a new CacheReader instance. If local is nil, the in-process cache is not used.
+func NewCacheReader(
+	reader repo.BannerReader,
+	cache *redis.Cache,
+	local *LocalCache,
+	logger *slog.Logger,
+) *CacheReader {
 	return &CacheReader{
 		reader: reader,
 		cache:  cache,
+		local:  local,
 		logger: logger,
 	}
 }
 
-// BannersByFeatureTag does nothing and just proxies the request to the decorated repo.BannerReader.
-func (cbr *CacheReader) BannersByFeatureTag(
+// Banners checks if requested list of the current lists version is stored in redis, and if not,
+// returns a request result from decorated repo.BannerReader and asynchronously updates cache.
+// If useLastRevision is nil or true, or the version can't be read, the request is just proxied
+// to the decorated repo.BannerReader.
+func (cbr *CacheReader) Banners(
 	ctx context.Context,
-	featureID, tagID *int64,
-	limit, offset *int,
+	q repo.BannerQuery,
 	useLastRevision *bool,
-) ([]*entity.Banner, error) {
-	return cbr.reader.BannersByFeatureTag(ctx, featureID, tagID, limit, offset, useLastRevision)
+) (*repo.BannerPage, repo.Revision, error) {
+	const comp = "service.banner.cached_banner.Banners"
+	log := cbr.logger.With(slog.String("comp", comp))
+	if useLastRevision == nil || *useLastRevision {
+		return cbr.reader.Banners(ctx, q, useLastRevision)
+	}
+
+	versions := cbr.versions(ctx, log, listVersionKey)
+	if versions == nil {
+		metrics.CacheLookups.WithLabelValues(metrics.CacheList, metrics.CacheMiss).Inc()
+		return cbr.reader.Banners(ctx, q, useLastRevision)
+	}
+
+	key := ListCacheKey{q, versions[0]}.ToRedisKeyFormat()
+	v, err := redis.Get[*repo.BannerPage](cbr.cache, ctx, key)
+	if err != nil {
+		log.Error("redis cache get error", sl.Err(err), slog.String("key", key))
+	} else if v.Status == redis.StatusExists {
+		metrics.CacheLookups.WithLabelValues(metrics.CacheList, metrics.CacheHit).Inc()
+		return v.Value, repo.Revision{Cached: true, FetchedAt: v.CreatedAt}, nil
+	}
+
+	metrics.CacheLookups.WithLabelValues(metrics.CacheList, metrics.CacheMiss).Inc()
+	page, rev, err := cbr.reader.Banners(ctx, q, useLastRevision)
+	if err != nil {
+		return page, rev, err
+	}
+	item := redis.NewCacheItem(page, redis.StatusExists)
+	setAsync(cbr.cache, key, listVersionKey, versions[0], item, CacheTTL, log)
+
+	return page, rev, nil
 }
 
-// BannerByFeatureTag checks if requested data is stored in redis, and if not,
+// BannerByFeatureTag checks if requested data is stored in the in-process cache or in redis, and if not,
 // returns a request result from decorated repo.BannerReader and asynchronously updates cache.
+// If the cached data is stale, it is returned anyway and refreshed in the background.
+// The data read before the key was evicted by CacheWriter is never cached after the eviction,
+// see redis.SetIfVersion and LocalCache.Set.
 func (cbr *CacheReader) BannerByFeatureTag(
 	ctx context.Context,
 	featureID, tagID int64,
 	useLastRevision bool,
-) (*entity.Banner, error) {
+) (*entity.Banner, repo.Revision, error) {
 	const comp = "service.banner.cached_banner.BannerByFeatureTag"
 	log := cbr.logger.With(slog.String("comp", comp))
 	if useLastRevision {
@@ -70,52 +150,302 @@ func (cbr *CacheReader) BannerByFeatureTag(
 	}
 
 	key := CacheKey{featureID, tagID}.ToRedisKeyFormat()
+	if cbr.local != nil {
+		if v, ok := cbr.local.Get(key); ok {
+			return cbr.cachedBanner(key, featureID, tagID, v)
+		}
+	}
+
+	gen := cbr.localGeneration()
 	v, err := redis.Get[*entity.Banner](cbr.cache, ctx, key)
 	if err != nil {
 		log.Error("redis cache get error", sl.Err(err), slog.String("key", key))
-		return cbr.getDataUpdateCache(ctx, featureID, tagID, useLastRevision)
+		metrics.CacheLookups.WithLabelValues(metrics.CacheBanner, metrics.CacheMiss).Inc()
+		return cbr.getDataUpdateCache(ctx, featureID, tagID)
+	}
+
+	if v.Status == redis.StatusNotFound {
+		metrics.CacheLookups.WithLabelValues(metrics.CacheBanner, metrics.CacheMiss).Inc()
+		return cbr.getDataUpdateCache(ctx, featureID, tagID)
+	}
+	cbr.setLocal(key, v, gen)
+
+	return cbr.cachedBanner(key, featureID, tagID, v)
+}
+
+// cachedBanner returns the banner stored in the cache item along with its revision.
+// If the item is negatively cached, repo.ErrBannerNotFound is returned.
+// If the item is stale, its background refresh is started.
+func (cbr *CacheReader) cachedBanner(
+	key string,
+	featureID, tagID int64,
+	v *redis.CacheItem[*entity.Banner],
+) (*entity.Banner, repo.Revision, error) {
+	if time.Since(v.CreatedAt) > CacheTTL {
+		cbr.refreshAsync(key, featureID, tagID)
 	}
 
-	switch v.Status {
-	case redis.StatusExists:
-		return v.Value, nil
-	case redis.StatusNotFound:
-		return cbr.getDataUpdateCache(ctx, featureID, tagID, useLastRevision)
-	case redis.StatusNotExists:
-		return nil, repo.ErrBannerNotFound
+	rev := repo.Revision{Cached: true, FetchedAt: v.CreatedAt}
+	if v.Status == redis.StatusNotExists {
+		metrics.CacheLookups.WithLabelValues(metrics.CacheBanner, metrics.CacheNegativeHit).Inc()
+		return nil, rev, repo.ErrBannerNotFound
 	}
 
-	return v.Value, nil
+	metrics.CacheLookups.WithLabelValues(metrics.CacheBanner, metrics.CacheHit).Inc()
+	return v.Value, rev, nil
+}
+
+// BannersByFeatureTags looks the requested banners up in the in-process cache, then the rest of them in redis
+// with a single MGET, and reads the remaining ones from the decorated repo.BannerReader with a single request,
+// asynchronously updating cache with them. Stale banners are returned anyway and refreshed in the background.
+// The returned revision is the one of the oldest banner.
+func (cbr *CacheReader) BannersByFeatureTags(
+	ctx context.Context,
+	keys []repo.FeatureTag,
+	useLastRevision bool,
+) ([]*entity.Banner, repo.Revision, error) {
+	const comp = "service.banner.cached_banner.BannersByFeatureTags"
+	log := cbr.logger.With(slog.String("comp", comp))
+	if useLastRevision {
+		return cbr.reader.BannersByFeatureTags(ctx, keys, useLastRevision)
+	}
+
+	gen := cbr.localGeneration()
+	redisKeys := make([]string, len(keys))
+	items := make([]*redis.CacheItem[*entity.Banner], len(keys))
+	missed := make([]int, 0, len(keys))
+	for i, k := range keys {
+		redisKeys[i] = CacheKey{k.FeatureID, k.TagID}.ToRedisKeyFormat()
+		if cbr.local != nil {
+			if v, ok := cbr.local.Get(redisKeys[i]); ok {
+				items[i] = v
+				continue
+			}
+		}
+		missed = append(missed, i)
+	}
+
+	if len(missed) > 0 {
+		missedKeys := make([]string, len(missed))
+		for j, i := range missed {
+			missedKeys[j] = redisKeys[i]
+		}
+		vs, err := redis.MGet[*entity.Banner](cbr.cache, ctx, missedKeys...)
+		if err != nil {
+			log.Error("redis cache mget error", sl.Err(err))
+		}
+		for j, v := range vs {
+			if v.Status == redis.StatusNotFound {
+				continue
+			}
+			items[missed[j]] = v
+			cbr.setLocal(redisKeys[missed[j]], v, gen)
+		}
+	}
+
+	rev := repo.Revision{FetchedAt: time.Now()}
+	fetchKeys := make([]repo.FeatureTag, 0, len(missed))
+	fetchIdx := make([]int, 0, len(missed))
+	for i, item := range items {
+		if item == nil {
+			metrics.CacheLookups.WithLabelValues(metrics.CacheBanner, metrics.CacheMiss).Inc()
+			fetchKeys = append(fetchKeys, keys[i])
+			fetchIdx = append(fetchIdx, i)
+			continue
+		}
+
+		if item.Status == redis.StatusNotExists {
+			metrics.CacheLookups.WithLabelValues(metrics.CacheBanner, metrics.CacheNegativeHit).Inc()
+		} else {
+			metrics.CacheLookups.WithLabelValues(metrics.CacheBanner, metrics.CacheHit).Inc()
+		}
+		rev.Cached = true
+		rev.FetchedAt = minTime(rev.FetchedAt, item.CreatedAt)
+		if time.Since(item.CreatedAt) > CacheTTL {
+			cbr.refreshAsync(redisKeys[i], keys[i].FeatureID, keys[i].TagID)
+		}
+	}
+
+	if len(fetchKeys) > 0 {
+		versionKeys := make([]string, len(fetchIdx))
+		for j, i := range fetchIdx {
+			versionKeys[j] = redis.VersionKey(redisKeys[i])
+		}
+		versions := cbr.versions(ctx, log, versionKeys...)
+		bs, fetchRev, err := cbr.reader.BannersByFeatureTags(ctx, fetchKeys, false)
+		if err != nil {
+			return nil, fetchRev, err
+		}
+		rev.FetchedAt = minTime(rev.FetchedAt, fetchRev.FetchedAt)
+		for j, b := range bs {
+			status := redis.StatusExists
+			if b == nil {
+				status = redis.StatusNotExists
+			}
+			item := redis.NewCacheItem(b, status)
+			i := fetchIdx[j]
+			items[i] = item
+			cbr.setLocal(redisKeys[i], item, gen)
+			if versions != nil {
+				exp := windowExpiration(b, CacheTTL+CacheStaleTTL)
+				setAsync(cbr.cache, redisKeys[i], versionKeys[j], versions[j], item, exp, log)
+			}
+		}
+	}
+
+	banners := make([]*entity.Banner, len(keys))
+	for i, item := range items {
+		if item.Status == redis.StatusExists {
+			banners[i] = item.Value
+		}
+	}
+
+	return banners, rev, nil
+}
+
+// minTime returns the earliest of a and b.
+func minTime(a, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+
+	return a
+}
+
+// fetchResult is a result of reading a banner from the decorated repo.BannerReader.
+type fetchResult struct {
+	banner *entity.Banner
+	rev    repo.Revision
+	err    error
 }
 
 // getDataUpdateCache retrieves data from the original repo.BannerReader and
 // asynchronously updates cache with this data.
+// Concurrent calls for the same featureID and tagID share a single read.
 func (cbr *CacheReader) getDataUpdateCache(
 	ctx context.Context,
 	featureID, tagID int64,
-	useLastRevision bool,
-) (*entity.Banner, error) {
-	const comp = "service.banner.cached_banner.getDataUpdateCache"
+) (*entity.Banner, repo.Revision, error) {
+	key := CacheKey{featureID, tagID}.ToRedisKeyFormat()
+	// the read is shared between all the callers, so it must not be canceled by the first one
+	ctx = context.WithoutCancel(ctx)
+	res, _, _ := cbr.group.Do(key, func() (any, error) {
+		return cbr.fetchUpdateCache(ctx, key, featureID, tagID), nil
+	})
+
+	r, _ := res.(*fetchResult)
+	return r.banner, r.rev, r.err
+}
+
+// refreshAsync asynchronously retrieves data from the original repo.BannerReader and updates cache with it.
+// If the refresh of the key is already running, it does nothing.
+func (cbr *CacheReader) refreshAsync(key string, featureID, tagID int64) {
+	if _, running := cbr.refreshing.LoadOrStore(key, struct{}{}); running {
+		return
+	}
+
+	go func() {
+		defer cbr.refreshing.Delete(key)
+		ctx, cancel := context.WithTimeout(context.Background(), cacheSetOpTimeout)
+		defer cancel()
+		_, _, _ = cbr.group.Do(key, func() (any, error) {
+			return cbr.fetchUpdateCache(ctx, key, featureID, tagID), nil
+		})
+	}()
+}
+
+// fetchUpdateCache retrieves data from the original repo.BannerReader and updates the in-process cache
+// and asynchronously redis cache with this data.
+func (cbr *CacheReader) fetchUpdateCache(ctx context.Context, key string, featureID, tagID int64) *fetchResult {
+	const comp = "service.banner.cached_banner.fetchUpdateCache"
 	log := cbr.logger.With(slog.String("comp", comp))
 	status := redis.StatusExists
-	v, err := cbr.reader.BannerByFeatureTag(ctx, featureID, tagID, useLastRevision)
+	gen := cbr.localGeneration()
+	versionKey := redis.VersionKey(key)
+	versions := cbr.versions(ctx, log, versionKey)
+	v, rev, err := cbr.reader.BannerByFeatureTag(ctx, featureID, tagID, false)
 	if err != nil {
 		if errors.Is(err, repo.ErrBannerNotFound) {
 			status = redis.StatusNotExists
 		} else {
-			return v, err
+			log.Error("failed to read banner", sl.Err(err), slog.String("key", key))
+			return &fetchResult{v, rev, err}
 		}
 	}
 	item := redis.NewCacheItem(v, status)
-	key := CacheKey{featureID, tagID}.ToRedisKeyFormat()
+	cbr.setLocal(key, item, gen)
+	if versions != nil {
+		setAsync(cbr.cache, key, versionKey, versions[0], item, windowExpiration(v, CacheTTL+CacheStaleTTL), log)
+	}
+
+	return &fetchResult{v, rev, err}
+}
+
+// versions returns the current versions of the redis keys by the version keys, that must be taken
+// before the data is read for the cache. If they can't be read, nil is returned, and the data must not be cached.
+func (cbr *CacheReader) versions(ctx context.Context, log *slog.Logger, versionKeys ...string) []int64 {
+	versions, err := cbr.cache.Versions(ctx, versionKeys...)
+	if err != nil {
+		log.Error("redis cache versions error", sl.Err(err))
+		return nil
+	}
+
+	return versions
+}
+
+// localGeneration returns the current generation of the in-process cache, if it's used.
+// It must be taken before the data is read for the in-process cache.
+func (cbr *CacheReader) localGeneration() uint64 {
+	if cbr.local == nil {
+		return 0
+	}
+
+	return cbr.local.Generation()
+}
+
+// setLocal stores the item in the in-process cache, if it's used and wasn't invalidated since gen.
+func (cbr *CacheReader) setLocal(key string, item *redis.CacheItem[*entity.Banner], gen uint64) {
+	if cbr.local != nil {
+		cbr.local.Set(key, item, gen)
+	}
+}
+
+// windowExpiration returns exp, shortened so that the cached banner b expires when its activation window,
+// or the window of any other banner sharing its feature and tag, is changed next time,
+// since another banner may be resolved then. b may be nil.
+func windowExpiration(b *entity.Banner, exp time.Duration) time.Duration {
+	if b == nil {
+		return exp
+	}
+	if next, ok := b.NextWindowChange(time.Now()); ok {
+		exp = min(exp, time.Until(next))
+	}
+	if b.NextResolutionChange != nil {
+		exp = min(exp, time.Until(*b.NextResolutionChange))
+	}
+
+	return exp
+}
+
+// setAsync asynchronously sets the item in redis cache c by the provided key for the exp duration,
+// if the version by versionKey is still the one taken before the item was read.
+// Note: it is not a method of CacheReader, because methods can't be generic.
+func setAsync[T any](
+	c *redis.Cache,
+	key, versionKey string,
+	version int64,
+	item *redis.CacheItem[T],
+	exp time.Duration,
+	log *slog.Logger,
+) {
 	go func() {
 		ctx, cancel := context.WithTimeout(context.Background(), cacheSetOpTimeout)
 		defer cancel()
-		err = redis.Set(cbr.cache, ctx, key, item, CacheTTL)
+		set, err := redis.SetIfVersion(c, ctx, key, versionKey, version, item, exp)
 		if err != nil {
 			log.Error("redis cache set error", sl.Err(err), slog.String("key", key))
+		} else if !set {
+			log.Debug("key evicted while being read, not cached", slog.String("key", key))
 		}
 	}()
-
-	return v, err
 }
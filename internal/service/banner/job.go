@@ -0,0 +1,26 @@
+package banner
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"banners-management/internal/lib/logger/sl"
+	"banners-management/internal/model/entity"
+	"banners-management/internal/storage/repo"
+)
+
+// Job returns the state of the deferred job with the given id.
+// If there is no such job, ErrJobNotFound is returned.
+func (s *Service) Job(ctx context.Context, id string) (*entity.Job, error) {
+	job, err := s.jobs.Job(ctx, id)
+	if errors.Is(err, repo.ErrJobNotFound) {
+		s.logger.Info("job not found", slog.String("jobID", id))
+		return nil, ErrJobNotFound
+	} else if err != nil {
+		s.logger.Error("unable to get job", slog.String("jobID", id), sl.Err(err))
+		return nil, ErrUnknown
+	}
+
+	return job, nil
+}
@@ -3,97 +3,431 @@ package banner
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log/slog"
+	"os"
+	"slices"
+	"time"
 
-	goredis "github.com/redis/go-redis/v9"
+	"github.com/google/uuid"
 
 	"banners-management/internal/cache/redis"
+	"banners-management/internal/lib/api"
 	"banners-management/internal/lib/logger/sl"
+	"banners-management/internal/lib/metrics"
+	"banners-management/internal/model/entity"
 	"banners-management/internal/storage/repo"
 )
 
 const (
-	RedisBannerDeleterByFeatureTagChannelName = "redis_deleter_job_delayer"
+	RedisBannerDeleterByFeatureTagStreamName = "banner_deleter_jobs"
+	RedisBannerDeleterGroupName              = "banner_deleter"
+	RedisBannerDeleterDeadLetterListName     = "banner_deleter_jobs:dead"
+
+	streamReadCount    = 16
+	streamReadBlock    = 2 * time.Second
+	streamPendingCount = 64
+	streamErrorDelay   = time.Second
+
+	// JobTTL is time for a job state to be stored in redis after its last update.
+	JobTTL = 24 * time.Hour
 )
 
-// redisDeleteMessage is a dto for a RedisChannelDeleter.
+// RetryPolicy describes how the failed deferred jobs are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts to execute a job before it's moved to the dead-letter list.
+	MaxAttempts int
+	// Backoff is the delay before the first retry. Every next delay is doubled.
+	Backoff time.Duration
+	// MaxBackoff is the maximum delay between the retries.
+	MaxBackoff time.Duration
+}
+
+// delay returns the delay before the next attempt of the job that was already attempted the given number of times.
+func (p RetryPolicy) delay(attempts int64) time.Duration {
+	d := p.Backoff
+	for i := int64(1); i < attempts && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+
+	return min(d, p.MaxBackoff)
+}
+
+// redisDeleteMessage is a dto for a RedisStreamDeleter.
+// Nil FeatureID or TagID means that the banners are deleted regardless of it.
+// Actor, ActorRole and RequestID belong to the request the deletion was scheduled by,
+// and are recorded to the audit log along with the deleted banners.
 type redisDeleteMessage struct {
-	FeatureID int64 `json:"feature_id"`
-	TagID     int64 `json:"tag_id"`
+	JobID     string `json:"job_id"`
+	FeatureID *int64 `json:"feature_id"`
+	TagID     *int64 `json:"tag_id"`
+	Actor     string `json:"actor,omitempty"`
+	ActorRole string `json:"actor_role,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// jobKey returns the redis key the state of the job with the given id is stored by.
+func jobKey(id string) string {
+	return "job:" + id
+}
+
+// deadLetter is a job that couldn't be executed and was moved to the dead-letter list.
+type deadLetter struct {
+	ID       string          `json:"id"`
+	Payload  json.RawMessage `json:"payload"`
+	Attempts int64           `json:"attempts"`
+	DiedAt   time.Time       `json:"died_at"`
 }
 
-// RedisChannelDeleter is a decorator for repo.BannerDeleter that allows an asynchronous operation executing.
-type RedisChannelDeleter struct {
-	cache   *redis.Cache
-	deleter repo.BannerDeleter
-	logger  *slog.Logger
+// RedisStreamDeleter implements repo.BannerJobs by executing the operations of the provided repo.BannerDeleter
+// asynchronously. The operations are stored in the redis stream and executed by a consumer group,
+// so they survive app restarts, and each of them is executed by only one app instance.
+// A failed operation is retried with exponential backoff and after RetryPolicy.MaxAttempts attempts
+// it's moved to the dead-letter list. The state of every operation is tracked in redis for JobTTL,
+// and every banner it affects is recorded with the provided repo.AuditSaver.
+type RedisStreamDeleter struct {
+	cache    *redis.Cache
+	deleter  repo.BannerDeleter
+	audit    repo.AuditSaver
+	policy   RetryPolicy
+	consumer string
+	logger   *slog.Logger
 }
 
-// NewRedisChannelDeleter returns a new RedisChannelDeleter instance.
-func NewRedisChannelDeleter(
+// NewRedisStreamDeleter returns a new RedisStreamDeleter instance.
+// It ensures that the consumer group exists and starts consuming the stream until ctx is done.
+func NewRedisStreamDeleter(
 	ctx context.Context,
-	redis *redis.Cache,
+	cache *redis.Cache,
 	deleter repo.BannerDeleter,
+	audit repo.AuditSaver,
+	policy RetryPolicy,
 	logger *slog.Logger,
-) *RedisChannelDeleter {
-	res := &RedisChannelDeleter{
-		cache:   redis,
-		deleter: deleter,
-		logger:  logger,
+) (*RedisStreamDeleter, error) {
+	err := cache.CreateGroup(ctx, RedisBannerDeleterByFeatureTagStreamName, RedisBannerDeleterGroupName)
+	if err != nil {
+		return nil, err
 	}
 
-	go res.runDeleterDaemon(ctx, res.cache.Subscribe(ctx, RedisBannerDeleterByFeatureTagChannelName))
+	hostname, _ := os.Hostname()
+	res := &RedisStreamDeleter{
+		cache:    cache,
+		deleter:  deleter,
+		audit:    audit,
+		policy:   policy,
+		consumer: hostname + "-" + uuid.NewString(),
+		logger:   logger.With(slog.String("comp", "service.banner.job_delayer")),
+	}
 
-	return res
-}
+	go res.runDeleterDaemon(ctx)
 
-// DeleteBanner does nothing and just proxies the request to the decorated repo.BannerDeleter.
-func (r *RedisChannelDeleter) DeleteBanner(ctx context.Context, bannerID int64) error {
-	return r.deleter.DeleteBanner(ctx, bannerID)
+	return res, nil
 }
 
-// DeleteByFeatureTag asynchronously executes operation of banner deletion by featureID and tagID.
-func (r *RedisChannelDeleter) DeleteByFeatureTag(ctx context.Context, featureID, tagID int64) error {
-	const comp = "service.banner.job_delayer"
+// ScheduleDelete schedules asynchronous operation of banner deletion by featureID and/or tagID.
+// If only featureID is provided, all the banners of the feature are deleted. If only tagID is provided,
+// the tag is removed from all the banners, and the banners left with no tags are deleted.
+// It returns the ID of the job that can be used to track the operation state.
+// The affected banners are recorded to the audit log on behalf of the caller ctx belongs to.
+func (r *RedisStreamDeleter) ScheduleDelete(ctx context.Context, featureID, tagID *int64) (string, error) {
+	const comp = "service.banner.job_delayer.ScheduleDelete"
+	job := entity.NewJob(uuid.NewString())
+	// the state is saved before the message is added, so the consumer always finds it
+	err := r.saveJob(ctx, job)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", comp, err)
+	}
+
+	message := redisDeleteMessage{
+		JobID:     job.ID,
+		FeatureID: featureID,
+		TagID:     tagID,
+		Actor:     api.SubjectFromContext(ctx),
+		ActorRole: api.RoleFromContext(ctx),
+		RequestID: api.RequestIDFromContext(ctx),
+	}
+	_, err = r.cache.StreamAdd(ctx, RedisBannerDeleterByFeatureTagStreamName, message)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", comp, err)
+	}
+	metrics.DeleteJobs.WithLabelValues(metrics.JobScheduled).Inc()
+
+	return job.ID, nil
+}
 
-	message := redisDeleteMessage{featureID, tagID}
-	err := r.cache.Publish(ctx, RedisBannerDeleterByFeatureTagChannelName, message)
+// Job returns the state of the job with the given id.
+// If there is no such job or its state has already expired, repo.ErrJobNotFound is returned.
+func (r *RedisStreamDeleter) Job(ctx context.Context, id string) (*entity.Job, error) {
+	v, err := redis.Get[*entity.Job](r.cache, ctx, jobKey(id))
 	if err != nil {
-		r.logger.Error("error publishing message to redis", slog.String("comp", comp), sl.Err(err))
-		return ErrUnknown
+		return nil, fmt.Errorf("service.banner.job_delayer.Job: %w", err)
+	}
+	if v.Status != redis.StatusExists || v.Value == nil {
+		return nil, repo.ErrJobNotFound
 	}
 
-	return nil
+	return v.Value, nil
 }
 
-// runDeleterDaemon reads channel ch and executes all the operations of banner deletion, received from this channel.
-func (r *RedisChannelDeleter) runDeleterDaemon(ctx context.Context, ch <-chan *goredis.Message) {
-	for m := range ch {
-		if m.Channel != RedisBannerDeleterByFeatureTagChannelName {
+// runDeleterDaemon reads the stream and executes all the operations of banner deletion, received from it,
+// retrying the failed ones, until ctx is done.
+func (r *RedisStreamDeleter) runDeleterDaemon(ctx context.Context) {
+	for ctx.Err() == nil {
+		r.retryPending(ctx)
+
+		messages, err := r.cache.ReadGroup(ctx,
+			RedisBannerDeleterByFeatureTagStreamName,
+			RedisBannerDeleterGroupName,
+			r.consumer,
+			streamReadCount,
+			streamReadBlock,
+		)
+		if err != nil {
+			if ctx.Err() == nil {
+				r.logger.Error("unable to read redis stream", sl.Err(err))
+				time.Sleep(streamErrorDelay)
+			}
 			continue
 		}
 
-		go func() {
-			r.handleReceivedPayload(ctx, []byte(m.Payload))
-		}()
+		for _, m := range messages {
+			r.handleReceivedMessage(ctx, m, 1)
+		}
+	}
+}
+
+// retryPending claims and executes again the failed operations, whose backoff delay has passed,
+// including the ones that were delivered to the app instances that died before acknowledging them.
+// The operations that were attempted RetryPolicy.MaxAttempts times are moved to the dead-letter list.
+func (r *RedisStreamDeleter) retryPending(ctx context.Context) {
+	pending, err := r.cache.Pending(ctx,
+		RedisBannerDeleterByFeatureTagStreamName,
+		RedisBannerDeleterGroupName,
+		streamPendingCount,
+	)
+	if err != nil {
+		if ctx.Err() == nil {
+			r.logger.Error("unable to read pending messages", sl.Err(err))
+		}
+		return
+	}
+
+	for _, p := range pending {
+		minIdle := r.policy.delay(p.Deliveries)
+		if p.Idle < minIdle {
+			continue
+		}
+
+		// claiming with minIdle guarantees that only one app instance gets the message
+		claimed, err := r.cache.Claim(ctx,
+			RedisBannerDeleterByFeatureTagStreamName,
+			RedisBannerDeleterGroupName,
+			r.consumer,
+			minIdle,
+			p.ID,
+		)
+		if err != nil {
+			r.logger.Error("unable to claim pending message", sl.Err(err), slog.String("id", p.ID))
+			continue
+		}
+
+		for _, m := range claimed {
+			if p.Deliveries >= int64(r.policy.MaxAttempts) {
+				r.deadLetter(ctx, m, p.Deliveries)
+				continue
+			}
+			// the claim above counts as one more delivery
+			r.handleReceivedMessage(ctx, m, p.Deliveries+1)
+		}
+	}
+}
+
+// handleReceivedMessage handles the message received from the redis stream for the attempt-th time.
+// The message is acknowledged if the operation succeeded or can't succeed at all,
+// otherwise it stays pending and is retried later.
+func (r *RedisStreamDeleter) handleReceivedMessage(ctx context.Context, m redis.StreamMessage, attempt int64) {
+	log := r.logger.With(slog.String("id", m.ID))
+
+	res := new(redisDeleteMessage)
+	err := json.Unmarshal(m.Payload, res)
+	if err != nil {
+		log.Error("unable to parse payload", sl.Err(err))
+		r.deadLetter(ctx, m, attempt)
+		return
+	}
+	if res.FeatureID == nil && res.TagID == nil {
+		log.Error("neither feature nor tag is provided")
+		r.deadLetter(ctx, m, attempt)
+		return
+	}
+	log = log.With(
+		slog.String("jobID", res.JobID),
+		sl.OptInt64("featureID", res.FeatureID),
+		sl.OptInt64("tagID", res.TagID),
+	)
+
+	r.updateJob(ctx, res.JobID, func(j *entity.Job) {
+		now := time.Now()
+		j.Status = entity.JobRunning
+		j.Attempts = attempt
+		j.StartedAt = &now
+	})
+
+	affected, err := r.delete(ctx, res.FeatureID, res.TagID)
+	if err != nil {
+		log.Error("unable to delete banners, will retry", sl.Err(err))
+		metrics.DeleteJobs.WithLabelValues(metrics.JobRetried).Inc()
+		r.updateJob(ctx, res.JobID, func(j *entity.Job) {
+			j.Status = entity.JobPending
+			j.Error = err.Error()
+		})
+		return
+	}
+
+	log.Info("banners deleted", slog.Int("affected", len(affected)))
+	metrics.DeleteJobs.WithLabelValues(metrics.JobSucceeded).Inc()
+	r.recordAffected(ctx, res, affected)
+	r.finishJob(ctx, res.JobID, entity.JobSucceeded, int64(len(affected)), nil)
+	r.ack(ctx, m.ID)
+}
+
+// delete executes the deletion with the decorated repo.BannerDeleter
+// and returns the states of the affected banners before it. At least one of featureID and tagID must be provided.
+func (r *RedisStreamDeleter) delete(ctx context.Context, featureID, tagID *int64) ([]*entity.Banner, error) {
+	switch {
+	case featureID != nil && tagID != nil:
+		return r.deleter.DeleteByFeatureTag(ctx, *featureID, *tagID)
+	case featureID != nil:
+		return r.deleter.DeleteByFeature(ctx, *featureID)
+	default:
+		return r.deleter.DeleteByTag(ctx, *tagID)
+	}
+}
+
+// recordAffected saves the audit events of the banners affected by the deletion from the message:
+// the deletion of every deleted banner, and the update of every banner that only lost the tag.
+// The deletion is already done, so a failure to record an event is only logged.
+func (r *RedisStreamDeleter) recordAffected(ctx context.Context, m *redisDeleteMessage, affected []*entity.Banner) {
+	var jobID *string
+	if m.JobID != "" {
+		jobID = &m.JobID
+	}
+
+	for _, before := range affected {
+		action, after := entity.AuditBannerDelete, (*entity.Banner)(nil)
+		if m.FeatureID == nil && len(before.TagIDs) > 1 {
+			// the banner with other tags is not deleted by the tag, see repo.BannerDeleter
+			b := *before
+			b.TagIDs = slices.DeleteFunc(slices.Clone(before.TagIDs), func(id int64) bool { return id == *m.TagID })
+			action, after = entity.AuditBannerUpdate, &b
+		}
+
+		_, err := r.audit.SaveAuditEvent(ctx, &entity.AuditEvent{
+			Action:    action,
+			Actor:     m.Actor,
+			ActorRole: m.ActorRole,
+			RequestID: m.RequestID,
+			BannerID:  &before.ID,
+			JobID:     jobID,
+			Diff:      entity.BannerDiff(before, after),
+			CreatedAt: time.Now(),
+		})
+		if err != nil {
+			r.logger.Error("failed to record audit event",
+				sl.Err(err),
+				slog.String("action", action),
+				slog.Int64("bannerID", before.ID),
+				slog.String("jobID", m.JobID),
+			)
+		}
 	}
 }
 
-// handleReceivedPayload handles the received payload from redis channel.
-func (r *RedisChannelDeleter) handleReceivedPayload(ctx context.Context, payload []byte) {
+// deadLetter moves the message to the dead-letter list and acknowledges it.
+func (r *RedisStreamDeleter) deadLetter(ctx context.Context, m redis.StreamMessage, attempts int64) {
+	log := r.logger.With(slog.String("id", m.ID))
+	log.Error("moving message to the dead-letter list", slog.Int64("attempts", attempts))
+	metrics.DeleteJobs.WithLabelValues(metrics.JobDeadLettered).Inc()
+
+	payload := json.RawMessage(m.Payload)
+	if !json.Valid(payload) {
+		payload, _ = json.Marshal(string(m.Payload))
+	}
+	err := r.cache.ListPush(ctx, RedisBannerDeleterDeadLetterListName, deadLetter{
+		ID:       m.ID,
+		Payload:  payload,
+		Attempts: attempts,
+		DiedAt:   time.Now(),
+	})
+	if err != nil {
+		log.Error("unable to push message to the dead-letter list", sl.Err(err))
+		return
+	}
+
 	res := new(redisDeleteMessage)
-	err := json.Unmarshal(payload, res)
+	if json.Unmarshal(m.Payload, res) == nil {
+		r.finishJob(ctx, res.JobID, entity.JobFailed, 0, errors.New("attempts limit exceeded"))
+	}
+
+	r.ack(ctx, m.ID)
+}
+
+// ack acknowledges the message with the given id and removes it from the stream.
+func (r *RedisStreamDeleter) ack(ctx context.Context, id string) {
+	err := r.cache.Ack(ctx, RedisBannerDeleterByFeatureTagStreamName, RedisBannerDeleterGroupName, id)
 	if err != nil {
-		r.logger.Error("unable to parse payload", sl.Err(err))
+		r.logger.Error("unable to acknowledge message", sl.Err(err), slog.String("id", id))
+	}
+}
+
+// saveJob stores the state of the job in redis for JobTTL.
+func (r *RedisStreamDeleter) saveJob(ctx context.Context, job *entity.Job) error {
+	return redis.Set(r.cache, ctx, jobKey(job.ID), redis.NewCacheItem(job, redis.StatusExists), JobTTL)
+}
+
+// updateJob applies the update to the stored state of the job with the given id.
+// Errors are only logged, because the state tracking must not affect the operation itself.
+// The messages without job id, e.g. the ones added before the tracking was introduced, are ignored.
+func (r *RedisStreamDeleter) updateJob(ctx context.Context, id string, update func(j *entity.Job)) {
+	if id == "" {
+		return
+	}
+	log := r.logger.With(slog.String("jobID", id))
+
+	job, err := r.Job(ctx, id)
+	if errors.Is(err, repo.ErrJobNotFound) {
+		// the state has expired, so it's recreated from scratch
+		job = entity.NewJob(id)
+	} else if err != nil {
+		log.Error("unable to get job state", sl.Err(err))
 		return
 	}
 
-	err = r.deleter.DeleteByFeatureTag(ctx, res.FeatureID, res.TagID)
+	update(job)
+	err = r.saveJob(ctx, job)
 	if err != nil {
-		r.logger.Error("unable to delete banner by feature & tag",
-			slog.Int64("featureID", res.FeatureID),
-			slog.Int64("tagID", res.TagID),
-			sl.Err(err),
-		)
+		log.Error("unable to save job state", sl.Err(err))
 	}
 }
+
+// finishJob sets the final status and the number of affected banners of the job with the given id.
+// If cause is not nil, it's saved as the job error.
+func (r *RedisStreamDeleter) finishJob(
+	ctx context.Context,
+	id string,
+	status entity.JobStatus,
+	affected int64,
+	cause error,
+) {
+	r.updateJob(ctx, id, func(j *entity.Job) {
+		now := time.Now()
+		j.Status = status
+		j.Affected = affected
+		j.FinishedAt = &now
+		j.Error = ""
+		if cause != nil {
+			j.Error = cause.Error()
+		}
+	})
+}
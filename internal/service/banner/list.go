@@ -0,0 +1,139 @@
+package banner
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+
+	"banners-management/internal/lib/logger/sl"
+	"banners-management/internal/model/dto/banner"
+	"banners-management/internal/model/entity"
+	"banners-management/internal/service"
+	"banners-management/internal/storage/repo"
+)
+
+// errInvalidCursor is returned, if the cursor can't be decoded or doesn't match the sorting of the list.
+var errInvalidCursor = service.ValidationError("invalid cursor")
+
+// Page is a page of the list of banners.
+// NextCursor is the cursor of the next page, it's empty if there are no more banners.
+// Total is the number of the banners matching the filters, it's counted only if requested.
+type Page struct {
+	Banners    []*entity.Banner
+	NextCursor string
+	Total      *int64
+}
+
+// Banners returns a page of the list of banners, filtered, sorted and paginated by the dto.
+// If useLastRevision is false, the list may be up to CacheTTL outdated.
+// The returned repo.Revision tells how fresh the list is.
+// If the dto is invalid, a new service.ValidationError is returned.
+func (s *Service) Banners(
+	ctx context.Context,
+	dto banner.ListDTO,
+	useLastRevision *bool,
+) (*Page, repo.Revision, error) {
+	if err := validatr.Struct(dto); err != nil {
+		var validErrs validator.ValidationErrors
+		errors.As(err, &validErrs)
+		s.logger.Info("request validation failed", sl.Err(err))
+		return nil, repo.Revision{}, service.ValidationErr(validErrs)
+	}
+	if emptyRange(dto.CreatedFrom, dto.CreatedTo) {
+		return nil, repo.Revision{}, service.ValidationError("created_to must be after created_from")
+	}
+	if emptyRange(dto.UpdatedFrom, dto.UpdatedTo) {
+		return nil, repo.Revision{}, service.ValidationError("updated_to must be after updated_from")
+	}
+
+	// the tags are normalized, so the same filters share the cache, and duplicates don't break the all match
+	tagIDs := slices.Clone(dto.TagIDs)
+	slices.Sort(tagIDs)
+	tagIDs = slices.Compact(tagIDs)
+	q := repo.BannerQuery{
+		FeatureID:   dto.FeatureID,
+		TagIDs:      tagIDs,
+		AllTags:     dto.TagMatch == "all" && len(tagIDs) > 1,
+		IsActive:    dto.IsActive,
+		CreatedFrom: dto.CreatedFrom,
+		CreatedTo:   dto.CreatedTo,
+		UpdatedFrom: dto.UpdatedFrom,
+		UpdatedTo:   dto.UpdatedTo,
+		Search:      strings.TrimSpace(dto.Q),
+		Limit:       dto.Limit,
+		Offset:      dto.Offset,
+		Sort:        repo.BannerSort(strings.TrimPrefix(dto.Sort, "-")),
+		Desc:        strings.HasPrefix(dto.Sort, "-"),
+		WithTotal:   dto.WithTotal,
+	}
+	if q.Sort == "" {
+		q.Sort = repo.SortByID
+	}
+	if dto.Cursor != "" {
+		after, err := decodeCursor(dto.Cursor)
+		if err != nil || after.Sort != q.Sort || after.Desc != q.Desc {
+			s.logger.Info("invalid cursor", sl.Err(err))
+			return nil, repo.Revision{}, errInvalidCursor
+		}
+		q.After = after
+	}
+
+	page, rev, err := s.reader.Banners(ctx, q, useLastRevision)
+	if err != nil {
+		s.logger.Error("failed to get banners", sl.Err(err))
+		return nil, rev, ErrUnknown
+	}
+
+	res := &Page{Banners: page.Banners, Total: page.Total}
+	if page.Next != nil {
+		res.NextCursor = encodeCursor(page.Next)
+	}
+
+	return res, rev, nil
+}
+
+// emptyRange returns true, if both bounds of the time range are set, and the range contains no moments.
+func emptyRange(from, to *time.Time) bool {
+	return from != nil && to != nil && !from.Before(*to)
+}
+
+// encodeCursor returns the opaque string representation of the cursor c.
+func encodeCursor(c *repo.BannerCursor) string {
+	data, _ := json.Marshal(c) //nolint:errchkjson // the cursor is always encodable
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor returns the cursor, encoded by encodeCursor.
+// The value of the cursor is checked to be of the sort field type, so it can be compared with the field.
+func decodeCursor(s string) (*repo.BannerCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	c := new(repo.BannerCursor)
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, err
+	}
+
+	switch c.Sort {
+	case repo.SortByID:
+		if c.Value != "" {
+			return nil, errInvalidCursor
+		}
+	case repo.SortByCreatedAt, repo.SortByUpdatedAt:
+		if _, err := time.Parse(time.RFC3339Nano, c.Value); err != nil {
+			return nil, err
+		}
+	case repo.SortByTitle:
+	default:
+		return nil, errInvalidCursor
+	}
+
+	return c, nil
+}
@@ -0,0 +1,118 @@
+package banner
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"banners-management/internal/cache/memory"
+	"banners-management/internal/cache/redis"
+	"banners-management/internal/lib/logger/sl"
+	"banners-management/internal/model/entity"
+)
+
+const (
+	RedisCacheInvalidationChannelName = "banner_cache_invalidation"
+)
+
+// LocalCache is an in-process cache tier that sits in front of redis.
+// It keeps the recently read banners in memory, so the hot ones are served without a round-trip to redis.
+// Invalidations are broadcast via redis channel, so all the app instances drop the same keys.
+// Every invalidation changes the generation of the cache, so the items read before it are not stored after it.
+type LocalCache struct {
+	mu     sync.Mutex
+	gen    uint64
+	items  *memory.Cache[string, *redis.CacheItem[*entity.Banner]]
+	ttl    time.Duration
+	cache  *redis.Cache
+	logger *slog.Logger
+}
+
+// NewLocalCache returns a new LocalCache instance, that stores at most size items, each for at most ttl.
+// It subscribes to the invalidation redis channel and drops the received keys until ctx is done.
+func NewLocalCache(
+	ctx context.Context,
+	size int,
+	ttl time.Duration,
+	cache *redis.Cache,
+	logger *slog.Logger,
+) *LocalCache {
+	res := &LocalCache{
+		items:  memory.NewCache[string, *redis.CacheItem[*entity.Banner]](size),
+		ttl:    ttl,
+		cache:  cache,
+		logger: logger,
+	}
+
+	go res.runInvalidationDaemon(res.cache.Subscribe(ctx, RedisCacheInvalidationChannelName))
+
+	return res
+}
+
+// Get returns the item stored by the key and true, or nil and false if there is no such item.
+func (lc *LocalCache) Get(key string) (*redis.CacheItem[*entity.Banner], bool) {
+	return lc.items.Get(key)
+}
+
+// Generation returns the current generation of the cache. It must be taken before the item is read,
+// and then passed to Set.
+func (lc *LocalCache) Generation() uint64 {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	return lc.gen
+}
+
+// Set stores the item by the key, unless the cache was invalidated since the generation gen,
+// so an item read before the invalidation of its key never outlives the invalidation.
+// Any invalidation counts, not only the one of the key, since the keys are not tracked separately.
+// The item is never stored longer than it's stored in redis,
+// so the in-process tier doesn't make the data older than redis does.
+func (lc *LocalCache) Set(key string, item *redis.CacheItem[*entity.Banner], gen uint64) {
+	ttl := windowExpiration(item.Value, min(lc.ttl, CacheTTL+CacheStaleTTL-time.Since(item.CreatedAt)))
+
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	if lc.gen != gen {
+		return
+	}
+	lc.items.Set(key, item, ttl)
+}
+
+// Invalidate drops the keys from the in-process cache of this instance
+// and broadcasts them to the other instances.
+func (lc *LocalCache) Invalidate(ctx context.Context, keys []string) error {
+	lc.delete(keys)
+
+	return lc.cache.Publish(ctx, RedisCacheInvalidationChannelName, keys)
+}
+
+// delete drops the keys and moves the cache to the next generation.
+func (lc *LocalCache) delete(keys []string) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	lc.gen++
+	lc.items.Delete(keys...)
+}
+
+// runInvalidationDaemon reads channel ch and drops all the keys, received from this channel.
+func (lc *LocalCache) runInvalidationDaemon(ch <-chan *goredis.Message) {
+	for m := range ch {
+		if m.Channel != RedisCacheInvalidationChannelName {
+			continue
+		}
+
+		var keys []string
+		err := json.Unmarshal([]byte(m.Payload), &keys)
+		if err != nil {
+			lc.logger.Error("unable to parse invalidation payload", sl.Err(err))
+			continue
+		}
+
+		lc.delete(keys)
+	}
+}
@@ -0,0 +1,132 @@
+package banner
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"banners-management/internal/lib/logger/sl"
+	"banners-management/internal/model/entity"
+	"banners-management/internal/service"
+	"banners-management/internal/storage/repo"
+)
+
+const (
+	// DefaultStatsDays is the number of days, the stats are returned for, if the range is not provided.
+	DefaultStatsDays = 30
+	// MaxStatsDays is the maximum number of days, the stats are returned for at once.
+	MaxStatsDays = 366
+)
+
+// UserBanner returns the banner of the feature for the user with the tag the same way as BannerByFeatureTag does,
+// along with the variant assigned to the user by userID, see entity.Banner.Variant.
+// The variant is nil, if the banner content itself is shown. An impression of the shown content is counted.
+func (s *Service) UserBanner(
+	ctx context.Context,
+	featureID, tagID int64,
+	userID string,
+	useLastRevision bool,
+) (*entity.Banner, *entity.BannerVariant, repo.Revision, error) {
+	b, rev, err := s.BannerByFeatureTag(ctx, featureID, tagID, useLastRevision, true)
+	if err != nil {
+		return nil, nil, rev, err
+	}
+
+	v := b.Variant(userID)
+	st := newStats(b, v)
+	st.Impressions = 1
+	s.countStats(ctx, st)
+
+	return b, v, rev, nil
+}
+
+// UserBannerClick resolves the banner content the same way as UserBanner does, counts a click of it,
+// and returns its URL. The banner may be up to CacheTTL outdated.
+func (s *Service) UserBannerClick(ctx context.Context, featureID, tagID int64, userID string) (string, error) {
+	b, _, err := s.BannerByFeatureTag(ctx, featureID, tagID, false, true)
+	if err != nil {
+		return "", err
+	}
+
+	v := b.Variant(userID)
+	st := newStats(b, v)
+	st.Clicks = 1
+	s.countStats(ctx, st)
+
+	if v != nil {
+		return v.URL, nil
+	}
+	return b.URL, nil
+}
+
+// BannerStats returns the daily stats of the banner with the given id within the days from and to inclusive,
+// including the days without impressions. If variantID is nil, the stats of all the banner contents are summed up,
+// and 0 means the banner content itself. The stats may be up to the flush interval of StatsBuffer outdated.
+// If to is nil, it's today, and if from is nil, it's DefaultStatsDays days before to.
+// If the range is empty or longer than MaxStatsDays, a new service.ValidationError is returned.
+// If the banner was not found, it returns an error.
+func (s *Service) BannerStats(
+	ctx context.Context,
+	id int64,
+	variantID *int64,
+	from, to *time.Time,
+) ([]*entity.BannerStats, error) {
+	last := entity.StatsDay(time.Now())
+	if to != nil {
+		last = entity.StatsDay(*to)
+	}
+	first := last.AddDate(0, 0, 1-DefaultStatsDays)
+	if from != nil {
+		first = entity.StatsDay(*from)
+	}
+	if last.Before(first) {
+		return nil, service.ValidationError("to must not be before from")
+	}
+	if last.Sub(first) >= MaxStatsDays*24*time.Hour {
+		return nil, service.ValidationError("too many days requested")
+	}
+
+	if _, err := s.Banner(ctx, id); err != nil {
+		return nil, err
+	}
+
+	stats, err := s.stats.BannerStats(ctx, id, variantID, first, last)
+	if err != nil {
+		s.logger.Error("failed to get banner stats", sl.Err(err), slog.Int64("id", id))
+		return nil, ErrUnknown
+	}
+
+	vID := int64(0)
+	if variantID != nil {
+		vID = *variantID
+	}
+	res := make([]*entity.BannerStats, 0, int(last.Sub(first)/(24*time.Hour))+1)
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		if len(stats) > 0 && stats[0].Day.Equal(day) {
+			res = append(res, stats[0])
+			stats = stats[1:]
+			continue
+		}
+		res = append(res, &entity.BannerStats{BannerID: id, VariantID: vID, Day: day})
+	}
+
+	return res, nil
+}
+
+// newStats returns the empty stats of the banner content for today.
+func newStats(b *entity.Banner, v *entity.BannerVariant) *entity.BannerStats {
+	st := &entity.BannerStats{BannerID: b.ID, Day: entity.StatsDay(time.Now())}
+	if v != nil {
+		st.VariantID = v.ID
+	}
+
+	return st
+}
+
+// countStats adds up the stats. The content is already shown at this point, so the errors are only logged.
+func (s *Service) countStats(ctx context.Context, stats ...*entity.BannerStats) {
+	err := s.stats.AddBannerStats(ctx, stats)
+	if err != nil {
+		s.logger.Error("failed to count banner stats", sl.Err(err))
+	}
+}
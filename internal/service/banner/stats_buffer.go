@@ -0,0 +1,149 @@
+package banner
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"banners-management/internal/lib/logger/sl"
+	"banners-management/internal/model/entity"
+	"banners-management/internal/storage/repo"
+)
+
+// DefaultStatsFlushInterval is the interval between the flushes of StatsBuffer, if it's not configured.
+const DefaultStatsFlushInterval = 10 * time.Second
+
+// statsKey identifies the daily stats of a single banner content.
+type statsKey struct {
+	bannerID, variantID int64
+	day                 time.Time
+}
+
+// StatsBuffer is a decorator for repo.BannerStats that adds up the banner stats in memory
+// and periodically flushes them to the decorated repo.BannerStats with a single write,
+// so the impressions and the clicks are counted without a round-trip to the storage.
+// Every app instance flushes only its own increments, so the instances don't need to be coordinated.
+// The stats, that are not flushed yet, are not returned by BannerStats, and are lost if the instance crashes.
+type StatsBuffer struct {
+	storage repo.BannerStats
+	mu      sync.Mutex
+	buf     map[statsKey]*entity.BannerStats
+	stop    chan struct{}
+	done    chan struct{}
+	logger  *slog.Logger
+}
+
+// NewStatsBuffer returns a new StatsBuffer instance, that flushes the stats every interval
+// until ctx is done or Close is called. Non-positive interval means DefaultStatsFlushInterval.
+func NewStatsBuffer(
+	ctx context.Context,
+	storage repo.BannerStats,
+	interval time.Duration,
+	logger *slog.Logger,
+) *StatsBuffer {
+	if interval <= 0 {
+		interval = DefaultStatsFlushInterval
+	}
+
+	res := &StatsBuffer{
+		storage: storage,
+		buf:     make(map[statsKey]*entity.BannerStats),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+		logger:  logger.With(slog.String("comp", "service.banner.stats_buffer")),
+	}
+
+	go res.runFlushDaemon(ctx, interval)
+
+	return res
+}
+
+// AddBannerStats adds the stats to the buffer. It never fails.
+func (sb *StatsBuffer) AddBannerStats(_ context.Context, stats []*entity.BannerStats) error {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	for _, st := range stats {
+		sb.add(st)
+	}
+
+	return nil
+}
+
+// BannerStats does nothing and just proxies the request to the decorated repo.BannerStats.
+func (sb *StatsBuffer) BannerStats(
+	ctx context.Context,
+	bannerID int64,
+	variantID *int64,
+	from, to time.Time,
+) ([]*entity.BannerStats, error) {
+	return sb.storage.BannerStats(ctx, bannerID, variantID, from, to)
+}
+
+// Flush writes the buffered stats to the decorated repo.BannerStats. If the write fails,
+// the stats are returned to the buffer, so they're written by the next flush.
+func (sb *StatsBuffer) Flush(ctx context.Context) error {
+	sb.mu.Lock()
+	if len(sb.buf) == 0 {
+		sb.mu.Unlock()
+		return nil
+	}
+	stats := make([]*entity.BannerStats, 0, len(sb.buf))
+	for _, st := range sb.buf {
+		stats = append(stats, st)
+	}
+	sb.buf = make(map[statsKey]*entity.BannerStats, len(stats))
+	sb.mu.Unlock()
+
+	err := sb.storage.AddBannerStats(ctx, stats)
+	if err != nil {
+		_ = sb.AddBannerStats(ctx, stats)
+		return err
+	}
+
+	return nil
+}
+
+// Close stops the periodic flushes and flushes the buffered stats for the last time.
+// It must be called once.
+func (sb *StatsBuffer) Close(ctx context.Context) error {
+	close(sb.stop)
+	<-sb.done
+
+	return sb.Flush(ctx)
+}
+
+// add adds up the stats with the buffered stats of the same banner content and day.
+// The caller must hold the lock.
+func (sb *StatsBuffer) add(st *entity.BannerStats) {
+	key := statsKey{st.BannerID, st.VariantID, st.Day}
+	if cur, ok := sb.buf[key]; ok {
+		cur.Impressions += st.Impressions
+		cur.Clicks += st.Clicks
+		return
+	}
+
+	cp := *st
+	sb.buf[key] = &cp
+}
+
+// runFlushDaemon flushes the buffered stats every interval until ctx is done or the buffer is closed.
+func (sb *StatsBuffer) runFlushDaemon(ctx context.Context, interval time.Duration) {
+	defer close(sb.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sb.stop:
+			return
+		case <-ticker.C:
+			err := sb.Flush(ctx)
+			if err != nil {
+				sb.logger.Error("failed to flush banner stats", sl.Err(err))
+			}
+		}
+	}
+}
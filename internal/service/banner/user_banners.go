@@ -0,0 +1,102 @@
+package banner
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"banners-management/internal/lib/logger/sl"
+	"banners-management/internal/model/entity"
+	"banners-management/internal/service"
+	"banners-management/internal/storage/repo"
+)
+
+// MaxUserBannerKeys is the maximum number of the feature and tag pairs, that are resolved by a single request.
+const MaxUserBannerKeys = 100
+
+// UserBanner is a banner of the feature, resolved for a user by one of the user tags.
+type UserBanner struct {
+	FeatureID int64
+	TagID     int64
+	Banner    *entity.Banner
+	// Variant is the variant of the banner assigned to the user, or nil if the banner content itself is shown.
+	Variant *entity.BannerVariant
+}
+
+// UserBanners resolves the banners of the features for a user with the tags at once.
+// For every feature the tags are tried in the given order, and the first one having a banner,
+// that can be shown to the user (active and within its activation window), wins.
+// So the clients list the tags from the most specific one to the most general one.
+// The features without such banners are omitted, and the duplicate ids are ignored.
+// All the banners are read with a single request, see repo.BannerReader.
+// The variants are assigned to the user by userID, and the impressions of the resolved banners are counted.
+// If the ids are empty, or there are too many pairs of them, a new service.ValidationError is returned.
+func (s *Service) UserBanners(
+	ctx context.Context,
+	featureIDs, tagIDs []int64,
+	userID string,
+	useLastRevision bool,
+) ([]*UserBanner, repo.Revision, error) {
+	featureIDs, tagIDs = uniqueIDs(featureIDs), uniqueIDs(tagIDs)
+	if len(featureIDs) == 0 || len(tagIDs) == 0 {
+		return nil, repo.Revision{}, service.ValidationError("at least one feature and one tag are required")
+	}
+	if len(featureIDs)*len(tagIDs) > MaxUserBannerKeys {
+		return nil, repo.Revision{}, service.ValidationError("too many pairs of features and tags")
+	}
+
+	keys := make([]repo.FeatureTag, 0, len(featureIDs)*len(tagIDs))
+	for _, fID := range featureIDs {
+		for _, tID := range tagIDs {
+			keys = append(keys, repo.FeatureTag{FeatureID: fID, TagID: tID})
+		}
+	}
+
+	bs, rev, err := s.reader.BannersByFeatureTags(ctx, keys, useLastRevision)
+	if err != nil {
+		s.logger.Error("failed to get banners by features and tags", sl.Err(err))
+		return nil, rev, ErrUnknown
+	}
+
+	now := time.Now()
+	res := make([]*UserBanner, 0, len(featureIDs))
+	stats := make([]*entity.BannerStats, 0, len(featureIDs))
+	for i, fID := range featureIDs {
+		for j, tID := range tagIDs {
+			b := bs[i*len(tagIDs)+j]
+			if b == nil {
+				continue
+			}
+			if !b.IsActive || !b.InWindow(now) {
+				s.logger.Debug("banner not shown to user, trying next tag", slog.Int64("id", b.ID))
+				continue
+			}
+			v := b.Variant(userID)
+			st := newStats(b, v)
+			st.Impressions = 1
+			res = append(res, &UserBanner{FeatureID: fID, TagID: tID, Banner: b, Variant: v})
+			stats = append(stats, st)
+			break
+		}
+	}
+	if len(stats) > 0 {
+		s.countStats(ctx, stats...)
+	}
+
+	return res, rev, nil
+}
+
+// uniqueIDs returns ids without the duplicates, keeping the order of the first occurrences.
+func uniqueIDs(ids []int64) []int64 {
+	seen := make(map[int64]struct{}, len(ids))
+	res := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		res = append(res, id)
+	}
+
+	return res
+}
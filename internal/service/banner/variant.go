@@ -0,0 +1,130 @@
+package banner
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+
+	"github.com/go-playground/validator/v10"
+
+	"banners-management/internal/lib/logger/sl"
+	"banners-management/internal/model/dto/banner"
+	"banners-management/internal/model/entity"
+	"banners-management/internal/service"
+	"banners-management/internal/storage/repo"
+)
+
+// BannerVariants returns the variants of the banner with the given id ordered by id.
+// If the banner was not found, it returns an error.
+func (s *Service) BannerVariants(ctx context.Context, id int64) ([]*entity.BannerVariant, error) {
+	variants, err := s.variants.BannerVariants(ctx, id)
+	if errors.Is(err, repo.ErrBannerNotFound) {
+		s.logger.Info("banner not found", sl.Err(err))
+		return nil, ErrNotFound
+	} else if err != nil {
+		s.logger.Error("failed to get banner variants", sl.Err(err), slog.Int64("id", id))
+		return nil, ErrUnknown
+	}
+
+	return variants, nil
+}
+
+// SaveBannerVariant saves a new variant of the banner with the given id and records it to the audit log.
+// It validates the input data and returns an error if the data is invalid.
+func (s *Service) SaveBannerVariant(ctx context.Context, id int64, dto banner.CreateVariantDTO) (int64, error) {
+	if err := validatr.Struct(dto); err != nil {
+		var validErrs validator.ValidationErrors
+		errors.As(err, &validErrs)
+		s.logger.Info("request validation failed", sl.Err(err))
+		return 0, service.ValidationErr(validErrs)
+	}
+
+	model := dto.ToModel(id)
+	s.logger.Info("saving banner variant", slog.Int64("id", id), slog.Int("weight", model.Weight))
+	variantID, err := s.variants.SaveBannerVariant(ctx, model)
+	if errors.Is(err, repo.ErrBannerNotFound) {
+		s.logger.Info("banner not found", sl.Err(err))
+		return 0, ErrNotFound
+	} else if err != nil {
+		s.logger.Error("failed to save banner variant", sl.Err(err))
+		return 0, ErrNotSaved
+	}
+	s.recordVariant(ctx, entity.AuditVariantCreate, id, variantID, entity.VariantDiff(nil, model))
+
+	return variantID, nil
+}
+
+// UpdateBannerVariant updates the variant by variantID of the banner with the given id
+// and records the changed fields to the audit log.
+// If the banner or its variant was not found, it returns an error.
+func (s *Service) UpdateBannerVariant(
+	ctx context.Context,
+	id, variantID int64,
+	dto banner.UpdateVariantDTO,
+) error {
+	if err := validatr.Struct(dto); err != nil {
+		var validErrs validator.ValidationErrors
+		errors.As(err, &validErrs)
+		s.logger.Info("request validation failed", sl.Err(err))
+		return service.ValidationErr(validErrs)
+	}
+
+	before, err := s.bannerVariant(ctx, id, variantID)
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("updating banner variant", slog.Int64("id", id), slog.Int64("variantID", variantID))
+	err = s.variants.UpdateBannerVariant(ctx, dto.ToModel(id, variantID))
+	if errors.Is(err, repo.ErrVariantNotFound) {
+		s.logger.Info("banner variant not found", sl.Err(err))
+		return ErrVariantNotFound
+	} else if err != nil {
+		s.logger.Error("failed to update banner variant", sl.Err(err))
+		return ErrUnknown
+	}
+
+	s.recordVariant(ctx, entity.AuditVariantUpdate, id, variantID, s.variantDiffWithCurrent(ctx, id, before))
+
+	return nil
+}
+
+// DeleteBannerVariant deletes the variant by variantID of the banner with the given id
+// and records it to the audit log.
+// If the banner or its variant was not found, it returns an error.
+func (s *Service) DeleteBannerVariant(ctx context.Context, id, variantID int64) error {
+	before, err := s.bannerVariant(ctx, id, variantID)
+	if err != nil {
+		return err
+	}
+
+	err = s.variants.DeleteBannerVariant(ctx, id, variantID)
+	if errors.Is(err, repo.ErrVariantNotFound) {
+		s.logger.Info("banner variant not found", sl.Err(err))
+		return ErrVariantNotFound
+	} else if err != nil {
+		s.logger.Error("failed to delete banner variant", sl.Err(err))
+		return ErrUnknown
+	}
+	s.recordVariant(ctx, entity.AuditVariantDelete, id, variantID, entity.VariantDiff(before, nil))
+
+	return nil
+}
+
+// bannerVariant returns the variant by variantID of the banner with the given id.
+// If the banner or its variant was not found, it returns an error.
+func (s *Service) bannerVariant(ctx context.Context, id, variantID int64) (*entity.BannerVariant, error) {
+	variants, err := s.BannerVariants(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	i := slices.IndexFunc(variants, func(v *entity.BannerVariant) bool { return v.ID == variantID })
+	if i < 0 {
+		s.logger.Info("banner variant not found", slog.Int64("id", id), slog.Int64("variantID", variantID))
+		return nil, ErrVariantNotFound
+	}
+
+	return variants[i], nil
+}
@@ -0,0 +1,50 @@
+package banner
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"banners-management/internal/lib/logger/sl"
+	"banners-management/internal/model/entity"
+	"banners-management/internal/storage/repo"
+)
+
+// BannerVersions returns the previous versions of the banner with the given id, starting from the most recent one.
+// If the banner was not found, it returns an error.
+func (s *Service) BannerVersions(ctx context.Context, id int64) ([]*entity.BannerVersion, error) {
+	versions, err := s.versioner.BannerVersions(ctx, id)
+	if errors.Is(err, repo.ErrBannerNotFound) {
+		s.logger.Info("banner not found", sl.Err(err))
+		return nil, ErrNotFound
+	} else if err != nil {
+		s.logger.Error("failed to get banner versions", sl.Err(err), slog.Int64("id", id))
+		return nil, ErrUnknown
+	}
+
+	return versions, nil
+}
+
+// RestoreBannerVersion makes the given version of the banner with the given id current again.
+// If the banner or its version was not found, it returns an error.
+// The restoration is recorded to the audit log along with the changed fields.
+func (s *Service) RestoreBannerVersion(ctx context.Context, id int64, version int) error {
+	s.logger.Info("restoring banner version", slog.Int64("id", id), slog.Int("version", version))
+	before, after, err := s.versioner.RestoreBannerVersion(ctx, id, version)
+	if errors.Is(err, repo.ErrBannerNotFound) {
+		s.logger.Info("banner not found", sl.Err(err))
+		return ErrNotFound
+	} else if errors.Is(err, repo.ErrVersionNotFound) {
+		s.logger.Info("banner version not found", sl.Err(err))
+		return ErrVersionNotFound
+	} else if errors.Is(err, repo.ErrBannerUnknownReference) {
+		s.logger.Info("banner version references unknown feature or tags", sl.Err(err))
+		return ErrUnknownReference
+	} else if err != nil {
+		s.logger.Error("failed to restore banner version", sl.Err(err))
+		return ErrUnknown
+	}
+	s.recordBanner(ctx, entity.AuditBannerRestore, id, entity.BannerDiff(before, after))
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package feature
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/go-playground/validator/v10"
+
+	"banners-management/internal/lib/api/msg"
+	"banners-management/internal/lib/logger/sl"
+	"banners-management/internal/model/dto/feature"
+	"banners-management/internal/model/entity"
+	"banners-management/internal/service"
+	"banners-management/internal/storage/repo"
+)
+
+var (
+	ErrNotFound      = errors.New(msg.FeatureNotFound)
+	ErrAlreadyExists = errors.New(msg.FeatureAlreadyExists)
+	ErrInUse         = errors.New(msg.FeatureInUse)
+	ErrUnknown       = errors.New(msg.ErrUnknown)
+)
+
+var (
+	validatr = validator.New()
+)
+
+// Service is a service for feature CRUD operations.
+type Service struct {
+	features repo.FeatureRepo
+	logger   *slog.Logger
+}
+
+// NewService returns a new Service instance.
+func NewService(features repo.FeatureRepo, log *slog.Logger) *Service {
+	return &Service{
+		features,
+		log.With(slog.String("comp", "service.feature")),
+	}
+}
+
+// SaveFeature saves a new feature to the storage.
+// It validates the input data and returns an error if the data is invalid.
+func (s *Service) SaveFeature(ctx context.Context, dto feature.CreateDTO) (int64, error) {
+	if err := validatr.Struct(dto); err != nil {
+		var validErrs validator.ValidationErrors
+		errors.As(err, &validErrs)
+		s.logger.Info("request validation failed", sl.Err(err))
+		return 0, service.ValidationErr(validErrs)
+	}
+
+	model := dto.ToModel()
+	s.logger.Info("saving feature", slog.Int64("id", model.ID))
+	err := s.features.SaveFeature(ctx, model)
+	if errors.Is(err, repo.ErrFeatureAlreadyExists) {
+		s.logger.Info("feature already exists", sl.Err(err))
+		return 0, ErrAlreadyExists
+	} else if err != nil {
+		s.logger.Error("failed to save feature", sl.Err(err))
+		return 0, ErrUnknown
+	}
+
+	return model.ID, nil
+}
+
+// Feature returns a feature by the ID.
+func (s *Service) Feature(ctx context.Context, id int64) (*entity.Feature, error) {
+	f, err := s.features.Feature(ctx, id)
+	if errors.Is(err, repo.ErrFeatureNotFound) {
+		s.logger.Info("feature not found", slog.Int64("id", id))
+		return nil, ErrNotFound
+	} else if err != nil {
+		s.logger.Error("failed to get feature", sl.Err(err), slog.Int64("id", id))
+		return nil, ErrUnknown
+	}
+
+	return f, nil
+}
+
+// Features returns a list of features. It respects the limit and offset parameters.
+func (s *Service) Features(ctx context.Context, limit, offset *int) ([]*entity.Feature, error) {
+	fs, err := s.features.Features(ctx, limit, offset)
+	if err != nil {
+		s.logger.Error("failed to get features", sl.Err(err))
+		return nil, ErrUnknown
+	}
+
+	return fs, nil
+}
+
+// UpdateFeature updates a feature by the ID.
+// If the feature was not found, it returns an error.
+func (s *Service) UpdateFeature(ctx context.Context, id int64, dto feature.UpdateDTO) error {
+	err := s.features.UpdateFeature(ctx, dto.ToModel(id))
+	if errors.Is(err, repo.ErrFeatureNotFound) {
+		s.logger.Info("feature not found", sl.Err(err))
+		return ErrNotFound
+	} else if err != nil {
+		s.logger.Error("failed to update feature", sl.Err(err))
+		return ErrUnknown
+	}
+
+	return nil
+}
+
+// DeleteFeature deletes a feature by the ID.
+// If the feature was not found or is used by any banner, it returns an error.
+func (s *Service) DeleteFeature(ctx context.Context, id int64) error {
+	err := s.features.DeleteFeature(ctx, id)
+	if errors.Is(err, repo.ErrFeatureNotFound) {
+		s.logger.Info("feature not found", sl.Err(err))
+		return ErrNotFound
+	} else if errors.Is(err, repo.ErrFeatureInUse) {
+		s.logger.Info("unable to delete feature", sl.Err(err))
+		return ErrInUse
+	} else if err != nil {
+		s.logger.Error("failed to delete feature", sl.Err(err))
+		return ErrUnknown
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package tag
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/go-playground/validator/v10"
+
+	"banners-management/internal/lib/api/msg"
+	"banners-management/internal/lib/logger/sl"
+	"banners-management/internal/model/dto/tag"
+	"banners-management/internal/model/entity"
+	"banners-management/internal/service"
+	"banners-management/internal/storage/repo"
+)
+
+var (
+	ErrNotFound      = errors.New(msg.TagNotFound)
+	ErrAlreadyExists = errors.New(msg.TagAlreadyExists)
+	ErrInUse         = errors.New(msg.TagInUse)
+	ErrUnknown       = errors.New(msg.ErrUnknown)
+)
+
+var (
+	validatr = validator.New()
+)
+
+// Service is a service for tag CRUD operations.
+type Service struct {
+	tags   repo.TagRepo
+	logger *slog.Logger
+}
+
+// NewService returns a new Service instance.
+func NewService(tags repo.TagRepo, log *slog.Logger) *Service {
+	return &Service{
+		tags,
+		log.With(slog.String("comp", "service.tag")),
+	}
+}
+
+// SaveTag saves a new tag to the storage.
+// It validates the input data and returns an error if the data is invalid.
+func (s *Service) SaveTag(ctx context.Context, dto tag.CreateDTO) (int64, error) {
+	if err := validatr.Struct(dto); err != nil {
+		var validErrs validator.ValidationErrors
+		errors.As(err, &validErrs)
+		s.logger.Info("request validation failed", sl.Err(err))
+		return 0, service.ValidationErr(validErrs)
+	}
+
+	model := dto.ToModel()
+	s.logger.Info("saving tag", slog.Int64("id", model.ID))
+	err := s.tags.SaveTag(ctx, model)
+	if errors.Is(err, repo.ErrTagAlreadyExists) {
+		s.logger.Info("tag already exists", sl.Err(err))
+		return 0, ErrAlreadyExists
+	} else if err != nil {
+		s.logger.Error("failed to save tag", sl.Err(err))
+		return 0, ErrUnknown
+	}
+
+	return model.ID, nil
+}
+
+// Tag returns a tag by the ID.
+func (s *Service) Tag(ctx context.Context, id int64) (*entity.Tag, error) {
+	t, err := s.tags.Tag(ctx, id)
+	if errors.Is(err, repo.ErrTagNotFound) {
+		s.logger.Info("tag not found", slog.Int64("id", id))
+		return nil, ErrNotFound
+	} else if err != nil {
+		s.logger.Error("failed to get tag", sl.Err(err), slog.Int64("id", id))
+		return nil, ErrUnknown
+	}
+
+	return t, nil
+}
+
+// Tags returns a list of tags. It respects the limit and offset parameters.
+func (s *Service) Tags(ctx context.Context, limit, offset *int) ([]*entity.Tag, error) {
+	ts, err := s.tags.Tags(ctx, limit, offset)
+	if err != nil {
+		s.logger.Error("failed to get tags", sl.Err(err))
+		return nil, ErrUnknown
+	}
+
+	return ts, nil
+}
+
+// UpdateTag updates a tag by the ID.
+// If the tag was not found, it returns an error.
+func (s *Service) UpdateTag(ctx context.Context, id int64, dto tag.UpdateDTO) error {
+	err := s.tags.UpdateTag(ctx, dto.ToModel(id))
+	if errors.Is(err, repo.ErrTagNotFound) {
+		s.logger.Info("tag not found", sl.Err(err))
+		return ErrNotFound
+	} else if err != nil {
+		s.logger.Error("failed to update tag", sl.Err(err))
+		return ErrUnknown
+	}
+
+	return nil
+}
+
+// DeleteTag deletes a tag by the ID.
+// If the tag was not found or is used by any banner, it returns an error.
+func (s *Service) DeleteTag(ctx context.Context, id int64) error {
+	err := s.tags.DeleteTag(ctx, id)
+	if errors.Is(err, repo.ErrTagNotFound) {
+		s.logger.Info("tag not found", sl.Err(err))
+		return ErrNotFound
+	} else if errors.Is(err, repo.ErrTagInUse) {
+		s.logger.Info("unable to delete tag", sl.Err(err))
+		return ErrInUse
+	} else if err != nil {
+		s.logger.Error("failed to delete tag", sl.Err(err))
+		return ErrUnknown
+	}
+
+	return nil
+}
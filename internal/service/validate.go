@@ -6,6 +6,7 @@ import (
 	"github.com/go-playground/validator/v10"
 
 	"banners-management/internal/lib/api/msg"
+	"banners-management/internal/model/entity"
 )
 
 // ValidationError is a custom error type for validation errors.
@@ -17,8 +18,20 @@ func (e ValidationError) Error() string {
 
 const (
 	validateRequired = "required"
+	// validateScope is the tag of the scopes, that can be granted, see entity.ValidScope.
+	validateScope = "scope"
 )
 
+// NewValidator returns a new validator with the custom tags of the service registered.
+func NewValidator() *validator.Validate {
+	v := validator.New()
+	_ = v.RegisterValidation(validateScope, func(fl validator.FieldLevel) bool {
+		return entity.ValidScope(fl.Field().String())
+	})
+
+	return v
+}
+
 // ValidationErr returns a custom error message for validation errors.
 func ValidationErr(errs validator.ValidationErrors) ValidationError {
 	var errMsgs []string
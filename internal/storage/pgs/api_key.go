@@ -0,0 +1,108 @@
+package pgs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"banners-management/internal/model/entity"
+	"banners-management/internal/storage/repo"
+)
+
+const apiKeyColumns = `id, name, prefix, key_hash, role, scopes, created_at, last_used_at, revoked_at`
+
+// SaveAPIKey saves an API key to the database.
+// It returns the ID of the key if successful, otherwise error.
+func (s *Storage) SaveAPIKey(ctx context.Context, k *entity.APIKey) (int64, error) {
+	const comp = "storage.pgs.SaveAPIKey"
+
+	scopes := k.Scopes
+	if scopes == nil {
+		scopes = []string{} // nil slice is encoded as NULL
+	}
+
+	var id int64
+	err := s.dbPool.QueryRow(ctx,
+		`INSERT INTO api_key (name, prefix, key_hash, role, scopes, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6) RETURNING id;`,
+		k.Name, k.Prefix, k.KeyHash, k.Role, scopes, k.CreatedAt,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", comp, err)
+	}
+
+	return id, nil
+}
+
+// APIKeyByHash finds an API key by the hash of the key. Revoked keys are found as well.
+func (s *Storage) APIKeyByHash(ctx context.Context, keyHash string) (*entity.APIKey, error) {
+	const comp = "storage.pgs.APIKeyByHash"
+
+	rows, err := s.dbPool.Query(ctx, `SELECT `+apiKeyColumns+` FROM api_key WHERE key_hash = $1;`, keyHash)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", comp, err)
+	}
+
+	k, err := pgx.CollectExactlyOneRow(rows, pgx.RowToAddrOfStructByPos[entity.APIKey])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%s: %w", comp, repo.ErrAPIKeyNotFound)
+	} else if err != nil {
+		return nil, fmt.Errorf("%s: %w", comp, err)
+	}
+
+	return k, nil
+}
+
+// APIKeys returns slice of API keys ordered by id.
+// It respects the limit and offset parameters, if provided. If they're set to nil, they're ignored.
+func (s *Storage) APIKeys(ctx context.Context, limit, offset *int) ([]*entity.APIKey, error) {
+	const comp = "storage.pgs.APIKeys"
+
+	// NULL limit and offset are the same as omitted ones
+	rows, err := s.dbPool.Query(ctx,
+		`SELECT `+apiKeyColumns+` FROM api_key ORDER BY id LIMIT $1 OFFSET $2;`,
+		limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", comp, err)
+	}
+
+	keys, err := pgx.CollectRows(rows, pgx.RowToAddrOfStructByPos[entity.APIKey])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", comp, err)
+	}
+
+	return keys, nil
+}
+
+// RevokeAPIKey marks an API key by the id as revoked. Revoking the revoked key doesn't change its revocation time.
+func (s *Storage) RevokeAPIKey(ctx context.Context, id int64) error {
+	const comp = "storage.pgs.RevokeAPIKey"
+
+	r, err := s.dbPool.Exec(ctx,
+		`UPDATE api_key SET revoked_at = COALESCE(revoked_at, CURRENT_TIMESTAMP) WHERE id = $1;`,
+		id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", comp, err)
+	}
+
+	if r.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", comp, repo.ErrAPIKeyNotFound)
+	}
+
+	return nil
+}
+
+// TouchAPIKey records the moment an API key by the id was used.
+func (s *Storage) TouchAPIKey(ctx context.Context, id int64, usedAt time.Time) error {
+	_, err := s.dbPool.Exec(ctx,
+		`UPDATE api_key SET last_used_at = GREATEST(last_used_at, $2) WHERE id = $1;`,
+		id, usedAt)
+	if err != nil {
+		return fmt.Errorf("storage.pgs.TouchAPIKey: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package pgs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"banners-management/internal/model/entity"
+	"banners-management/internal/storage/repo"
+)
+
+const (
+	auditEventInsertColumns = `action, actor, actor_role, request_id,
+		banner_id, variant_id, feature_id, tag_id, job_id, diff, created_at`
+	auditEventColumns = `id, ` + auditEventInsertColumns
+)
+
+// SaveAuditEvent saves an audit event to the database.
+// It returns the ID of the event if successful, otherwise error.
+func (s *Storage) SaveAuditEvent(ctx context.Context, e *entity.AuditEvent) (int64, error) {
+	const comp = "storage.pgs.SaveAuditEvent"
+
+	diff := e.Diff
+	if diff == nil {
+		diff = map[string]entity.AuditChange{} // nil map is encoded as NULL
+	}
+
+	var id int64
+	err := s.dbPool.QueryRow(ctx,
+		`INSERT INTO audit_event (`+auditEventInsertColumns+`)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id;`,
+		e.Action, e.Actor, e.ActorRole, e.RequestID, e.BannerID, e.VariantID, e.FeatureID, e.TagID, e.JobID,
+		diff, e.CreatedAt,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", comp, err)
+	}
+
+	return id, nil
+}
+
+// AuditEvents returns slice of audit events, that match the filter, starting from the most recent one.
+// It respects the limit and offset parameters, if provided. If they're set to nil, they're ignored.
+func (s *Storage) AuditEvents(
+	ctx context.Context,
+	filter repo.AuditFilter,
+	limit, offset *int,
+) ([]*entity.AuditEvent, error) {
+	const comp = "storage.pgs.AuditEvents"
+
+	// NULL filters, limit and offset are the same as omitted ones
+	rows, err := s.dbPool.Query(ctx,
+		`SELECT `+auditEventColumns+` FROM audit_event
+			WHERE ($1::INT IS NULL OR banner_id = $1)
+			AND ($2::TEXT IS NULL OR actor = $2)
+			AND ($3::TIMESTAMPTZ IS NULL OR created_at >= $3)
+			AND ($4::TIMESTAMPTZ IS NULL OR created_at < $4)
+			ORDER BY created_at DESC, id DESC LIMIT $5 OFFSET $6;`,
+		filter.BannerID, filter.Actor, filter.From, filter.To, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", comp, err)
+	}
+
+	events, err := pgx.CollectRows(rows, pgx.RowToAddrOfStructByPos[entity.AuditEvent])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", comp, err)
+	}
+
+	return events, nil
+}
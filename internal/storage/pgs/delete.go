@@ -2,40 +2,188 @@ package pgs
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
-	"banners-management/internal/storage/repo"
+	"github.com/jackc/pgx/v5"
+
+	"banners-management/internal/model/entity"
 )
 
-// DeleteBanner deletes banner by id.
-func (s *Storage) DeleteBanner(ctx context.Context, id int64) error {
+// DeleteBanner deletes banner by id. It returns the state of the deleted banner,
+// read within the deletion transaction.
+func (s *Storage) DeleteBanner(ctx context.Context, id int64) (_ *entity.Banner, err error) {
 	const comp = "storage.pgs.DeleteBanner"
 
-	r, err := s.dbPool.Exec(ctx, `DELETE FROM banner WHERE id = $1;`, id)
+	tx, err := s.dbPool.BeginTx(ctx, pgx.TxOptions{})
 	if err != nil {
-		return fmt.Errorf("%s: %w", comp, err)
+		return nil, fmt.Errorf("%s: %w", comp, err)
 	}
+	defer func() {
+		err := tx.Rollback(ctx)
+		if err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			err = fmt.Errorf("%s: %w", comp, err)
+		}
+	}()
 
-	if r.RowsAffected() == 0 {
-		return fmt.Errorf("%s: %w", comp, repo.ErrBannerNotFound)
+	deleted, err := bannerByID(ctx, tx, id, true)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", comp, err)
 	}
 
-	return nil
+	_, err = tx.Exec(ctx, `DELETE FROM banner WHERE id = $1;`, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", comp, err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", comp, err)
+	}
+
+	return deleted, nil
 }
 
-// DeleteByFeatureTag deletes banner by featureID and tagID.
-func (s *Storage) DeleteByFeatureTag(ctx context.Context, featureID, tagID int64) error {
+// DeleteByFeatureTag deletes all the banners of the feature with the given featureID, that have the tag
+// with the given tagID. It returns the states of the deleted banners.
+func (s *Storage) DeleteByFeatureTag(ctx context.Context, featureID, tagID int64) ([]*entity.Banner, error) {
 	const comp = "storage.pgs.DeleteByFeatureTag"
 
-	banner, err := s.BannerByFeatureTag(ctx, featureID, tagID, true)
+	deleted, err := s.deleteBanners(ctx,
+		`b.feature_id = $1 AND EXISTS (SELECT 1 FROM banner_tag bt WHERE bt.banner_id = b.id AND bt.tag_id = $2)`,
+		featureID, tagID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", comp, err)
+	}
+
+	return deleted, nil
+}
+
+// DeleteByFeature deletes all the banners of the feature with the given featureID.
+// It returns the states of the deleted banners.
+func (s *Storage) DeleteByFeature(ctx context.Context, featureID int64) ([]*entity.Banner, error) {
+	const comp = "storage.pgs.DeleteByFeature"
+
+	deleted, err := s.deleteBanners(ctx, `b.feature_id = $1`, featureID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", comp, err)
+	}
+
+	return deleted, nil
+}
+
+// deleteBanners deletes all the banners b matching the SQL condition with the args in a single transaction.
+// It returns the states of the deleted banners, read within the transaction.
+func (s *Storage) deleteBanners(ctx context.Context, cond string, args ...any) (_ []*entity.Banner, err error) {
+	tx, err := s.dbPool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err := tx.Rollback(ctx)
+		if err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			err = fmt.Errorf("storage.pgs.deleteBanners: %w", err)
+		}
+	}()
+
+	deleted, ids, err := bannersForUpdate(ctx, tx, cond, args...)
+	if err != nil || len(ids) == 0 {
+		return deleted, err
+	}
+
+	_, err = tx.Exec(ctx, `DELETE FROM banner WHERE id = ANY($1);`, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
 	if err != nil {
-		return fmt.Errorf("%s: %w", comp, err)
+		return nil, err
 	}
 
-	err = s.DeleteBanner(ctx, banner.ID)
+	return deleted, nil
+}
+
+// DeleteByTag removes the tag with the given tagID from all the banners and deletes the banners
+// left with no tags. The state of every affected banner is archived as its next version.
+// It returns the states of all the affected banners before the removal, both updated and deleted.
+func (s *Storage) DeleteByTag(ctx context.Context, tagID int64) (_ []*entity.Banner, err error) {
+	const comp = "storage.pgs.DeleteByTag"
+
+	tx, err := s.dbPool.BeginTx(ctx, pgx.TxOptions{})
 	if err != nil {
-		return fmt.Errorf("%s: %w", comp, err)
+		return nil, fmt.Errorf("%s: %w", comp, err)
+	}
+	defer func() {
+		err := tx.Rollback(ctx)
+		if err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			err = fmt.Errorf("%s: %w", comp, err)
+		}
+	}()
+
+	affected, ids, err := bannersForUpdate(ctx, tx,
+		`EXISTS (SELECT 1 FROM banner_tag bt WHERE bt.banner_id = b.id AND bt.tag_id = $1)`,
+		tagID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", comp, err)
+	}
+	if len(ids) == 0 {
+		return affected, nil
+	}
+
+	for _, id := range ids {
+		err = s.archiveBanner(ctx, tx, id)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", comp, err)
+		}
+	}
+
+	batch := new(pgx.Batch)
+	batch.Queue(`DELETE FROM banner_tag WHERE tag_id = $1;`, tagID)
+	batch.Queue(`DELETE FROM banner b WHERE b.id = ANY($1)
+		AND NOT EXISTS (SELECT 1 FROM banner_tag bt WHERE bt.banner_id = b.id);`, ids)
+	batch.Queue(`UPDATE banner SET updated_at = NOW() WHERE id = ANY($1);`, ids)
+
+	bres := tx.SendBatch(ctx, batch)
+	defer bres.Close()
+	for range batch.Len() {
+		_, err = bres.Exec()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", comp, err)
+		}
+	}
+	_ = bres.Close()
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", comp, err)
+	}
+
+	return affected, nil
+}
+
+// bannersForUpdate finds all the banners b matching the SQL condition with the args ordered by id,
+// and locks their rows until the end of the tx. It returns the banners along with their ids.
+func bannersForUpdate(ctx context.Context, tx pgx.Tx, cond string, args ...any) ([]*entity.Banner, []int64, error) {
+	rows, err := tx.Query(ctx, `SELECT `+bannerColumns+` FROM banner b WHERE `+cond+` ORDER BY b.id FOR UPDATE;`, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	banners := make([]*entity.Banner, 0)
+	ids := make([]int64, 0)
+	for rows.Next() {
+		b, err := scanBanner(rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		banners = append(banners, b)
+		ids = append(ids, b.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
 	}
 
-	return nil
+	return banners, ids, nil
 }
@@ -0,0 +1,22 @@
+package pgs
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	uniqueViolationCode     = "23505"
+	foreignKeyViolationCode = "23503"
+)
+
+// pgErrCode returns the code of the postgres error err, or an empty string if err is not a postgres error.
+func pgErrCode(err error) string {
+	pgErr := new(pgconn.PgError)
+	if errors.As(err, &pgErr) {
+		return pgErr.Code
+	}
+
+	return ""
+}
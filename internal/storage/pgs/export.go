@@ -0,0 +1,55 @@
+package pgs
+
+import (
+	"context"
+	"fmt"
+
+	"banners-management/internal/model/entity"
+)
+
+// EachBanner calls fn for every banner in the database ordered by id, while reading the banners.
+// So all the banners are never loaded into memory at once.
+// If fn returns an error, the iteration is stopped and the error is returned.
+func (s *Storage) EachBanner(ctx context.Context, fn func(b *entity.Banner) error) error {
+	const comp = "storage.pgs.EachBanner"
+
+	rows, err := s.dbPool.Query(ctx,
+		`SELECT id, title, text, url, is_active, priority, feature_id,
+				ARRAY(SELECT tag_id FROM banner_tag WHERE banner_id = b.id ORDER BY tag_id),
+				active_from, active_until, created_at, updated_at
+			FROM banner b ORDER BY id;`,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", comp, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		b := new(entity.Banner)
+		err := rows.Scan(
+			&b.ID,
+			&b.Title,
+			&b.Text,
+			&b.URL,
+			&b.IsActive,
+			&b.Priority,
+			&b.FeatureID,
+			&b.TagIDs,
+			&b.ActiveFrom,
+			&b.ActiveUntil,
+			&b.CreatedAt,
+			&b.UpdatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("%s: %w", comp, err)
+		}
+		if err := fn(b); err != nil {
+			return fmt.Errorf("%s: %w", comp, err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("%s: %w", comp, err)
+	}
+
+	return nil
+}
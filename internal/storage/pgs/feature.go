@@ -0,0 +1,112 @@
+package pgs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"banners-management/internal/model/entity"
+	"banners-management/internal/storage/repo"
+)
+
+// SaveFeature saves a feature with the provided id to the database.
+func (s *Storage) SaveFeature(ctx context.Context, f *entity.Feature) error {
+	const comp = "storage.pgs.SaveFeature"
+
+	_, err := s.dbPool.Exec(ctx,
+		`INSERT INTO feature (id, name, description) VALUES ($1, $2, $3);`,
+		f.ID, f.Name, f.Description)
+	if pgErrCode(err) == uniqueViolationCode {
+		return fmt.Errorf("%s: %w", comp, repo.ErrFeatureAlreadyExists)
+	} else if err != nil {
+		return fmt.Errorf("%s: %w", comp, err)
+	}
+
+	return nil
+}
+
+// Feature finds a feature by its id.
+func (s *Storage) Feature(ctx context.Context, id int64) (*entity.Feature, error) {
+	const comp = "storage.pgs.Feature"
+
+	f := new(entity.Feature)
+	err := s.dbPool.QueryRow(ctx,
+		`SELECT id, name, description FROM feature WHERE id = $1;`,
+		id,
+	).Scan(&f.ID, &f.Name, &f.Description)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%s: %w", comp, repo.ErrFeatureNotFound)
+	} else if err != nil {
+		return nil, fmt.Errorf("%s: %w", comp, err)
+	}
+
+	return f, nil
+}
+
+// Features returns slice of features ordered by id.
+// It respects the limit and offset parameters, if provided. If they're set to nil, they're ignored.
+func (s *Storage) Features(ctx context.Context, limit, offset *int) ([]*entity.Feature, error) {
+	const comp = "storage.pgs.Features"
+
+	// NULL limit and offset are the same as omitted ones
+	rows, err := s.dbPool.Query(ctx,
+		`SELECT id, name, description FROM feature ORDER BY id LIMIT $1 OFFSET $2;`,
+		limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", comp, err)
+	}
+
+	features, err := pgx.CollectRows(rows, pgx.RowToAddrOfStructByPos[entity.Feature])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", comp, err)
+	}
+
+	return features, nil
+}
+
+// UpdateFeature updates feature f in the storage.
+func (s *Storage) UpdateFeature(ctx context.Context, f *entity.UpdatableFeature) error {
+	const comp = "storage.pgs.UpdateFeature"
+
+	r, err := s.dbPool.Exec(ctx,
+		`UPDATE feature SET name = COALESCE($2, name), description = COALESCE($3, description) WHERE id = $1;`,
+		f.ID, f.Name, f.Description)
+	if err != nil {
+		return fmt.Errorf("%s: %w", comp, err)
+	}
+
+	if r.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", comp, repo.ErrFeatureNotFound)
+	}
+
+	return nil
+}
+
+// DeleteFeature deletes feature by id. The feature that is used by any banner is not deleted.
+func (s *Storage) DeleteFeature(ctx context.Context, id int64) error {
+	const comp = "storage.pgs.DeleteFeature"
+
+	var deleted, exists bool
+	err := s.dbPool.QueryRow(ctx,
+		`WITH deleted AS (
+				DELETE FROM feature f WHERE f.id = $1
+					AND NOT EXISTS (SELECT 1 FROM banner WHERE feature_id = f.id)
+				RETURNING id
+			) SELECT EXISTS (SELECT 1 FROM deleted), EXISTS (SELECT 1 FROM feature WHERE id = $1);`,
+		id,
+	).Scan(&deleted, &exists)
+	if err != nil {
+		return fmt.Errorf("%s: %w", comp, err)
+	}
+
+	switch {
+	case deleted:
+		return nil
+	case exists:
+		return fmt.Errorf("%s: %w", comp, repo.ErrFeatureInUse)
+	default:
+		return fmt.Errorf("%s: %w", comp, repo.ErrFeatureNotFound)
+	}
+}
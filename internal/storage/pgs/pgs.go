@@ -9,11 +9,13 @@ import (
 
 // Storage is a postgres database storage handler.
 type Storage struct {
-	dbPool *pgxpool.Pool
+	dbPool        *pgxpool.Pool
+	versionsLimit int
 }
 
 // New returns a new Storage instance.
-func New(ctx context.Context, connectionString string) (*Storage, error) {
+// versionsLimit is the number of previous banner versions kept in the history, non-positive means unlimited.
+func New(ctx context.Context, connectionString string, versionsLimit int) (*Storage, error) {
 	const comp = "storage.pgs.New"
 
 	dbPool, err := pgxpool.New(ctx, connectionString)
@@ -21,7 +23,7 @@ func New(ctx context.Context, connectionString string) (*Storage, error) {
 		return nil, fmt.Errorf("%s: %w", comp, err)
 	}
 
-	return &Storage{dbPool: dbPool}, nil
+	return &Storage{dbPool: dbPool, versionsLimit: versionsLimit}, nil
 }
 
 // Close closes the underlying connection to postgres database.
@@ -29,3 +31,8 @@ func (s *Storage) Close(_ context.Context) error {
 	s.dbPool.Close()
 	return nil
 }
+
+// PoolStat returns the current stats of the connection pool to postgres database.
+func (s *Storage) PoolStat() *pgxpool.Stat {
+	return s.dbPool.Stat()
+}
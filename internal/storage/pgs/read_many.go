@@ -5,28 +5,29 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"banners-management/internal/model/entity"
+	"banners-management/internal/storage/repo"
 )
 
-// BannersByFeatureTag returns slice of banners associated with given feature and tag.
-// It respects the limit and offset parameters, if provided,
-// where limit is the maximum number of banners to return and
-// offset is the number of banners to skip.
-// All the parameters are optional. If they're set to nil, they're ignored.
-func (s *Storage) BannersByFeatureTag(
+// Banners returns a page of banners, filtered, sorted and paginated by the query q.
+// The banners are ordered by the sort field and then by id, so the keyset cursor always points to a single banner.
+// To find out, whether there is a next page, one more banner than the limit is fetched.
+// The storage always returns the last revision of the data, so the useLastRevision flag is ignored.
+func (s *Storage) Banners(
 	ctx context.Context,
-	featureID, tagID *int64,
-	limit, offset *int,
+	q repo.BannerQuery,
 	_ *bool,
-) ([]*entity.Banner, error) {
-	const comp = "storage.pgs.BannersByFeatureTag"
+) (*repo.BannerPage, repo.Revision, error) {
+	const comp = "storage.pgs.Banners"
 
-	q, args := buildReadManyQuery(featureID, tagID, limit, offset)
+	rev := repo.Revision{FetchedAt: time.Now()}
+	query, args := buildReadManyQuery(q)
 
-	rows, err := s.dbPool.Query(ctx, q, args...)
+	rows, err := s.dbPool.Query(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("%s: %w", comp, err)
+		return nil, rev, fmt.Errorf("%s: %w", comp, err)
 	}
 
 	defer rows.Close()
@@ -41,13 +42,16 @@ func (s *Storage) BannersByFeatureTag(
 			&buf.Text,
 			&buf.URL,
 			&buf.IsActive,
+			&buf.Priority,
 			&buf.FeatureID,
 			&tagID,
+			&buf.ActiveFrom,
+			&buf.ActiveUntil,
 			&buf.CreatedAt,
 			&buf.UpdatedAt,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("%s: %w", comp, err)
+			return nil, rev, fmt.Errorf("%s: %w", comp, err)
 		}
 		if len(banners) > 0 && buf.ID == banners[len(banners)-1].ID {
 			banners[len(banners)-1].TagIDs = append(banners[len(banners)-1].TagIDs, tagID)
@@ -57,66 +61,195 @@ func (s *Storage) BannersByFeatureTag(
 			buf = new(entity.Banner)
 		}
 	}
+	if err := rows.Err(); err != nil {
+		return nil, rev, fmt.Errorf("%s: %w", comp, err)
+	}
+
+	page := &repo.BannerPage{Banners: banners}
+	if q.Limit != nil && len(banners) > *q.Limit {
+		page.Banners = banners[:*q.Limit]
+		page.Next = cursorOf(page.Banners[len(page.Banners)-1], q)
+	}
+
+	if q.WithTotal {
+		countQuery, countArgs := buildCountQuery(q)
+		page.Total = new(int64)
+		if err := s.dbPool.QueryRow(ctx, countQuery, countArgs...).Scan(page.Total); err != nil {
+			return nil, rev, fmt.Errorf("%s: %w", comp, err)
+		}
+	}
+
+	return page, rev, nil
+}
+
+// cursorOf returns the cursor, pointing right after the banner b in the order of the query q.
+func cursorOf(b *entity.Banner, q repo.BannerQuery) *repo.BannerCursor {
+	c := &repo.BannerCursor{Sort: q.Sort, Desc: q.Desc, ID: b.ID}
+	switch q.Sort {
+	case repo.SortByCreatedAt:
+		c.Value = b.CreatedAt.UTC().Format(time.RFC3339Nano)
+	case repo.SortByUpdatedAt:
+		c.Value = b.UpdatedAt.UTC().Format(time.RFC3339Nano)
+	case repo.SortByTitle:
+		c.Value = b.Title
+	case repo.SortByID:
+	}
 
-	return banners, nil
+	return c
 }
 
-// buildReadManyQuery builds a sql query based on the provided parameters.
+// buildReadManyQuery builds a sql query based on the provided query.
 // It returns the query string and the arguments to be passed to the query.
-// If a parameter is nil, it's ignored.
-func buildReadManyQuery(featureID, tagID *int64, limit, offset *int) (string, []any) {
+func buildReadManyQuery(q repo.BannerQuery) (string, []any) {
 	var sb strings.Builder
 
+	order := orderBy(q)
 	sb.WriteString(`WITH banners AS (`)
-	q, args := getBannersQuery(featureID, tagID, limit, offset)
-	sb.WriteString(q)
-	sb.WriteString(`) SELECT id, title, text, url, is_active, feature_id, tag_id, created_at, updated_at
+	query, args := getBannersQuery(q, order)
+	sb.WriteString(query)
+	sb.WriteString(`) SELECT id, title, text, url, is_active, priority, feature_id, tag_id, active_from, active_until,
+				created_at, updated_at
 			FROM banners JOIN banner_tag bt ON banners.id = bt.banner_id
-			ORDER BY id, tag_id;`)
+			ORDER BY `)
+	sb.WriteString(order)
+	sb.WriteString(`, tag_id;`)
 
 	return sb.String(), args
 }
 
-func getBannersQuery(featureID, tagID *int64, limit, offset *int) (string, []any) {
+// buildCountQuery builds a sql query, counting the banners matching the filters of the query q.
+func buildCountQuery(q repo.BannerQuery) (string, []any) {
+	var sb strings.Builder
+
+	sb.WriteString(`SELECT count(*) FROM banner b`)
+	q.After = nil
+	args := writeFilters(&sb, q, make([]any, 0, 8))
+
+	return sb.String(), args
+}
+
+func getBannersQuery(q repo.BannerQuery, order string) (string, []any) {
 	var (
-		args = make([]any, 0, 4)
+		args = make([]any, 0, 12)
 		sb   strings.Builder
 	)
 
-	sb.WriteString(`SELECT id, title, text, url, is_active, feature_id, created_at, updated_at FROM banner b`)
+	sb.WriteString(`SELECT id, title, text, url, is_active, priority, feature_id, active_from, active_until,
+			created_at, updated_at
+		FROM banner b`)
+
+	args = writeFilters(&sb, q, args)
+
+	sb.WriteString(" ORDER BY ")
+	sb.WriteString(order)
 
-	if tagID != nil {
-		sb.WriteString(` JOIN banner_tag bt ON b.id = bt.banner_id`)
+	if q.Limit != nil {
+		sb.WriteString(" LIMIT $")
+		sb.WriteString(strconv.Itoa(len(args)+1) + " ")
+		args = append(args, *q.Limit+1)
 	}
 
-	if featureID != nil {
-		sb.WriteString(" WHERE b.feature_id = $")
+	if q.Offset != nil {
+		sb.WriteString(" OFFSET $")
 		sb.WriteString(strconv.Itoa(len(args)+1) + " ")
-		args = append(args, *featureID)
+		args = append(args, *q.Offset)
+	}
+
+	return sb.String(), args
+}
+
+// writeFilters writes the WHERE clause of the list query q to the sb.
+// If q.After is not nil, only the banners following the cursor are selected.
+// It returns args along with the appended arguments of the written clause.
+func writeFilters(sb *strings.Builder, q repo.BannerQuery, args []any) []any {
+	conds := make([]string, 0, 9)
+	arg := func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	if q.FeatureID != nil {
+		conds = append(conds, "b.feature_id = "+arg(*q.FeatureID))
 	}
 
-	if tagID != nil {
-		if featureID != nil {
-			sb.WriteString(" AND ")
+	if len(q.TagIDs) > 0 {
+		tags := arg(q.TagIDs)
+		if q.AllTags {
+			conds = append(conds, fmt.Sprintf(
+				"(SELECT count(*) FROM banner_tag bt WHERE bt.banner_id = b.id AND bt.tag_id = ANY(%s)) = %s",
+				tags, arg(len(q.TagIDs))))
 		} else {
-			sb.WriteString(" WHERE ")
+			conds = append(conds,
+				"EXISTS (SELECT 1 FROM banner_tag bt WHERE bt.banner_id = b.id AND bt.tag_id = ANY("+tags+"))")
 		}
-		sb.WriteString(" bt.tag_id = $")
-		sb.WriteString(strconv.Itoa(len(args)+1) + " ")
-		args = append(args, *tagID)
 	}
 
-	if limit != nil {
-		sb.WriteString(" LIMIT $")
-		sb.WriteString(strconv.Itoa(len(args)+1) + " ")
-		args = append(args, *limit)
+	if q.IsActive != nil {
+		conds = append(conds, "b.is_active = "+arg(*q.IsActive))
 	}
 
-	if offset != nil {
-		sb.WriteString(" OFFSET $")
-		sb.WriteString(strconv.Itoa(len(args)+1) + " ")
-		args = append(args, *offset)
+	ranges := []struct {
+		cond string
+		t    *time.Time
+	}{
+		{"b.created_at >= ", q.CreatedFrom},
+		{"b.created_at < ", q.CreatedTo},
+		{"b.updated_at >= ", q.UpdatedFrom},
+		{"b.updated_at < ", q.UpdatedTo},
+	}
+	for _, rng := range ranges {
+		if rng.t != nil {
+			conds = append(conds, rng.cond+arg(*rng.t))
+		}
 	}
 
-	return sb.String(), args
+	if q.Search != "" {
+		// the expression must match the one of the banner_search_idx index
+		conds = append(conds,
+			"to_tsvector('simple', b.title || ' ' || coalesce(b.text, '')) @@ websearch_to_tsquery('simple', "+
+				arg(q.Search)+")")
+	}
+
+	if q.After != nil {
+		cmp := " > "
+		if q.After.Desc {
+			cmp = " < "
+		}
+		if q.After.Sort == repo.SortByID {
+			conds = append(conds, "b.id"+cmp+arg(q.After.ID))
+		} else {
+			value := arg(q.After.Value)
+			conds = append(conds, fmt.Sprintf("(b.%s, b.id)%s(%s::%s, %s)",
+				q.After.Sort, cmp, value, sortColumnType(q.After.Sort), arg(q.After.ID)))
+		}
+	}
+
+	if len(conds) > 0 {
+		sb.WriteString(" WHERE ")
+		sb.WriteString(strings.Join(conds, " AND "))
+	}
+
+	return args
+}
+
+// orderBy returns the ORDER BY clause expressions of the query q.
+func orderBy(q repo.BannerQuery) string {
+	dir := ""
+	if q.Desc {
+		dir = " DESC"
+	}
+	if q.Sort == repo.SortByID || q.Sort == "" {
+		return "id" + dir
+	}
+
+	return string(q.Sort) + dir + ", id" + dir
+}
+
+// sortColumnType returns the sql type of the column, the banners are sorted by.
+func sortColumnType(sort repo.BannerSort) string {
+	if sort == repo.SortByTitle {
+		return "text"
+	}
+
+	return "timestamptz"
 }
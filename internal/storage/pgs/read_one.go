@@ -1,78 +1,231 @@
 package pgs
 
 import (
-	"banners-management/internal/storage/repo"
 	"context"
+	"errors"
 	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
 
 	"banners-management/internal/model/entity"
+	"banners-management/internal/storage/repo"
 )
 
-// BannerByFeatureTag finds a banner by provided featureID and tagID.
+// BannerByFeatureTag finds the banner by provided featureID and tagID, that is shown to users.
+// If several banners share the feature and the tag, they're resolved by the resolutionOrder.
+// The storage always returns the last revision of the data, so the useLastRevision flag is ignored.
 func (s *Storage) BannerByFeatureTag(
 	ctx context.Context,
 	featureID, tagID int64,
 	_ bool,
-) (*entity.Banner, error) {
+) (*entity.Banner, repo.Revision, error) {
 	const comp = "storage.pgs.BannerByFeatureTag"
 
-	rows, err := s.dbPool.Query(ctx,
-		`WITH banners AS (
-				SELECT id, title, text, url, is_active, feature_id, created_at, updated_at 
-				FROM banner b JOIN banner_tag bt ON b.id = bt.banner_id 
-				WHERE b.feature_id = $1 AND bt.tag_id = $2
-			) SELECT id, title, text, url, is_active, feature_id, tag_id, created_at, updated_at
-			FROM banners JOIN banner_tag bt ON banners.id = bt.banner_id
-			ORDER BY id, tag_id;`,
-		featureID, tagID)
+	rev := repo.Revision{FetchedAt: time.Now()}
+	b, err := s.bannerByFeatureTag(ctx, featureID, tagID)
+	if err != nil {
+		return nil, rev, fmt.Errorf("%s: %w", comp, err)
+	}
+
+	return b, rev, nil
+}
 
+// resolutionOrder is the ORDER BY clause expressions, that order the banners, sharing a feature and a tag,
+// from the one shown to users. The banners, that can be shown now (active and within the activation window),
+// go first, then the ones with the highest priority, and then the newest ones.
+const resolutionOrder = `(b.is_active
+		AND (b.active_from IS NULL OR b.active_from <= NOW())
+		AND (b.active_until IS NULL OR NOW() < b.active_until)) DESC,
+	b.priority DESC, b.created_at DESC NULLS LAST, b.id DESC`
+
+// variantsColumn is the select list expression, that aggregates the variants of the banner b, ordered by id,
+// to a JSON array, that is decoded into []entity.BannerVariant.
+const variantsColumn = `COALESCE((SELECT json_agg(json_build_object(
+		'ID', v.id, 'BannerID', v.banner_id, 'Title', v.title, 'Text', COALESCE(v.text, ''),
+		'URL', COALESCE(v.url, ''), 'Weight', v.weight, 'CreatedAt', v.created_at, 'UpdatedAt', v.updated_at
+	) ORDER BY v.id) FROM banner_variant v WHERE v.banner_id = b.id), '[]')`
+
+// nextResolutionChangeColumn returns the select list expression, that finds the nearest future bound
+// of the activation windows of all the banners of the feature and the tag given by the SQL expressions,
+// since the resolution of the banner shown to users may change at that moment.
+func nextResolutionChangeColumn(featureID, tagID string) string {
+	return `(SELECT MIN(w.at)
+			FROM banner wb JOIN banner_tag wbt ON wb.id = wbt.banner_id,
+				LATERAL (VALUES (wb.active_from), (wb.active_until)) AS w(at)
+			WHERE wb.feature_id = ` + featureID + ` AND wbt.tag_id = ` + tagID + ` AND w.at > NOW())`
+}
+
+// bannerByFeatureTag finds the banner by provided featureID and tagID, that is shown to users, with its variants.
+// If several banners share the feature and the tag, the first one in the resolutionOrder is returned,
+// along with the moment the resolution may change.
+func (s *Storage) bannerByFeatureTag(ctx context.Context, featureID, tagID int64) (*entity.Banner, error) {
+	const comp = "storage.pgs.bannerByFeatureTag"
+
+	banner := new(entity.Banner)
+	err := s.dbPool.QueryRow(ctx,
+		`SELECT b.id, b.title, b.text, b.url, b.is_active, b.priority, b.feature_id,
+				ARRAY(SELECT tag_id FROM banner_tag WHERE banner_id = b.id ORDER BY tag_id),
+				b.active_from, b.active_until, b.created_at, b.updated_at, `+variantsColumn+`,
+				`+nextResolutionChangeColumn("$1", "$2")+`
+			FROM banner b JOIN banner_tag bt ON b.id = bt.banner_id
+			WHERE b.feature_id = $1 AND bt.tag_id = $2
+			ORDER BY `+resolutionOrder+`
+			LIMIT 1;`,
+		featureID, tagID,
+	).Scan(
+		&banner.ID,
+		&banner.Title,
+		&banner.Text,
+		&banner.URL,
+		&banner.IsActive,
+		&banner.Priority,
+		&banner.FeatureID,
+		&banner.TagIDs,
+		&banner.ActiveFrom,
+		&banner.ActiveUntil,
+		&banner.CreatedAt,
+		&banner.UpdatedAt,
+		&banner.Variants,
+		&banner.NextResolutionChange,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%s: %w", comp, repo.ErrBannerNotFound)
+	} else if err != nil {
+		return nil, fmt.Errorf("%s: %w", comp, err)
+	}
+
+	return banner, nil
+}
+
+// BannerByID finds a banner by its id.
+func (s *Storage) BannerByID(ctx context.Context, id int64) (*entity.Banner, error) {
+	const comp = "storage.pgs.BannerByID"
+
+	banner, err := bannerByID(ctx, s.dbPool, id, false)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", comp, err)
 	}
 
-	banner := new(entity.Banner)
-	tagIDs := make([]int64, 1, 16)
-	if !rows.Next() {
+	return banner, nil
+}
+
+// rowQuerier is either the pool or a tx.
+type rowQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// bannerByID finds a banner by its id with q. If forUpdate is true, the banner row is locked
+// until the end of the tx, so its state can't change before the write that follows.
+func bannerByID(ctx context.Context, q rowQuerier, id int64, forUpdate bool) (*entity.Banner, error) {
+	lock := ""
+	if forUpdate {
+		lock = " FOR UPDATE"
+	}
+
+	banner, err := scanBanner(q.QueryRow(ctx, `SELECT `+bannerColumns+` FROM banner b WHERE b.id = $1`+lock+`;`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, repo.ErrBannerNotFound
+	} else if err != nil {
+		return nil, err
 	}
-	err = rows.Scan(
+
+	return banner, nil
+}
+
+// bannerColumns is the select list of the banner b without its variants, that is scanned by scanBanner.
+const bannerColumns = `b.id, b.title, b.text, b.url, b.is_active, b.priority, b.feature_id,
+	ARRAY(SELECT tag_id FROM banner_tag WHERE banner_id = b.id ORDER BY tag_id),
+	b.active_from, b.active_until, b.created_at, b.updated_at`
+
+// scanBanner scans the row, selected with bannerColumns, into a new banner.
+func scanBanner(row pgx.Row) (*entity.Banner, error) {
+	banner := new(entity.Banner)
+	err := row.Scan(
 		&banner.ID,
 		&banner.Title,
 		&banner.Text,
 		&banner.URL,
 		&banner.IsActive,
+		&banner.Priority,
 		&banner.FeatureID,
-		&tagIDs[0],
+		&banner.TagIDs,
+		&banner.ActiveFrom,
+		&banner.ActiveUntil,
 		&banner.CreatedAt,
 		&banner.UpdatedAt,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("%s: %w", comp, err)
+		return nil, err
+	}
+
+	return banner, nil
+}
+
+// BannersByFeatureTags finds the banners with their variants by the provided pairs of featureID and tagID
+// in a single query.
+// The banners are returned in the order of keys, with nil for the keys without a banner.
+// The banners, sharing a feature and a tag, are resolved the same way as by BannerByFeatureTag.
+// The storage always returns the last revision of the data, so the useLastRevision flag is ignored.
+func (s *Storage) BannersByFeatureTags(
+	ctx context.Context,
+	keys []repo.FeatureTag,
+	_ bool,
+) ([]*entity.Banner, repo.Revision, error) {
+	const comp = "storage.pgs.BannersByFeatureTags"
+
+	rev := repo.Revision{FetchedAt: time.Now()}
+	featureIDs := make([]int64, len(keys))
+	tagIDs := make([]int64, len(keys))
+	for i, k := range keys {
+		featureIDs[i], tagIDs[i] = k.FeatureID, k.TagID
 	}
-	i := 1
-	id := new(int64)
+
+	rows, err := s.dbPool.Query(ctx,
+		`SELECT DISTINCT ON (k.ord) k.ord, b.id, b.title, b.text, b.url, b.is_active, b.priority, b.feature_id,
+				ARRAY(SELECT tag_id FROM banner_tag WHERE banner_id = b.id ORDER BY tag_id),
+				b.active_from, b.active_until, b.created_at, b.updated_at, `+variantsColumn+`,
+				`+nextResolutionChangeColumn("k.feature_id", "k.tag_id")+`
+			FROM unnest($1::bigint[], $2::bigint[]) WITH ORDINALITY AS k(feature_id, tag_id, ord)
+				JOIN banner_tag bt ON bt.tag_id = k.tag_id
+				JOIN banner b ON b.id = bt.banner_id AND b.feature_id = k.feature_id
+			ORDER BY k.ord, `+resolutionOrder+`;`,
+		featureIDs, tagIDs)
+	if err != nil {
+		return nil, rev, fmt.Errorf("%s: %w", comp, err)
+	}
+	defer rows.Close()
+
+	banners := make([]*entity.Banner, len(keys))
 	for rows.Next() {
-		tagIDs = append(tagIDs, 0)
-		err = rows.Scan(
-			id,
-			nil,
-			nil,
-			nil,
-			nil,
-			nil,
-			&tagIDs[i],
-			nil,
-			nil,
+		var ord int
+		banner := new(entity.Banner)
+		err := rows.Scan(
+			&ord,
+			&banner.ID,
+			&banner.Title,
+			&banner.Text,
+			&banner.URL,
+			&banner.IsActive,
+			&banner.Priority,
+			&banner.FeatureID,
+			&banner.TagIDs,
+			&banner.ActiveFrom,
+			&banner.ActiveUntil,
+			&banner.CreatedAt,
+			&banner.UpdatedAt,
+			&banner.Variants,
+			&banner.NextResolutionChange,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("%s: %w", comp, err)
+			return nil, rev, fmt.Errorf("%s: %w", comp, err)
 		}
-		if *id != banner.ID {
-			return nil, fmt.Errorf("%s: %w", comp, repo.ErrBannerNotUnique)
-		}
-		i++
+		// the ordinality starts from 1
+		banners[ord-1] = banner
+	}
+	if err := rows.Err(); err != nil {
+		return nil, rev, fmt.Errorf("%s: %w", comp, err)
 	}
-	banner.TagIDs = tagIDs
 
-	return banner, nil
+	return banners, rev, nil
 }
@@ -6,7 +6,6 @@ import (
 	"fmt"
 
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgconn"
 
 	"banners-management/internal/model/entity"
 	"banners-management/internal/storage/pgs/common/bannertag"
@@ -29,36 +28,121 @@ func (s *Storage) SaveBanner(ctx context.Context, b *entity.Banner) (bannerID in
 		}
 	}()
 
+	bannerID, err = insertBanner(ctx, tx, b)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", comp, err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", comp, err)
+	}
+
+	return bannerID, nil
+}
+
+// SaveBanners saves the banners to the database in a single transaction.
+// Every banner is inserted within its own savepoint, so all the failed banners are reported
+// in repo.BulkError by their indices, and none of the banners is saved then.
+// If dryRun is true, the transaction is rolled back anyway, and nil IDs are returned.
+// It returns the IDs of the saved banners in the order of the banners.
+func (s *Storage) SaveBanners(ctx context.Context, bs []*entity.Banner, dryRun bool) (ids []int64, err error) {
+	const comp = "storage.pgs.SaveBanners"
+
+	tx, err := s.dbPool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", comp, err)
+	}
+	defer func() {
+		err := tx.Rollback(ctx)
+		if err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			ids, err = nil, fmt.Errorf("%s: %w", comp, err)
+		}
+	}()
+
+	ids = make([]int64, len(bs))
+	bulkErr := make(repo.BulkError)
+	for i, b := range bs {
+		id, err := insertBannerChecked(ctx, tx, b)
+		if errors.Is(err, repo.ErrBannerUnknownReference) {
+			bulkErr[i] = err
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("%s: %w", comp, err)
+		}
+		ids[i] = id
+	}
+
+	if len(bulkErr) > 0 {
+		return nil, fmt.Errorf("%s: %w", comp, bulkErr)
+	}
+	if dryRun {
+		return nil, nil
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", comp, err)
+	}
+
+	return ids, nil
+}
+
+// insertBannerChecked inserts the banner and its tags within a savepoint of transaction tx.
+// If the banner can't be inserted, the transaction is rolled back to the savepoint, so it can go on.
+func insertBannerChecked(ctx context.Context, tx pgx.Tx, b *entity.Banner) (id int64, err error) {
+	sp, err := tx.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if rbErr := sp.Rollback(ctx); rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) {
+			id, err = 0, rbErr
+		}
+	}()
+
+	id, err = insertBanner(ctx, sp, b)
+	if err != nil {
+		return 0, err
+	}
+
+	return id, sp.Commit(ctx)
+}
+
+// insertBanner inserts the banner and its tags within transaction tx.
+// It returns the ID of the inserted banner.
+func insertBanner(ctx context.Context, tx pgx.Tx, b *entity.Banner) (int64, error) {
 	row := tx.QueryRow(
 		ctx,
-		`INSERT INTO Banner (title, text, url, is_active, feature_id, created_at, updated_at)
-				VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id;`,
+		`INSERT INTO Banner (title, text, url, is_active, priority, feature_id, active_from, active_until,
+				created_at, updated_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id;`,
 		b.Title,
 		b.Text,
 		b.URL,
 		b.IsActive,
+		b.Priority,
 		b.FeatureID,
+		b.ActiveFrom,
+		b.ActiveUntil,
 		b.CreatedAt,
 		b.UpdatedAt,
 	)
 
-	err = row.Scan(&bannerID)
-	if err != nil {
-		return 0, fmt.Errorf("%s: %w", comp, err)
+	var bannerID int64
+	err := row.Scan(&bannerID)
+	if pgErrCode(err) == foreignKeyViolationCode {
+		return 0, repo.ErrBannerUnknownReference
+	} else if err != nil {
+		return 0, err
 	}
 
 	q := bannertag.InsertTagsQuery(bannerID, b.TagIDs)
 	_, err = tx.Exec(ctx, q)
-	if err != nil {
-		return 0, fmt.Errorf("%s: %w", comp, err)
-	}
-
-	err = tx.Commit(ctx)
-	pgErr := new(pgconn.PgError)
-	if errors.As(err, &pgErr) && pgErr.Code == "P0001" { // P0001 when trigger is fired
-		return 0, fmt.Errorf("%s: %w", comp, repo.ErrBannerAlreadyExists)
+	if pgErrCode(err) == foreignKeyViolationCode {
+		return 0, repo.ErrBannerUnknownReference
 	} else if err != nil {
-		return 0, fmt.Errorf("%s: %w", comp, err)
+		return 0, err
 	}
 
 	return bannerID, nil
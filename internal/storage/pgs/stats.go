@@ -0,0 +1,79 @@
+package pgs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"banners-management/internal/model/entity"
+)
+
+// AddBannerStats adds the impressions and the clicks to the stored daily stats in a single query.
+// The stats of the banners, that don't exist anymore, are skipped.
+func (s *Storage) AddBannerStats(ctx context.Context, stats []*entity.BannerStats) error {
+	const comp = "storage.pgs.AddBannerStats"
+
+	if len(stats) == 0 {
+		return nil
+	}
+
+	var (
+		bannerIDs   = make([]int64, len(stats))
+		variantIDs  = make([]int64, len(stats))
+		days        = make([]time.Time, len(stats))
+		impressions = make([]int64, len(stats))
+		clicks      = make([]int64, len(stats))
+	)
+	for i, st := range stats {
+		bannerIDs[i], variantIDs[i], days[i] = st.BannerID, st.VariantID, st.Day
+		impressions[i], clicks[i] = st.Impressions, st.Clicks
+	}
+
+	_, err := s.dbPool.Exec(ctx,
+		`INSERT INTO banner_stats (banner_id, variant_id, day, impressions, clicks)
+			SELECT u.banner_id, u.variant_id, u.day, u.impressions, u.clicks
+				FROM unnest($1::bigint[], $2::bigint[], $3::date[], $4::bigint[], $5::bigint[])
+					AS u(banner_id, variant_id, day, impressions, clicks)
+				WHERE EXISTS (SELECT 1 FROM banner b WHERE b.id = u.banner_id)
+			ON CONFLICT (banner_id, day, variant_id) DO UPDATE SET
+				impressions = banner_stats.impressions + EXCLUDED.impressions,
+				clicks = banner_stats.clicks + EXCLUDED.clicks;`,
+		bannerIDs, variantIDs, days, impressions, clicks)
+	if err != nil {
+		return fmt.Errorf("%s: %w", comp, err)
+	}
+
+	return nil
+}
+
+// BannerStats returns the daily stats of the banner within the days from and to inclusive ordered by day.
+// If variantID is nil, the stats of all the banner contents are summed up.
+func (s *Storage) BannerStats(
+	ctx context.Context,
+	bannerID int64,
+	variantID *int64,
+	from, to time.Time,
+) ([]*entity.BannerStats, error) {
+	const comp = "storage.pgs.BannerStats"
+
+	// NULL variant is the same as omitted one
+	rows, err := s.dbPool.Query(ctx,
+		`SELECT banner_id, COALESCE($2::INT, 0), day, SUM(impressions)::BIGINT, SUM(clicks)::BIGINT
+			FROM banner_stats
+			WHERE banner_id = $1 AND ($2::INT IS NULL OR variant_id = $2)
+			AND day >= $3 AND day <= $4
+			GROUP BY banner_id, day ORDER BY day;`,
+		bannerID, variantID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", comp, err)
+	}
+
+	stats, err := pgx.CollectRows(rows, pgx.RowToAddrOfStructByPos[entity.BannerStats])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", comp, err)
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,112 @@
+package pgs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"banners-management/internal/model/entity"
+	"banners-management/internal/storage/repo"
+)
+
+// SaveTag saves a tag with the provided id to the database.
+func (s *Storage) SaveTag(ctx context.Context, t *entity.Tag) error {
+	const comp = "storage.pgs.SaveTag"
+
+	_, err := s.dbPool.Exec(ctx,
+		`INSERT INTO tag (id, name, description) VALUES ($1, $2, $3);`,
+		t.ID, t.Name, t.Description)
+	if pgErrCode(err) == uniqueViolationCode {
+		return fmt.Errorf("%s: %w", comp, repo.ErrTagAlreadyExists)
+	} else if err != nil {
+		return fmt.Errorf("%s: %w", comp, err)
+	}
+
+	return nil
+}
+
+// Tag finds a tag by its id.
+func (s *Storage) Tag(ctx context.Context, id int64) (*entity.Tag, error) {
+	const comp = "storage.pgs.Tag"
+
+	t := new(entity.Tag)
+	err := s.dbPool.QueryRow(ctx,
+		`SELECT id, name, description FROM tag WHERE id = $1;`,
+		id,
+	).Scan(&t.ID, &t.Name, &t.Description)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%s: %w", comp, repo.ErrTagNotFound)
+	} else if err != nil {
+		return nil, fmt.Errorf("%s: %w", comp, err)
+	}
+
+	return t, nil
+}
+
+// Tags returns slice of tags ordered by id.
+// It respects the limit and offset parameters, if provided. If they're set to nil, they're ignored.
+func (s *Storage) Tags(ctx context.Context, limit, offset *int) ([]*entity.Tag, error) {
+	const comp = "storage.pgs.Tags"
+
+	// NULL limit and offset are the same as omitted ones
+	rows, err := s.dbPool.Query(ctx,
+		`SELECT id, name, description FROM tag ORDER BY id LIMIT $1 OFFSET $2;`,
+		limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", comp, err)
+	}
+
+	tags, err := pgx.CollectRows(rows, pgx.RowToAddrOfStructByPos[entity.Tag])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", comp, err)
+	}
+
+	return tags, nil
+}
+
+// UpdateTag updates tag t in the storage.
+func (s *Storage) UpdateTag(ctx context.Context, t *entity.UpdatableTag) error {
+	const comp = "storage.pgs.UpdateTag"
+
+	r, err := s.dbPool.Exec(ctx,
+		`UPDATE tag SET name = COALESCE($2, name), description = COALESCE($3, description) WHERE id = $1;`,
+		t.ID, t.Name, t.Description)
+	if err != nil {
+		return fmt.Errorf("%s: %w", comp, err)
+	}
+
+	if r.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", comp, repo.ErrTagNotFound)
+	}
+
+	return nil
+}
+
+// DeleteTag deletes tag by id. The tag that is used by any banner is not deleted.
+func (s *Storage) DeleteTag(ctx context.Context, id int64) error {
+	const comp = "storage.pgs.DeleteTag"
+
+	var deleted, exists bool
+	err := s.dbPool.QueryRow(ctx,
+		`WITH deleted AS (
+				DELETE FROM tag t WHERE t.id = $1
+					AND NOT EXISTS (SELECT 1 FROM banner_tag WHERE tag_id = t.id)
+				RETURNING id
+			) SELECT EXISTS (SELECT 1 FROM deleted), EXISTS (SELECT 1 FROM tag WHERE id = $1);`,
+		id,
+	).Scan(&deleted, &exists)
+	if err != nil {
+		return fmt.Errorf("%s: %w", comp, err)
+	}
+
+	switch {
+	case deleted:
+		return nil
+	case exists:
+		return fmt.Errorf("%s: %w", comp, repo.ErrTagInUse)
+	default:
+		return fmt.Errorf("%s: %w", comp, repo.ErrTagNotFound)
+	}
+}
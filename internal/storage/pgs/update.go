@@ -8,20 +8,23 @@ import (
 	"strings"
 
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgconn"
 
 	"banners-management/internal/model/entity"
 	"banners-management/internal/storage/pgs/common/bannertag"
 	"banners-management/internal/storage/repo"
 )
 
-// UpdateBanner updates banner b in the storage.
-func (s *Storage) UpdateBanner(ctx context.Context, b *entity.UpdatableBanner) (err error) {
+// UpdateBanner updates banner b in the storage. It returns the states of the banner before and after
+// the update, both read within the update transaction.
+func (s *Storage) UpdateBanner(
+	ctx context.Context,
+	b *entity.UpdatableBanner,
+) (before, after *entity.Banner, err error) {
 	const comp = "storage.pgs.UpdateBanner"
 
 	tx, err := s.dbPool.BeginTx(ctx, pgx.TxOptions{})
 	if err != nil {
-		return fmt.Errorf("%s: %w", comp, err)
+		return nil, nil, fmt.Errorf("%s: %w", comp, err)
 	}
 	defer func() {
 		err := tx.Rollback(ctx)
@@ -30,13 +33,14 @@ func (s *Storage) UpdateBanner(ctx context.Context, b *entity.UpdatableBanner) (
 		}
 	}()
 
-	t := tx.QueryRow(ctx, "SELECT id FROM banner WHERE id = $1 FOR UPDATE;", b.ID)
-	err = t.Scan(&b.ID)
+	before, err = bannerByID(ctx, tx, b.ID, true)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return fmt.Errorf("%s: %w", comp, repo.ErrBannerNotFound)
-		}
-		return fmt.Errorf("%s: %w", comp, err)
+		return nil, nil, fmt.Errorf("%s: %w", comp, err)
+	}
+
+	err = s.archiveBanner(ctx, tx, b.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", comp, err)
 	}
 
 	batch := new(pgx.Batch)
@@ -50,20 +54,27 @@ func (s *Storage) UpdateBanner(ctx context.Context, b *entity.UpdatableBanner) (
 
 	bres := tx.SendBatch(ctx, batch)
 	defer bres.Close()
-	_, err = bres.Exec()
-	if err != nil {
-		return fmt.Errorf("%s: %w", comp, err)
+	for range batch.Len() {
+		_, err = bres.Exec()
+		if pgErrCode(err) == foreignKeyViolationCode {
+			return nil, nil, fmt.Errorf("%s: %w", comp, repo.ErrBannerUnknownReference)
+		} else if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", comp, err)
+		}
 	}
 	_ = bres.Close()
+
+	after, err = bannerByID(ctx, tx, b.ID, false)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", comp, err)
+	}
+
 	err = tx.Commit(ctx)
-	pgErr := new(pgconn.PgError)
-	if errors.As(err, &pgErr) && pgErr.Code == "P0001" { // P0001 when trigger is fired
-		return fmt.Errorf("%s: %w", comp, repo.ErrBannerAlreadyExists)
-	} else if err != nil {
-		return fmt.Errorf("%s: %w", comp, err)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", comp, err)
 	}
 
-	return nil
+	return before, after, nil
 }
 
 // buildUpdateBannerQuery returns an SQL query for updating banner b and a slice of parameters for this query.
@@ -99,12 +110,30 @@ func buildUpdateBannerQuery(b *entity.UpdatableBanner) (string, []any) {
 		args = append(args, *b.IsActive)
 	}
 
+	if b.Priority != nil {
+		sb.WriteString("priority = $")
+		sb.WriteString(strconv.Itoa(len(args)+1) + ", ")
+		args = append(args, *b.Priority)
+	}
+
 	if b.URL != nil {
 		sb.WriteString("url = $")
 		sb.WriteString(strconv.Itoa(len(args)+1) + ", ")
 		args = append(args, *b.URL)
 	}
 
+	if b.ActiveFrom != nil {
+		sb.WriteString("active_from = $")
+		sb.WriteString(strconv.Itoa(len(args)+1) + ", ")
+		args = append(args, *b.ActiveFrom)
+	}
+
+	if b.ActiveUntil != nil {
+		sb.WriteString("active_until = $")
+		sb.WriteString(strconv.Itoa(len(args)+1) + ", ")
+		args = append(args, *b.ActiveUntil)
+	}
+
 	sb.WriteString("updated_at = NOW()")
 
 	query := sb.String()
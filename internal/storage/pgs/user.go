@@ -0,0 +1,54 @@
+package pgs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"banners-management/internal/model/entity"
+	"banners-management/internal/storage/repo"
+)
+
+// SaveUser saves a user to the database.
+// It returns the ID of the user if successful, otherwise error.
+func (s *Storage) SaveUser(ctx context.Context, u *entity.User) (int64, error) {
+	const comp = "storage.pgs.SaveUser"
+
+	scopes := u.Scopes
+	if scopes == nil {
+		scopes = []string{} // nil slice is encoded as NULL
+	}
+
+	var id int64
+	err := s.dbPool.QueryRow(ctx,
+		`INSERT INTO users (login, password_hash, role, scopes, created_at) VALUES ($1, $2, $3, $4, $5) RETURNING id;`,
+		u.Login, u.PasswordHash, u.Role, scopes, u.CreatedAt,
+	).Scan(&id)
+	if pgErrCode(err) == uniqueViolationCode {
+		return 0, fmt.Errorf("%s: %w", comp, repo.ErrUserAlreadyExists)
+	} else if err != nil {
+		return 0, fmt.Errorf("%s: %w", comp, err)
+	}
+
+	return id, nil
+}
+
+// UserByLogin finds a user by its login.
+func (s *Storage) UserByLogin(ctx context.Context, login string) (*entity.User, error) {
+	const comp = "storage.pgs.UserByLogin"
+
+	u := new(entity.User)
+	err := s.dbPool.QueryRow(ctx,
+		`SELECT id, login, password_hash, role, scopes, created_at FROM users WHERE login = $1;`,
+		login,
+	).Scan(&u.ID, &u.Login, &u.PasswordHash, &u.Role, &u.Scopes, &u.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%s: %w", comp, repo.ErrUserNotFound)
+	} else if err != nil {
+		return nil, fmt.Errorf("%s: %w", comp, err)
+	}
+
+	return u, nil
+}
@@ -0,0 +1,104 @@
+package pgs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"banners-management/internal/model/entity"
+	"banners-management/internal/storage/repo"
+)
+
+// BannerVariants returns all the variants of the banner with the given id ordered by id.
+func (s *Storage) BannerVariants(ctx context.Context, bannerID int64) ([]*entity.BannerVariant, error) {
+	const comp = "storage.pgs.BannerVariants"
+
+	var exists bool
+	err := s.dbPool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM banner WHERE id = $1);`, bannerID).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", comp, err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("%s: %w", comp, repo.ErrBannerNotFound)
+	}
+
+	rows, err := s.dbPool.Query(ctx,
+		`SELECT id, banner_id, title, COALESCE(text, ''), COALESCE(url, ''), weight, created_at, updated_at
+			FROM banner_variant WHERE banner_id = $1
+			ORDER BY id;`,
+		bannerID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", comp, err)
+	}
+
+	variants, err := pgx.CollectRows(rows, pgx.RowToAddrOfStructByPos[entity.BannerVariant])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", comp, err)
+	}
+
+	return variants, nil
+}
+
+// SaveBannerVariant saves the variant v of the banner with v.BannerID to the database.
+// It returns the ID of the variant if successful, otherwise error.
+func (s *Storage) SaveBannerVariant(ctx context.Context, v *entity.BannerVariant) (int64, error) {
+	const comp = "storage.pgs.SaveBannerVariant"
+
+	var id int64
+	err := s.dbPool.QueryRow(ctx,
+		`INSERT INTO banner_variant (banner_id, title, text, url, weight, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id;`,
+		v.BannerID, v.Title, v.Text, v.URL, v.Weight, v.CreatedAt, v.UpdatedAt,
+	).Scan(&id)
+	if pgErrCode(err) == foreignKeyViolationCode {
+		return 0, fmt.Errorf("%s: %w", comp, repo.ErrBannerNotFound)
+	} else if err != nil {
+		return 0, fmt.Errorf("%s: %w", comp, err)
+	}
+
+	return id, nil
+}
+
+// UpdateBannerVariant updates the variant v of the banner with v.BannerID in the database.
+// Nil fields of v are left unchanged.
+func (s *Storage) UpdateBannerVariant(ctx context.Context, v *entity.UpdatableBannerVariant) error {
+	const comp = "storage.pgs.UpdateBannerVariant"
+
+	// NULL parameters are the same as omitted ones
+	r, err := s.dbPool.Exec(ctx,
+		`UPDATE banner_variant SET
+				title = COALESCE($3, title),
+				text = COALESCE($4, text),
+				url = COALESCE($5, url),
+				weight = COALESCE($6, weight),
+				updated_at = NOW()
+			WHERE id = $1 AND banner_id = $2;`,
+		v.ID, v.BannerID, v.Title, v.Text, v.URL, v.Weight)
+	if err != nil {
+		return fmt.Errorf("%s: %w", comp, err)
+	}
+
+	if r.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", comp, repo.ErrVariantNotFound)
+	}
+
+	return nil
+}
+
+// DeleteBannerVariant deletes the variant by id of the banner with the given bannerID.
+func (s *Storage) DeleteBannerVariant(ctx context.Context, bannerID, variantID int64) error {
+	const comp = "storage.pgs.DeleteBannerVariant"
+
+	r, err := s.dbPool.Exec(ctx,
+		`DELETE FROM banner_variant WHERE id = $1 AND banner_id = $2;`, variantID, bannerID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", comp, err)
+	}
+
+	if r.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", comp, repo.ErrVariantNotFound)
+	}
+
+	return nil
+}
@@ -0,0 +1,182 @@
+package pgs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"banners-management/internal/model/entity"
+	"banners-management/internal/storage/pgs/common/bannertag"
+	"banners-management/internal/storage/repo"
+)
+
+// BannerVersions returns all the stored previous versions of the banner with the given id,
+// starting from the most recent one.
+func (s *Storage) BannerVersions(ctx context.Context, bannerID int64) ([]*entity.BannerVersion, error) {
+	const comp = "storage.pgs.BannerVersions"
+
+	var exists bool
+	err := s.dbPool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM banner WHERE id = $1);`, bannerID).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", comp, err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("%s: %w", comp, repo.ErrBannerNotFound)
+	}
+
+	rows, err := s.dbPool.Query(ctx,
+		`SELECT banner_id, version, title, text, url, is_active, priority, feature_id, tag_ids,
+				active_from, active_until, updated_at, archived_at
+			FROM banner_version WHERE banner_id = $1
+			ORDER BY version DESC;`,
+		bannerID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", comp, err)
+	}
+	defer rows.Close()
+
+	versions := make([]*entity.BannerVersion, 0)
+	for rows.Next() {
+		v := new(entity.BannerVersion)
+		err = rows.Scan(
+			&v.BannerID,
+			&v.Version,
+			&v.Title,
+			&v.Text,
+			&v.URL,
+			&v.IsActive,
+			&v.Priority,
+			&v.FeatureID,
+			&v.TagIDs,
+			&v.ActiveFrom,
+			&v.ActiveUntil,
+			&v.UpdatedAt,
+			&v.ArchivedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", comp, err)
+		}
+		versions = append(versions, v)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", comp, err)
+	}
+
+	return versions, nil
+}
+
+// RestoreBannerVersion makes the stored version of the banner with the given id current again.
+// The state of the banner before the restoration is archived as a new version,
+// so the restoration itself can be rolled back. It returns the states of the banner before and after
+// the restoration, both read within the restoration transaction.
+func (s *Storage) RestoreBannerVersion(
+	ctx context.Context,
+	bannerID int64,
+	version int,
+) (before, after *entity.Banner, err error) {
+	const comp = "storage.pgs.RestoreBannerVersion"
+
+	tx, err := s.dbPool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", comp, err)
+	}
+	defer func() {
+		err := tx.Rollback(ctx)
+		if err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			err = fmt.Errorf("%s: %w", comp, err)
+		}
+	}()
+
+	before, err = bannerByID(ctx, tx, bannerID, true)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", comp, err)
+	}
+
+	v := new(entity.BannerVersion)
+	err = tx.QueryRow(ctx,
+		`SELECT title, text, url, is_active, priority, feature_id, tag_ids, active_from, active_until
+			FROM banner_version WHERE banner_id = $1 AND version = $2;`,
+		bannerID, version,
+	).Scan(&v.Title, &v.Text, &v.URL, &v.IsActive, &v.Priority, &v.FeatureID, &v.TagIDs, &v.ActiveFrom, &v.ActiveUntil)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, fmt.Errorf("%s: %w", comp, repo.ErrVersionNotFound)
+		}
+		return nil, nil, fmt.Errorf("%s: %w", comp, err)
+	}
+
+	err = s.archiveBanner(ctx, tx, bannerID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", comp, err)
+	}
+
+	_, err = tx.Exec(ctx,
+		`UPDATE banner SET title = $1, text = $2, url = $3, is_active = $4, priority = $5, feature_id = $6,
+				active_from = $7, active_until = $8, updated_at = NOW()
+			WHERE id = $9;`,
+		v.Title, v.Text, v.URL, v.IsActive, v.Priority, v.FeatureID, v.ActiveFrom, v.ActiveUntil, bannerID)
+	if pgErrCode(err) == foreignKeyViolationCode {
+		return nil, nil, fmt.Errorf("%s: %w", comp, repo.ErrBannerUnknownReference)
+	} else if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", comp, err)
+	}
+
+	_, err = tx.Exec(ctx, "DELETE FROM banner_tag WHERE banner_id = $1;", bannerID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", comp, err)
+	}
+	if len(v.TagIDs) > 0 {
+		_, err = tx.Exec(ctx, bannertag.InsertTagsQuery(bannerID, v.TagIDs))
+		if pgErrCode(err) == foreignKeyViolationCode {
+			return nil, nil, fmt.Errorf("%s: %w", comp, repo.ErrBannerUnknownReference)
+		} else if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", comp, err)
+		}
+	}
+
+	after, err = bannerByID(ctx, tx, bannerID, false)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", comp, err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", comp, err)
+	}
+
+	return before, after, nil
+}
+
+// archiveBanner stores the current state of the banner with the given id as its next version
+// and removes the oldest versions that exceed the versions limit.
+// The banner row is expected to be locked by the tx.
+func (s *Storage) archiveBanner(ctx context.Context, tx pgx.Tx, bannerID int64) error {
+	_, err := tx.Exec(ctx,
+		`INSERT INTO banner_version (banner_id, version, title, text, url, is_active, priority, feature_id, tag_ids,
+				active_from, active_until, updated_at)
+			SELECT b.id,
+				COALESCE((SELECT MAX(version) FROM banner_version WHERE banner_id = b.id), 0) + 1,
+				b.title, b.text, b.url, b.is_active, b.priority, b.feature_id,
+				ARRAY(SELECT tag_id FROM banner_tag WHERE banner_id = b.id ORDER BY tag_id),
+				b.active_from, b.active_until, b.updated_at
+			FROM banner b WHERE b.id = $1;`,
+		bannerID)
+	if err != nil {
+		return err
+	}
+
+	if s.versionsLimit <= 0 {
+		return nil
+	}
+
+	_, err = tx.Exec(ctx,
+		`DELETE FROM banner_version
+			WHERE banner_id = $1 AND version <= (
+				SELECT MAX(version) FROM banner_version WHERE banner_id = $1
+			) - $2;`,
+		bannerID, s.versionsLimit)
+
+	return err
+}
@@ -0,0 +1,17 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"banners-management/internal/model/entity"
+)
+
+// APIKeyRepo is an interface that supports saving, retrieving and revoking API keys.
+type APIKeyRepo interface {
+	SaveAPIKey(ctx context.Context, k *entity.APIKey) (int64, error)
+	APIKeyByHash(ctx context.Context, keyHash string) (*entity.APIKey, error)
+	APIKeys(ctx context.Context, limit, offset *int) ([]*entity.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id int64) error
+	TouchAPIKey(ctx context.Context, id int64, usedAt time.Time) error
+}
@@ -0,0 +1,26 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"banners-management/internal/model/entity"
+)
+
+// AuditFilter narrows down the audit events. Nil fields are ignored.
+// From is inclusive, To is exclusive.
+type AuditFilter struct {
+	BannerID *int64
+	Actor    *string
+	From, To *time.Time
+}
+
+// AuditSaver is an interface that supports recording audit events.
+type AuditSaver interface {
+	SaveAuditEvent(ctx context.Context, e *entity.AuditEvent) (int64, error)
+}
+
+// AuditReader is an interface that supports reading audit events.
+type AuditReader interface {
+	AuditEvents(ctx context.Context, filter AuditFilter, limit, offset *int) ([]*entity.AuditEvent, error)
+}
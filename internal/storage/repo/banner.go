@@ -2,38 +2,174 @@ package repo
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
 	"banners-management/internal/model/entity"
 )
 
-// BannerSaver is an interface that supports banner creating.
+// BannerSaver is an interface that supports banner creating, one by one and in bulk.
+// SaveBanners saves all the banners or none of them. If some of them can't be saved, it returns BulkError.
+// If dryRun is true, nothing is saved, but the errors are reported the same way.
 type BannerSaver interface {
 	SaveBanner(ctx context.Context, banner *entity.Banner) (int64, error)
+	SaveBanners(ctx context.Context, banners []*entity.Banner, dryRun bool) ([]int64, error)
 }
 
-// BannerReader is an interface that supports retrieving banners by featureID and/or tagID.
+// BulkError is an error of the bulk operation. It holds the errors of the failed items by their indices.
+type BulkError map[int]error
+
+func (e BulkError) Error() string {
+	items := make([]string, 0, len(e))
+	for i, err := range e {
+		items = append(items, fmt.Sprintf("%d: %s", i, err))
+	}
+
+	return "bulk operation failed: " + strings.Join(items, "; ")
+}
+
+// BannerExporter is an interface that supports iterating over all the banners ordered by id.
+// If fn returns an error, the iteration is stopped and the error is returned.
+type BannerExporter interface {
+	EachBanner(ctx context.Context, fn func(b *entity.Banner) error) error
+}
+
+// Revision describes how fresh the data returned by BannerReader is.
+type Revision struct {
+	// Cached is true if the data was served from cache and may be outdated.
+	Cached bool
+	// FetchedAt is the moment the data was read from the persistent storage.
+	FetchedAt time.Time
+}
+
+// BannerSort is a field the list of banners is sorted by.
+type BannerSort string
+
+const (
+	SortByID        BannerSort = "id"
+	SortByCreatedAt BannerSort = "created_at"
+	SortByUpdatedAt BannerSort = "updated_at"
+	SortByTitle     BannerSort = "title"
+)
+
+// BannerQuery describes a list of banners. Nil and empty fields are ignored.
+// The banners are filtered by TagIDs having any of them, or all of them, if AllTags is true.
+// The time ranges include their lower bounds (CreatedFrom, UpdatedFrom) and exclude the upper ones.
+// Search is a full-text search query over the title and the text of the banners in the web search syntax.
+// The banners are ordered by Sort and then by id, so the order is always total. Empty Sort means SortByID.
+// After is a keyset cursor: only the banners, following it in the order, are listed.
+// If WithTotal is true, the number of the banners matching the filters regardless of the pagination is counted.
+type BannerQuery struct {
+	FeatureID   *int64     `json:"feature_id,omitempty"`
+	TagIDs      []int64    `json:"tag_ids,omitempty"`
+	AllTags     bool       `json:"all_tags,omitempty"`
+	IsActive    *bool      `json:"is_active,omitempty"`
+	CreatedFrom *time.Time `json:"created_from,omitempty"`
+	CreatedTo   *time.Time `json:"created_to,omitempty"`
+	UpdatedFrom *time.Time `json:"updated_from,omitempty"`
+	UpdatedTo   *time.Time `json:"updated_to,omitempty"`
+	Search      string     `json:"search,omitempty"`
+
+	Limit     *int          `json:"limit,omitempty"`
+	Offset    *int          `json:"offset,omitempty"`
+	Sort      BannerSort    `json:"sort,omitempty"`
+	Desc      bool          `json:"desc,omitempty"`
+	After     *BannerCursor `json:"after,omitempty"`
+	WithTotal bool          `json:"with_total,omitempty"`
+}
+
+// BannerCursor is a position in the ordered list of banners, that is right after the banner
+// with the sort Value and the ID. Value is empty for SortByID.
+type BannerCursor struct {
+	Sort  BannerSort `json:"s"`
+	Desc  bool       `json:"d,omitempty"`
+	Value string     `json:"v,omitempty"`
+	ID    int64      `json:"id"`
+}
+
+// BannerPage is a page of the list of banners.
+// Next is the cursor of the next page, it's nil if there are no more banners or the list is not limited.
+// Total is counted only if requested.
+type BannerPage struct {
+	Banners []*entity.Banner
+	Next    *BannerCursor
+	Total   *int64
+}
+
+// FeatureTag is a pair of featureID and tagID, that identifies a banner.
+type FeatureTag struct {
+	FeatureID int64
+	TagID     int64
+}
+
+// BannerReader is an interface that supports retrieving a banner by featureID and tagID,
+// several banners by the pairs of them at once, and a list of banners by BannerQuery.
+// BannersByFeatureTags returns the banners in the order of keys, with nil for the keys without a banner.
+// If useLastRevision is false, the implementation is allowed to return outdated data.
 type BannerReader interface {
 	BannerByFeatureTag(
 		ctx context.Context,
 		featureID, tagID int64,
 		useLastRevision bool,
-	) (*entity.Banner, error)
+	) (*entity.Banner, Revision, error)
 
-	BannersByFeatureTag(
+	BannersByFeatureTags(
 		ctx context.Context,
-		featureID, tagID *int64,
-		limit, offset *int,
-		lastRevision *bool,
-	) ([]*entity.Banner, error)
+		keys []FeatureTag,
+		useLastRevision bool,
+	) ([]*entity.Banner, Revision, error)
+
+	Banners(ctx context.Context, q BannerQuery, useLastRevision *bool) (*BannerPage, Revision, error)
 }
 
-// BannerDeleter is an interface that supports deleting banners by id and by featureID and tagID.
+// BannerByIDReader is an interface that supports retrieving banners by id.
+type BannerByIDReader interface {
+	BannerByID(ctx context.Context, id int64) (*entity.Banner, error)
+}
+
+// BannerDeleter is an interface that supports deleting banners by id, by featureID and tagID,
+// and in bulk by featureID or tagID. DeleteBanner returns the state of the deleted banner.
+// The bulk deletions return the states of the affected banners before the deletion, which are empty
+// if nothing matches. DeleteByTag deletes only the banners without other tags, and the rest of
+// the affected banners just lose the tag.
 type BannerDeleter interface {
-	DeleteBanner(ctx context.Context, bannerID int64) error
-	DeleteByFeatureTag(ctx context.Context, featureID, tagID int64) error
+	DeleteBanner(ctx context.Context, bannerID int64) (*entity.Banner, error)
+	DeleteByFeatureTag(ctx context.Context, featureID, tagID int64) ([]*entity.Banner, error)
+	DeleteByFeature(ctx context.Context, featureID int64) ([]*entity.Banner, error)
+	DeleteByTag(ctx context.Context, tagID int64) ([]*entity.Banner, error)
 }
 
 // BannerUpdater is an interface that supports updating banners.
+// UpdateBanner returns the states of the banner before and after the update.
 type BannerUpdater interface {
-	UpdateBanner(ctx context.Context, banner *entity.UpdatableBanner) error
+	UpdateBanner(ctx context.Context, banner *entity.UpdatableBanner) (before, after *entity.Banner, err error)
+}
+
+// BannerVersioner is an interface that supports retrieving and restoring previous banner versions.
+// RestoreBannerVersion returns the states of the banner before and after the restoration.
+type BannerVersioner interface {
+	BannerVersions(ctx context.Context, bannerID int64) ([]*entity.BannerVersion, error)
+	RestoreBannerVersion(ctx context.Context, bannerID int64, version int) (before, after *entity.Banner, err error)
+}
+
+// BannerVariantStore is an interface that supports managing the variants of the banners.
+// BannerVariants returns the variants ordered by id. ErrVariantNotFound is returned, if the variant
+// doesn't belong to the banner.
+type BannerVariantStore interface {
+	BannerVariants(ctx context.Context, bannerID int64) ([]*entity.BannerVariant, error)
+	SaveBannerVariant(ctx context.Context, variant *entity.BannerVariant) (int64, error)
+	UpdateBannerVariant(ctx context.Context, variant *entity.UpdatableBannerVariant) error
+	DeleteBannerVariant(ctx context.Context, bannerID, variantID int64) error
+}
+
+// BannerStats is an interface that supports adding up the daily banner stats and reading them.
+// AddBannerStats adds the impressions and the clicks to the already stored ones. The stats of the deleted
+// banners are skipped.
+// BannerStats returns the stats of the banner ordered by day within the days from and to inclusive.
+// The stats of the variant by variantID are returned, or the stats summed over all the banner contents,
+// if variantID is nil. The days without the stats are omitted.
+type BannerStats interface {
+	AddBannerStats(ctx context.Context, stats []*entity.BannerStats) error
+	BannerStats(ctx context.Context, bannerID int64, variantID *int64, from, to time.Time) ([]*entity.BannerStats, error)
 }
@@ -7,7 +7,23 @@ import (
 )
 
 var (
-	ErrBannerNotFound      = errors.New(msg.BannerNotFound)
-	ErrBannerAlreadyExists = errors.New(msg.BannerAlreadyExists)
-	ErrBannerNotUnique     = errors.New(msg.BannerNotUnique)
+	ErrBannerNotFound         = errors.New(msg.BannerNotFound)
+	ErrBannerUnknownReference = errors.New(msg.BannerUnknownReference)
+	ErrVersionNotFound        = errors.New(msg.BannerVersionNotFound)
+	ErrVariantNotFound        = errors.New(msg.BannerVariantNotFound)
+	ErrJobNotFound            = errors.New(msg.JobNotFound)
+
+	ErrFeatureNotFound      = errors.New(msg.FeatureNotFound)
+	ErrFeatureAlreadyExists = errors.New(msg.FeatureAlreadyExists)
+	ErrFeatureInUse         = errors.New(msg.FeatureInUse)
+
+	ErrTagNotFound      = errors.New(msg.TagNotFound)
+	ErrTagAlreadyExists = errors.New(msg.TagAlreadyExists)
+	ErrTagInUse         = errors.New(msg.TagInUse)
+
+	ErrUserNotFound      = errors.New(msg.UserNotFound)
+	ErrUserAlreadyExists = errors.New(msg.UserAlreadyExists)
+	ErrSessionNotFound   = errors.New(msg.SessionNotFound)
+
+	ErrAPIKeyNotFound = errors.New(msg.APIKeyNotFound)
 )
@@ -0,0 +1,16 @@
+package repo
+
+import (
+	"context"
+
+	"banners-management/internal/model/entity"
+)
+
+// FeatureRepo is an interface that supports feature CRUD operations.
+type FeatureRepo interface {
+	SaveFeature(ctx context.Context, f *entity.Feature) error
+	Feature(ctx context.Context, id int64) (*entity.Feature, error)
+	Features(ctx context.Context, limit, offset *int) ([]*entity.Feature, error)
+	UpdateFeature(ctx context.Context, f *entity.UpdatableFeature) error
+	DeleteFeature(ctx context.Context, id int64) error
+}
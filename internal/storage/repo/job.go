@@ -0,0 +1,13 @@
+package repo
+
+import (
+	"context"
+
+	"banners-management/internal/model/entity"
+)
+
+// BannerJobs is an interface that supports scheduling deferred banner operations and tracking their state.
+type BannerJobs interface {
+	ScheduleDelete(ctx context.Context, featureID, tagID *int64) (jobID string, err error)
+	Job(ctx context.Context, id string) (*entity.Job, error)
+}
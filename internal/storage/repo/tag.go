@@ -0,0 +1,16 @@
+package repo
+
+import (
+	"context"
+
+	"banners-management/internal/model/entity"
+)
+
+// TagRepo is an interface that supports tag CRUD operations.
+type TagRepo interface {
+	SaveTag(ctx context.Context, f *entity.Tag) error
+	Tag(ctx context.Context, id int64) (*entity.Tag, error)
+	Tags(ctx context.Context, limit, offset *int) ([]*entity.Tag, error)
+	UpdateTag(ctx context.Context, f *entity.UpdatableTag) error
+	DeleteTag(ctx context.Context, id int64) error
+}
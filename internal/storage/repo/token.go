@@ -0,0 +1,20 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"banners-management/internal/model/entity"
+)
+
+// TokenStore is an interface that supports storing the sessions by their refresh tokens
+// and revoking the access tokens by their IDs.
+type TokenStore interface {
+	SaveSession(ctx context.Context, refreshToken string, s *entity.Session, ttl time.Duration) error
+	// Session returns the session of the refresh token without removing it.
+	Session(ctx context.Context, refreshToken string) (*entity.Session, error)
+	// TakeSession returns the session of the refresh token and removes it, so the token can't be used twice.
+	TakeSession(ctx context.Context, refreshToken string) (*entity.Session, error)
+	RevokeToken(ctx context.Context, tokenID string, ttl time.Duration) error
+	TokenRevoked(ctx context.Context, tokenID string) (bool, error)
+}
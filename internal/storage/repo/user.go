@@ -0,0 +1,13 @@
+package repo
+
+import (
+	"context"
+
+	"banners-management/internal/model/entity"
+)
+
+// UserRepo is an interface that supports saving and retrieving registered users.
+type UserRepo interface {
+	SaveUser(ctx context.Context, u *entity.User) (int64, error)
+	UserByLogin(ctx context.Context, login string) (*entity.User, error)
+}
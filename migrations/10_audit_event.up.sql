@@ -0,0 +1,17 @@
+CREATE TABLE audit_event (
+    id BIGINT PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    action TEXT NOT NULL,
+    actor TEXT NOT NULL,
+    actor_role TEXT NOT NULL,
+    request_id TEXT NOT NULL,
+    banner_id INT,
+    feature_id INT,
+    tag_id INT,
+    job_id TEXT,
+    diff JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX audit_event_banner_id_idx ON audit_event (banner_id, created_at);
+CREATE INDEX audit_event_actor_idx ON audit_event (actor, created_at);
+CREATE INDEX audit_event_created_at_idx ON audit_event (created_at);
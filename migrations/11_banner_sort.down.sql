@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS banner_title_idx;
+DROP INDEX IF EXISTS banner_updated_at_idx;
+DROP INDEX IF EXISTS banner_created_at_idx;
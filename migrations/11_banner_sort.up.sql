@@ -0,0 +1,3 @@
+CREATE INDEX banner_created_at_idx ON banner (created_at, id);
+CREATE INDEX banner_updated_at_idx ON banner (updated_at, id);
+CREATE INDEX banner_title_idx ON banner (title, id);
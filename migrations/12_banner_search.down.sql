@@ -0,0 +1 @@
+DROP INDEX IF EXISTS banner_search_idx;
@@ -0,0 +1,2 @@
+CREATE INDEX banner_search_idx ON banner
+    USING GIN (to_tsvector('simple', title || ' ' || coalesce(text, '')));
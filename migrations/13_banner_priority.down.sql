@@ -0,0 +1,29 @@
+ALTER TABLE banner_version
+    DROP COLUMN IF EXISTS priority;
+
+ALTER TABLE banner
+    DROP COLUMN IF EXISTS priority;
+
+-- the trigger is checked for the new rows only, so the overlapping banners are to be removed manually
+CREATE OR REPLACE FUNCTION check_feature_tag_unique()
+    RETURNS TRIGGER AS $$
+BEGIN
+    IF EXISTS (
+        SELECT 1
+        FROM banner b JOIN banner_tag bt ON b.id = bt.banner_id
+        WHERE b.feature_id = NEW.feature_id
+        GROUP BY bt.tag_id
+        HAVING COUNT(*) > 1
+    ) THEN
+        RAISE EXCEPTION 'Duplicate banner tags found for the given feature_id';
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE CONSTRAINT TRIGGER unique_feature_tag_trigger
+    AFTER INSERT OR UPDATE ON banner
+    INITIALLY DEFERRED
+    FOR EACH ROW
+EXECUTE FUNCTION check_feature_tag_unique();
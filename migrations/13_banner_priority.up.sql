@@ -0,0 +1,8 @@
+DROP TRIGGER IF EXISTS unique_feature_tag_trigger ON banner;
+DROP FUNCTION IF EXISTS check_feature_tag_unique();
+
+ALTER TABLE banner
+    ADD COLUMN priority INT NOT NULL DEFAULT 0;
+
+ALTER TABLE banner_version
+    ADD COLUMN priority INT NOT NULL DEFAULT 0;
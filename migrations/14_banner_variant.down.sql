@@ -0,0 +1,3 @@
+ALTER TABLE IF EXISTS audit_event DROP COLUMN IF EXISTS variant_id;
+
+DROP TABLE IF EXISTS banner_variant;
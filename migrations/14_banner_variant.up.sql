@@ -0,0 +1,14 @@
+CREATE TABLE banner_variant (
+    id INT PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    banner_id INT NOT NULL REFERENCES banner(id) ON DELETE CASCADE,
+    title TEXT NOT NULL,
+    text TEXT,
+    url TEXT,
+    weight INT NOT NULL DEFAULT 1 CHECK (weight >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX banner_variant_banner_id_idx ON banner_variant (banner_id, id);
+
+ALTER TABLE audit_event ADD COLUMN variant_id INT;
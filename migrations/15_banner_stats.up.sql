@@ -0,0 +1,8 @@
+CREATE TABLE banner_stats (
+    banner_id INT NOT NULL REFERENCES banner(id) ON DELETE CASCADE,
+    variant_id INT NOT NULL DEFAULT 0,
+    day DATE NOT NULL,
+    impressions BIGINT NOT NULL DEFAULT 0,
+    clicks BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (banner_id, day, variant_id)
+);
@@ -0,0 +1,13 @@
+CREATE TABLE banner_version (
+    banner_id INT REFERENCES banner(id) ON DELETE CASCADE,
+    version INT NOT NULL,
+    title TEXT NOT NULL,
+    text TEXT,
+    url TEXT,
+    is_active BOOLEAN,
+    feature_id INT,
+    tag_ids INT[] NOT NULL DEFAULT '{}',
+    updated_at TIMESTAMPTZ,
+    archived_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (banner_id, version)
+);
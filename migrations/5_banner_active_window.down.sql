@@ -0,0 +1,7 @@
+ALTER TABLE banner_version
+    DROP COLUMN IF EXISTS active_from,
+    DROP COLUMN IF EXISTS active_until;
+
+ALTER TABLE banner
+    DROP COLUMN IF EXISTS active_from,
+    DROP COLUMN IF EXISTS active_until;
@@ -0,0 +1,7 @@
+ALTER TABLE banner
+    ADD COLUMN active_from TIMESTAMPTZ,
+    ADD COLUMN active_until TIMESTAMPTZ;
+
+ALTER TABLE banner_version
+    ADD COLUMN active_from TIMESTAMPTZ,
+    ADD COLUMN active_until TIMESTAMPTZ;
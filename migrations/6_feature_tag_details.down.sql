@@ -0,0 +1,7 @@
+ALTER TABLE tag
+    DROP COLUMN IF EXISTS name,
+    DROP COLUMN IF EXISTS description;
+
+ALTER TABLE feature
+    DROP COLUMN IF EXISTS name,
+    DROP COLUMN IF EXISTS description;
@@ -0,0 +1,7 @@
+ALTER TABLE feature
+    ADD COLUMN name TEXT NOT NULL DEFAULT '',
+    ADD COLUMN description TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE tag
+    ADD COLUMN name TEXT NOT NULL DEFAULT '',
+    ADD COLUMN description TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+ALTER TABLE users DROP COLUMN IF EXISTS scopes;
+DELETE FROM users WHERE role IN ('viewer', 'editor');
+ALTER TABLE users DROP CONSTRAINT users_role_check;
+ALTER TABLE users ADD CONSTRAINT users_role_check CHECK (role IN ('user', 'admin'));
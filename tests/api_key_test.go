@@ -0,0 +1,133 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/gavv/httpexpect/v2"
+
+	"banners-management/internal/model/dto/apikey"
+	"banners-management/internal/model/entity"
+)
+
+func TestAPIKey_FetchesBanners(t *testing.T) {
+	e, _, tokenAdm := initTest(t)
+	b := newCreateBannerDTO()
+	e.POST("/banner").
+		WithJSON(b).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusCreated)
+
+	resp := e.POST("/api_key").
+		WithJSON(apikey.CreateDTO{Name: gofakeit.Word(), Role: entity.RoleUser}).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object()
+	id := resp.Value("api_key_id").Raw()
+	key := resp.Value("key").String().Raw()
+	resp.Value("prefix").String().NotEmpty()
+
+	e.GET("/user_banner").
+		WithQuery("feature_id", b.FeatureID).
+		WithQuery("tag_id", b.TagIDs[0]).
+		WithQuery("use_last_revision", true).
+		WithHeader("X-API-Key", key).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("title").IsEqual(b.Content.Title)
+
+	// the key has user role and can't manage banners
+	e.GET("/banner").
+		WithHeader("X-API-Key", key).
+		Expect().
+		Status(http.StatusForbidden)
+
+	e.DELETE("/api_key/{id}", id).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusNoContent)
+
+	e.GET("/user_banner").
+		WithQuery("feature_id", b.FeatureID).
+		WithQuery("tag_id", b.TagIDs[0]).
+		WithHeader("X-API-Key", key).
+		Expect().
+		Status(http.StatusUnauthorized)
+
+	e.DELETE("/api_key/{id}", 1<<40).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusNotFound)
+}
+
+func TestAPIKey_List_HidesSecrets(t *testing.T) {
+	e, _, tokenAdm := initTest(t)
+	name := gofakeit.UUID()
+	key := e.POST("/api_key").
+		WithJSON(apikey.CreateDTO{
+			Name:   name,
+			Role:   entity.RoleEditor,
+			Scopes: []string{entity.FeatureWriteScope(getNextFeatureID())},
+		}).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().Value("key").String().Raw()
+
+	e.GET("/banner").
+		WithHeader("X-API-Key", key).
+		Expect().
+		Status(http.StatusOK)
+
+	keys := e.GET("/api_key").
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array()
+	k := keys.Filter(func(_ int, v *httpexpect.Value) bool {
+		return v.Object().Value("name").String().Raw() == name
+	}).Value(0).Object()
+	k.Value("role").IsEqual(entity.RoleEditor)
+	k.Value("last_used_at").String().NotEmpty()
+	k.NotContainsKey("key")
+	k.NotContainsKey("key_hash")
+}
+
+func TestAPIKey_Invalid(t *testing.T) {
+	e, tokenUsr, tokenAdm := initTest(t)
+
+	e.GET("/user_banner").
+		WithQuery("feature_id", 1).
+		WithQuery("tag_id", 1).
+		WithHeader("X-API-Key", "bnr_"+gofakeit.UUID()).
+		Expect().
+		Status(http.StatusUnauthorized)
+
+	e.POST("/api_key").
+		WithJSON(apikey.CreateDTO{Name: gofakeit.Word(), Role: entity.RoleUser}).
+		WithHeader("Authorization", "Bearer "+tokenUsr).
+		Expect().
+		Status(http.StatusForbidden)
+
+	e.POST("/api_key").
+		WithJSON(apikey.CreateDTO{Name: gofakeit.Word(), Role: "root"}).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusBadRequest)
+
+	for _, scope := range []string{"feature:abc:write", "feature:1:2:write", "feature::write", "feature:042:write"} {
+		e.POST("/api_key").
+			WithJSON(apikey.CreateDTO{Name: gofakeit.Word(), Role: entity.RoleEditor, Scopes: []string{scope}}).
+			WithHeader("Authorization", "Bearer "+tokenAdm).
+			Expect().
+			Status(http.StatusBadRequest)
+	}
+	e.POST("/api_key").
+		WithJSON(apikey.CreateDTO{Name: gofakeit.Word(), Role: entity.RoleEditor, Scopes: []string{"feature:*:write"}}).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusCreated)
+}
@@ -0,0 +1,160 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gavv/httpexpect/v2"
+
+	"banners-management/internal/model/entity"
+	"banners-management/tests/suit"
+)
+
+func TestAudit_RecordsBannerMutations(t *testing.T) {
+	e, _, _ := initTest(t)
+	token, _ := login(e)
+	b := newCreateBannerDTO()
+	start := time.Now().Add(-time.Second)
+
+	id := e.POST("/banner").
+		WithJSON(b).
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().Value("banner_id").Raw()
+
+	upd := updateBannerDTO(nil, nil, nil)
+	e.PATCH("/banner/{id}", id).
+		WithJSON(upd).
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(http.StatusOK)
+
+	e.DELETE("/banner/{id}", id).
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(http.StatusNoContent)
+
+	events := e.GET("/audit").
+		WithQuery("banner_id", id).
+		WithQuery("actor", suit.AdminLogin).
+		WithQuery("from", start.Format(time.RFC3339)).
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array()
+	events.Length().IsEqual(3)
+
+	// the most recent event goes first
+	del := events.Value(0).Object()
+	del.Value("action").IsEqual(entity.AuditBannerDelete)
+	del.Value("actor_role").IsEqual(entity.RoleAdmin)
+	del.Value("request_id").String().NotEmpty()
+	del.Value("diff").Object().Value("title").Object().Value("before").IsEqual(*upd.Content.Title)
+
+	update := events.Value(1).Object()
+	update.Value("action").IsEqual(entity.AuditBannerUpdate)
+	title := update.Value("diff").Object().Value("title").Object()
+	title.Value("before").IsEqual(b.Content.Title)
+	title.Value("after").IsEqual(*upd.Content.Title)
+	update.Value("diff").Object().NotContainsKey("feature_id")
+
+	create := events.Value(2).Object()
+	create.Value("action").IsEqual(entity.AuditBannerCreate)
+	create.Value("diff").Object().Value("feature_id").Object().Value("after").IsEqual(b.FeatureID)
+	create.Value("diff").Object().Value("title").Object().NotContainsKey("before")
+}
+
+func TestAudit_RecordsDeleteByFeatureTag(t *testing.T) {
+	e, _, _ := initTest(t)
+	token, _ := login(e)
+	featureID := getNextFeatureID()
+
+	jobID := e.DELETE("/banner").
+		WithQuery("feature_id", featureID).
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(http.StatusAccepted).
+		JSON().Object().Value("job_id").String().Raw()
+
+	events := e.GET("/audit").
+		WithQuery("actor", suit.AdminLogin).
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array()
+	ev := events.Filter(func(_ int, v *httpexpect.Value) bool {
+		return v.Object().Value("job_id").String().Raw() == jobID
+	})
+	ev.Length().IsEqual(1)
+	ev.Value(0).Object().Value("action").IsEqual(entity.AuditBannerDeleteByFeatureTag)
+	ev.Value(0).Object().Value("feature_id").IsEqual(featureID)
+	ev.Value(0).Object().NotContainsKey("tag_id")
+}
+
+func TestAudit_RecordsBannersAffectedByDeleteJob(t *testing.T) {
+	e, _, _ := initTest(t)
+	token, _ := login(e)
+	tagIDs := getNextTagIDs(2)
+	deleted := createBanner(e, token, createBannerDTO(getNextFeatureID(), tagIDs[:1], true))
+	updated := createBanner(e, token, createBannerDTO(getNextFeatureID(), tagIDs, true))
+
+	jobID := e.DELETE("/banner").
+		WithQuery("tag_id", tagIDs[0]).
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(http.StatusAccepted).
+		JSON().Object().Value("job_id").String().Raw()
+	waitJobFinished(t, e, token, jobID).Value("affected").IsEqual(2)
+
+	del := e.GET("/audit").
+		WithQuery("banner_id", deleted).
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array().Value(0).Object()
+	del.Value("action").IsEqual(entity.AuditBannerDelete)
+	del.Value("actor").IsEqual(suit.AdminLogin)
+	del.Value("job_id").IsEqual(jobID)
+	del.Value("diff").Object().Value("tag_ids").Object().Value("before").IsEqual(tagIDs[:1])
+
+	upd := e.GET("/audit").
+		WithQuery("banner_id", updated).
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array().Value(0).Object()
+	upd.Value("action").IsEqual(entity.AuditBannerUpdate)
+	upd.Value("job_id").IsEqual(jobID)
+	tags := upd.Value("diff").Object().Value("tag_ids").Object()
+	tags.Value("before").IsEqual(tagIDs)
+	tags.Value("after").IsEqual(tagIDs[1:])
+}
+
+func TestAudit_Access(t *testing.T) {
+	e, tokenUsr, tokenAdm := initTest(t)
+
+	e.GET("/audit").
+		WithHeader("Authorization", "Bearer "+scopedToken(t, entity.RoleEditor, entity.AllFeaturesWriteScope)).
+		Expect().
+		Status(http.StatusForbidden)
+
+	e.GET("/audit").
+		WithHeader("Authorization", "Bearer "+tokenUsr).
+		Expect().
+		Status(http.StatusForbidden)
+
+	e.GET("/audit").
+		WithQuery("from", "yesterday").
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusBadRequest)
+
+	e.GET("/audit").
+		WithQuery("from", "2024-02-01T00:00:00Z").
+		WithQuery("to", "2024-01-01T00:00:00Z").
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusBadRequest)
+}
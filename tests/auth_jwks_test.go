@@ -0,0 +1,54 @@
+package tests
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"banners-management/internal/app"
+	"banners-management/tests/suit"
+)
+
+func TestJWKS_PublishesPublicKeys(t *testing.T) {
+	e, _, _ := initTest(t)
+
+	keys := e.GET("/.well-known/jwks.json").
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("keys").Array()
+	keys.Length().IsEqual(2)
+
+	ed := keys.Value(0).Object()
+	ed.Value("kid").IsEqual("test-ed25519")
+	ed.Value("kty").IsEqual("OKP")
+	ed.Value("crv").IsEqual("Ed25519")
+	ed.NotContainsKey("d")
+
+	rsa := keys.Value(1).Object()
+	rsa.Value("kid").IsEqual("test-rs256")
+	rsa.Value("kty").IsEqual("RSA")
+	rsa.Value("n").String().NotEmpty()
+	rsa.NotContainsKey("d")
+}
+
+func TestJWT_RetiredKey_StillVerifies(t *testing.T) {
+	e, _, _ := initTest(t)
+	s := suit.Setup(t)
+
+	// the manager that signed the tokens before the rotation to the current signing key
+	settings := s.Cfg.JwtSettings
+	settings.Keys = append(settings.Keys[:0:0], settings.Keys...)
+	settings.Keys[1].PrivateKeyPath = filepath.Join("..", "config", "keys", "test-rs256.pem")
+	settings.SigningKeyID = "test-rs256"
+	old, err := app.NewJwtManager(&settings)
+	require.NoError(t, err)
+	token, err := old.GenerateToken("admin")
+	require.NoError(t, err)
+
+	e.GET("/banner").
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(http.StatusOK)
+}
@@ -0,0 +1,156 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"banners-management/internal/lib/oidc"
+	"banners-management/tests/suit"
+)
+
+// countingTransport counts the requests made through the default transport.
+// If fail is true, the requests fail without reaching the transport.
+type countingTransport struct {
+	requests atomic.Int64
+	fail     bool
+}
+
+func (ct *countingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	ct.requests.Add(1)
+	if ct.fail {
+		return nil, errors.New("issuer unavailable")
+	}
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestOIDC_AdminGroup_Successful(t *testing.T) {
+	e, _, _ := initTest(t)
+	s := suit.Setup(t)
+
+	token, err := s.OIDC.IssueToken(map[string]any{
+		"aud":    s.Cfg.Auth.OIDC.Audience,
+		"groups": []string{"employees", "banner-admins"},
+	})
+	require.NoError(t, err)
+
+	e.GET("/banner").
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(http.StatusOK)
+}
+
+func TestOIDC_UserGroup_Forbidden(t *testing.T) {
+	e, _, _ := initTest(t)
+	s := suit.Setup(t)
+
+	token, err := s.OIDC.IssueToken(map[string]any{
+		"aud":    s.Cfg.Auth.OIDC.Audience,
+		"groups": "employees",
+	})
+	require.NoError(t, err)
+
+	e.GET("/banner").
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(http.StatusForbidden)
+}
+
+func TestOIDC_WrongAudience_Unauthorized(t *testing.T) {
+	e, _, _ := initTest(t)
+	s := suit.Setup(t)
+
+	token, err := s.OIDC.IssueToken(map[string]any{
+		"aud":    "another-service",
+		"groups": []string{"banner-admins"},
+	})
+	require.NoError(t, err)
+
+	e.GET("/banner").
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(http.StatusUnauthorized)
+}
+
+func TestOIDC_UnmappedGroup_Unauthorized(t *testing.T) {
+	e, _, _ := initTest(t)
+	s := suit.Setup(t)
+
+	token, err := s.OIDC.IssueToken(map[string]any{
+		"aud":    s.Cfg.Auth.OIDC.Audience,
+		"groups": []string{"contractors"},
+	})
+	require.NoError(t, err)
+
+	e.GET("/banner").
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(http.StatusUnauthorized)
+}
+
+func TestOIDC_ConcurrentVerifications_KeysFetchedOnce(t *testing.T) {
+	s := suit.Setup(t)
+	ct := new(countingTransport)
+	v := oidc.NewVerifier(oidc.Config{
+		Issuer:       s.Cfg.Auth.OIDC.Issuer,
+		Audience:     s.Cfg.Auth.OIDC.Audience,
+		RoleClaim:    "groups",
+		RoleMapping:  []oidc.RoleMapping{{Value: "banner-admins", Role: "admin"}},
+		JWKSCacheTTL: time.Minute,
+	}, &http.Client{Transport: ct})
+
+	token, err := s.OIDC.IssueToken(map[string]any{
+		"aud":    s.Cfg.Auth.OIDC.Audience,
+		"groups": []string{"banner-admins"},
+	})
+	require.NoError(t, err)
+
+	const n = 20
+	var wg sync.WaitGroup
+	errs := make([]error, n)
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = v.Verify(context.Background(), token)
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+	// the discovery document and the keys are requested once for all the verifications
+	require.Equal(t, int64(2), ct.requests.Load())
+}
+
+func TestOIDC_IssuerUnavailable_FetchNotRetriedAtOnce(t *testing.T) {
+	s := suit.Setup(t)
+	ct := &countingTransport{fail: true}
+	v := oidc.NewVerifier(oidc.Config{
+		Issuer:       s.Cfg.Auth.OIDC.Issuer,
+		Audience:     s.Cfg.Auth.OIDC.Audience,
+		RoleClaim:    "groups",
+		RoleMapping:  []oidc.RoleMapping{{Value: "banner-admins", Role: "admin"}},
+		JWKSCacheTTL: time.Minute,
+	}, &http.Client{Transport: ct})
+
+	token, err := s.OIDC.IssueToken(map[string]any{
+		"aud":    s.Cfg.Auth.OIDC.Audience,
+		"groups": []string{"banner-admins"},
+	})
+	require.NoError(t, err)
+
+	for range 5 {
+		_, err = v.Verify(context.Background(), token)
+		require.Error(t, err)
+	}
+	// the failed discovery request is not repeated by the verifications that follow it
+	require.Equal(t, int64(1), ct.requests.Load())
+}
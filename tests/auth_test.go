@@ -0,0 +1,180 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/gavv/httpexpect/v2"
+	"github.com/stretchr/testify/require"
+
+	slogdiscard "banners-management/internal/lib/logger/slogimpl"
+	authdto "banners-management/internal/model/dto/auth"
+	authsvc "banners-management/internal/service/auth"
+	"banners-management/tests/suit"
+)
+
+func TestLogin_Successful(t *testing.T) {
+	e, _, _ := initTest(t)
+
+	resp := e.POST("/auth/login").
+		WithJSON(map[string]any{"login": suit.AdminLogin, "password": suit.AdminPassword}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	resp.Value("refresh_token").String().NotEmpty()
+	token := resp.Value("token").String().NotEmpty().Raw()
+
+	e.GET("/banner").
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(http.StatusOK)
+}
+
+func TestLogin_WrongPassword_Unauthorized(t *testing.T) {
+	e, _, _ := initTest(t)
+
+	e.POST("/auth/login").
+		WithJSON(map[string]any{"login": suit.AdminLogin, "password": "wrong-password"}).
+		Expect().
+		Status(http.StatusUnauthorized)
+}
+
+func TestLogin_UnknownUser_Unauthorized(t *testing.T) {
+	e, _, _ := initTest(t)
+
+	e.POST("/auth/login").
+		WithJSON(map[string]any{"login": "nobody", "password": suit.AdminPassword}).
+		Expect().
+		Status(http.StatusUnauthorized)
+}
+
+func TestLogin_NoPassword_BadRequest(t *testing.T) {
+	e, _, _ := initTest(t)
+
+	e.POST("/auth/login").
+		WithJSON(map[string]any{"login": suit.AdminLogin}).
+		Expect().
+		Status(http.StatusBadRequest)
+}
+
+func TestRefresh_Successful(t *testing.T) {
+	e, _, _ := initTest(t)
+	_, refresh := login(e)
+
+	resp := e.POST("/auth/refresh").
+		WithJSON(map[string]any{"refresh_token": refresh}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	resp.Value("refresh_token").String().NotEmpty().NotEqual(refresh)
+	token := resp.Value("token").String().NotEmpty().Raw()
+
+	e.GET("/banner").
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(http.StatusOK)
+
+	// the refresh token can be used only once
+	e.POST("/auth/refresh").
+		WithJSON(map[string]any{"refresh_token": refresh}).
+		Expect().
+		Status(http.StatusUnauthorized)
+}
+
+func TestRefresh_UnknownToken_Unauthorized(t *testing.T) {
+	e, _, _ := initTest(t)
+
+	e.POST("/auth/refresh").
+		WithJSON(map[string]any{"refresh_token": "unknown"}).
+		Expect().
+		Status(http.StatusUnauthorized)
+}
+
+func TestRefresh_RevokesPreviousAccessToken(t *testing.T) {
+	e, _, _ := initTest(t)
+	token, refresh := login(e)
+
+	e.POST("/auth/refresh").
+		WithJSON(map[string]any{"refresh_token": refresh}).
+		Expect().
+		Status(http.StatusOK)
+
+	e.GET("/banner").
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(http.StatusUnauthorized)
+}
+
+func TestLogout_ForeignRefreshToken_Forbidden(t *testing.T) {
+	e, _, _ := initTest(t)
+	s := suit.Setup(t)
+	adminToken, adminRefresh := login(e)
+
+	au := authsvc.NewService(s.Storage, authsvc.NewRedisTokenStore(s.Cache), s.JwtManager, time.Minute,
+		slogdiscard.NewDiscardLogger())
+	user := authdto.CreateUserDTO{Login: gofakeit.Username() + gofakeit.UUID(), Password: gofakeit.UUID(), Role: "user"}
+	_, err := au.CreateUser(context.Background(), user)
+	require.NoError(t, err)
+	userToken := e.POST("/auth/login").
+		WithJSON(map[string]any{"login": user.Login, "password": user.Password}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("token").String().Raw()
+
+	e.POST("/auth/logout").
+		WithJSON(map[string]any{"refresh_token": adminRefresh}).
+		WithHeader("Authorization", "Bearer "+userToken).
+		Expect().
+		Status(http.StatusForbidden)
+
+	// neither the session of the admin, nor the token of the user is revoked
+	e.GET("/banner").
+		WithHeader("Authorization", "Bearer "+adminToken).
+		Expect().
+		Status(http.StatusOK)
+	e.GET("/user_banner").
+		WithQuery("feature_id", getNextFeatureID()).
+		WithQuery("tag_id", getNextTagIDs(1)[0]).
+		WithHeader("Authorization", "Bearer "+userToken).
+		Expect().
+		Status(http.StatusNotFound)
+	e.POST("/auth/refresh").
+		WithJSON(map[string]any{"refresh_token": adminRefresh}).
+		Expect().
+		Status(http.StatusOK)
+}
+
+func TestLogout_RevokesBothTokens(t *testing.T) {
+	e, _, _ := initTest(t)
+	token, refresh := login(e)
+
+	e.POST("/auth/logout").
+		WithJSON(map[string]any{"refresh_token": refresh}).
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(http.StatusOK)
+
+	e.GET("/banner").
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(http.StatusUnauthorized)
+
+	e.POST("/auth/refresh").
+		WithJSON(map[string]any{"refresh_token": refresh}).
+		Expect().
+		Status(http.StatusUnauthorized)
+}
+
+// login logs in as the test admin and returns the access and the refresh tokens.
+func login(e *httpexpect.Expect) (string, string) {
+	resp := e.POST("/auth/login").
+		WithJSON(map[string]any{"login": suit.AdminLogin, "password": suit.AdminPassword}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+
+	return resp.Value("token").String().Raw(), resp.Value("refresh_token").String().Raw()
+}
@@ -0,0 +1,136 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestBannerActiveWindow_UserAccess(t *testing.T) {
+	e, tokenUsr, tokenAdm := initTest(t)
+	now := time.Now()
+	hourAgo, inHour := now.Add(-time.Hour), now.Add(time.Hour)
+
+	cases := []struct {
+		name        string
+		from, until *time.Time
+		status      int
+	}{
+		{"not started", &inHour, nil, http.StatusForbidden},
+		{"finished", nil, &hourAgo, http.StatusForbidden},
+		{"in window", &hourAgo, &inHour, http.StatusOK},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			b := newCreateBannerDTO()
+			b.ActiveFrom, b.ActiveUntil = c.from, c.until
+
+			e.POST("/banner").
+				WithJSON(b).
+				WithHeader("Authorization", "Bearer "+tokenAdm).
+				Expect().
+				Status(http.StatusCreated)
+
+			e.GET("/user_banner").
+				WithQuery("feature_id", b.FeatureID).WithQuery("tag_id", b.TagIDs[0]).
+				WithHeader("Authorization", "Bearer "+tokenUsr).
+				Expect().
+				Status(c.status)
+		})
+	}
+}
+
+func TestBannerActiveWindow_Invalid_BadRequest(t *testing.T) {
+	e, _, tokenAdm := initTest(t)
+	b := newCreateBannerDTO()
+	from := time.Now()
+	until := from.Add(-time.Minute)
+	b.ActiveFrom, b.ActiveUntil = &from, &until
+
+	e.POST("/banner").
+		WithJSON(b).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusBadRequest)
+}
+
+func TestBannerActiveWindow_UpdateOneBound_ValidatedWithStored(t *testing.T) {
+	e, _, tokenAdm := initTest(t)
+	b := newCreateBannerDTO()
+	until := time.Now().Add(time.Hour)
+	b.ActiveUntil = &until
+	id := createBanner(e, tokenAdm, b)
+
+	// only active_from is provided, but it's after the stored active_until
+	e.PATCH("/banner/{id}", id).
+		WithJSON(map[string]any{"active_from": until.Add(time.Minute)}).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusBadRequest)
+
+	e.PATCH("/banner/{id}", id).
+		WithJSON(map[string]any{"active_from": until.Add(time.Minute), "active_until": nil}).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK)
+}
+
+func TestBannerActiveWindow_AdminListAndUpdate(t *testing.T) {
+	e, tokenUsr, tokenAdm := initTest(t)
+	b := newCreateBannerDTO()
+	until := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	b.ActiveUntil = &until
+
+	id := e.POST("/banner").
+		WithJSON(b).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().Value("banner_id").Raw()
+
+	item := e.GET("/banner").
+		WithQuery("feature_id", b.FeatureID).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("banners").Array().Value(0).Object()
+	item.NotContainsKey("active_from")
+	item.Value("active_until").String().AsDateTime(time.RFC3339).IsEqual(until)
+
+	e.PATCH("/banner/{id}", rawToInt64(id)).
+		WithJSON(map[string]any{"active_until": nil}).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK)
+
+	e.GET("/user_banner").
+		WithQuery("feature_id", b.FeatureID).WithQuery("tag_id", b.TagIDs[0]).
+		WithHeader("Authorization", "Bearer "+tokenUsr).
+		Expect().
+		Status(http.StatusOK)
+}
+
+func TestBannerActiveWindow_CacheExpiresAtWindowStart(t *testing.T) {
+	e, tokenUsr, tokenAdm := initTest(t)
+	b := newCreateBannerDTO()
+	from := time.Now().Add(2 * time.Second)
+	b.ActiveFrom = &from
+
+	e.POST("/banner").
+		WithJSON(b).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusCreated)
+
+	waitUserBannerCached(t, e, tokenUsr, b.FeatureID, b.TagIDs[0], http.StatusForbidden)
+
+	time.Sleep(time.Until(from) + 100*time.Millisecond)
+
+	e.GET("/user_banner").
+		WithQuery("feature_id", b.FeatureID).WithQuery("tag_id", b.TagIDs[0]).
+		WithQuery("use_last_revision", false).
+		WithHeader("Authorization", "Bearer "+tokenUsr).
+		Expect().
+		Status(http.StatusOK).
+		Header("X-Cache").IsEqual("MISS")
+}
@@ -0,0 +1,119 @@
+package tests
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/gavv/httpexpect/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// listBannerIDs returns the ids of the banners, listed by the query parameters.
+func listBannerIDs(e *httpexpect.Expect, tokenAdm string, query map[string]any) []int64 {
+	req := e.GET("/banner").WithHeader("Authorization", "Bearer "+tokenAdm)
+	for k, v := range query {
+		req = req.WithQuery(k, v)
+	}
+
+	arr := req.Expect().Status(http.StatusOK).JSON().Object().Value("banners").Array()
+	ids := make([]int64, 0, len(arr.Raw()))
+	for _, v := range arr.Iter() {
+		ids = append(ids, rawToInt64(v.Object().Value("banner_id").Raw()))
+	}
+
+	return ids
+}
+
+func TestBannerAdminGet_FilterByTags(t *testing.T) {
+	e, _, tokenAdm := initTest(t)
+	featureID := getNextFeatureID()
+	tags := getNextTagIDs(3)
+	both := createBanner(e, tokenAdm, createBannerDTO(featureID, []int64{tags[0], tags[1]}, true))
+	single := createBanner(e, tokenAdm, createBannerDTO(featureID, []int64{tags[2]}, true))
+
+	tagIDs := []string{
+		strconv.FormatInt(tags[0], 10) + "," + strconv.FormatInt(tags[2], 10),
+		strconv.FormatInt(tags[0], 10) + "," + strconv.FormatInt(tags[1], 10),
+	}
+	require.Equal(t, []int64{both, single}, listBannerIDs(e, tokenAdm, map[string]any{"tag_ids": tagIDs[0]}))
+	require.Equal(t, []int64{both}, listBannerIDs(e, tokenAdm, map[string]any{
+		"tag_ids":   tagIDs[1],
+		"tag_match": "all",
+	}))
+	require.Empty(t, listBannerIDs(e, tokenAdm, map[string]any{
+		"tag_ids":   tagIDs[0],
+		"tag_match": "all",
+	}))
+}
+
+func TestBannerAdminGet_FilterByIsActive(t *testing.T) {
+	e, _, tokenAdm := initTest(t)
+	featureID := getNextFeatureID()
+	active := createBanner(e, tokenAdm, createBannerDTO(featureID, getNextTagIDs(1), true))
+	inactive := createBanner(e, tokenAdm, createBannerDTO(featureID, getNextTagIDs(1), false))
+
+	require.Equal(t, []int64{active}, listBannerIDs(e, tokenAdm, map[string]any{
+		"feature_id": featureID,
+		"is_active":  true,
+	}))
+	require.Equal(t, []int64{inactive}, listBannerIDs(e, tokenAdm, map[string]any{
+		"feature_id": featureID,
+		"is_active":  false,
+	}))
+}
+
+func TestBannerAdminGet_FilterByCreatedAt(t *testing.T) {
+	e, _, tokenAdm := initTest(t)
+	featureID := getNextFeatureID()
+	before := createBanner(e, tokenAdm, createBannerDTO(featureID, getNextTagIDs(1), true))
+	time.Sleep(10 * time.Millisecond)
+	from := time.Now().Format(time.RFC3339Nano)
+	time.Sleep(10 * time.Millisecond)
+	after := createBanner(e, tokenAdm, createBannerDTO(featureID, getNextTagIDs(1), true))
+
+	require.Equal(t, []int64{after}, listBannerIDs(e, tokenAdm, map[string]any{
+		"feature_id":   featureID,
+		"created_from": from,
+	}))
+	require.Equal(t, []int64{before}, listBannerIDs(e, tokenAdm, map[string]any{
+		"feature_id": featureID,
+		"created_to": from,
+	}))
+}
+
+func TestBannerAdminGet_Search(t *testing.T) {
+	e, _, tokenAdm := initTest(t)
+	word := gofakeit.LetterN(16)
+	titled := newCreateBannerDTO()
+	titled.Content.Title = "Spring " + word
+	texted := newCreateBannerDTO()
+	texted.Content.Text = "Get the " + word + " discount"
+	titledID := createBanner(e, tokenAdm, titled)
+	textedID := createBanner(e, tokenAdm, texted)
+	createBanner(e, tokenAdm, newCreateBannerDTO())
+
+	require.Equal(t, []int64{titledID, textedID}, listBannerIDs(e, tokenAdm, map[string]any{"q": word}))
+	require.Equal(t, []int64{textedID}, listBannerIDs(e, tokenAdm, map[string]any{"q": word + " discount"}))
+}
+
+func TestBannerAdminGet_InvalidFilters(t *testing.T) {
+	e, _, tokenAdm := initTest(t)
+
+	cases := []map[string]any{
+		{"tag_ids": "1,x"},
+		{"tag_match": "none"},
+		{"is_active": "yes"},
+		{"created_from": "yesterday"},
+		{"created_from": "2024-02-01T00:00:00Z", "created_to": "2024-01-01T00:00:00Z"},
+	}
+	for _, c := range cases {
+		req := e.GET("/banner").WithHeader("Authorization", "Bearer "+tokenAdm)
+		for k, v := range c {
+			req = req.WithQuery(k, v)
+		}
+		req.Expect().Status(http.StatusBadRequest)
+	}
+}
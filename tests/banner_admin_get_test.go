@@ -47,7 +47,7 @@ func TestBannerAdminGet_BannerNotActive_Successful(t *testing.T) {
 		WithHeader("Authorization", "Bearer "+tokenAdm).
 		Expect().
 		Status(http.StatusOK).
-		JSON().Array()
+		JSON().Object().Value("banners").Array()
 
 	require.Equal(t, int64(1), int64(resp.Length().Raw()))
 	r1 := rawToInt64(resp.Value(0).Object().Raw()["banner_id"])
@@ -73,7 +73,7 @@ func TestBannerAdminGet_Successful(t *testing.T) {
 		WithHeader("Authorization", "Bearer "+tokenAdm).
 		Expect().
 		Status(http.StatusOK).
-		JSON().Array()
+		JSON().Object().Value("banners").Array()
 
 	require.Equal(t, int64(1), int64(resp.Length().Raw()))
 	r1 := rawToInt64(resp.Value(0).Object().Raw()["banner_id"])
@@ -105,10 +105,33 @@ func TestBannerAdminGet_MultipleBanners(t *testing.T) {
 		WithQuery("feature_id", b1.FeatureID).
 		WithHeader("Authorization", "Bearer "+tokenAdm).
 		Expect().
-		JSON().Array()
+		JSON().Object().Value("banners").Array()
 
 	require.Equal(t, int64(2), int64(resp.Length().Raw()))
 	r1 := rawToInt64(resp.Value(0).Object().Raw()["banner_id"])
 	r2 := rawToInt64(resp.Value(1).Object().Raw()["banner_id"])
 	require.True(t, r1 == id1 && r2 == id2)
 }
+
+func TestBannerAdminGet_UseLastRevision_Successful(t *testing.T) {
+	e, _, tokenAdm := initTest(t)
+	b := newCreateBannerDTO()
+
+	e.POST("/banner").
+		WithMaxRetries(5).
+		WithJSON(b).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusCreated)
+
+	resp := e.GET("/banner").
+		WithMaxRetries(5).
+		WithQuery("feature_id", b.FeatureID).
+		WithQuery("use_last_revision", true).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK)
+
+	resp.Header("X-Cache").IsEqual("MISS")
+	resp.JSON().Object().Value("banners").Array().Length().IsEqual(1)
+}
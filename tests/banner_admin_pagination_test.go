@@ -0,0 +1,131 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gavv/httpexpect/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// createFeatureBanners creates n banners of a single feature and returns their ids in the creation order.
+func createFeatureBanners(e *httpexpect.Expect, tokenAdm string, n int) (int64, []int64) {
+	featureID := getNextFeatureID()
+
+	ids := make([]int64, n)
+	for i := range ids {
+		v := e.POST("/banner").
+			WithMaxRetries(5).
+			WithJSON(createBannerDTO(featureID, getNextTagIDs(1), true)).
+			WithHeader("Authorization", "Bearer "+tokenAdm).
+			Expect().
+			Status(http.StatusCreated).
+			JSON().Object().Value("banner_id").Raw()
+		ids[i] = rawToInt64(v)
+	}
+
+	return featureID, ids
+}
+
+func TestBannerAdminGet_CursorPagination(t *testing.T) {
+	e, _, tokenAdm := initTest(t)
+	featureID, ids := createFeatureBanners(e, tokenAdm, 5)
+
+	var (
+		got    []int64
+		cursor string
+	)
+	for range ids {
+		req := e.GET("/banner").
+			WithQuery("feature_id", featureID).
+			WithQuery("limit", 2).
+			WithHeader("Authorization", "Bearer "+tokenAdm)
+		if cursor != "" {
+			req = req.WithQuery("cursor", cursor)
+		}
+		page := req.Expect().Status(http.StatusOK).JSON().Object()
+		for _, v := range page.Value("banners").Array().Iter() {
+			got = append(got, rawToInt64(v.Object().Value("banner_id").Raw()))
+		}
+
+		next, ok := page.Raw()["next_cursor"]
+		if !ok {
+			break
+		}
+		cursor = next.(string)
+	}
+
+	require.Equal(t, ids, got)
+}
+
+func TestBannerAdminGet_SortDesc(t *testing.T) {
+	e, _, tokenAdm := initTest(t)
+	featureID, ids := createFeatureBanners(e, tokenAdm, 3)
+
+	page := e.GET("/banner").
+		WithQuery("feature_id", featureID).
+		WithQuery("sort", "-created_at").
+		WithQuery("limit", 2).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	arr := page.Value("banners").Array()
+	arr.Length().IsEqual(2)
+	require.Equal(t, ids[2], rawToInt64(arr.Value(0).Object().Value("banner_id").Raw()))
+	require.Equal(t, ids[1], rawToInt64(arr.Value(1).Object().Value("banner_id").Raw()))
+
+	arr = e.GET("/banner").
+		WithQuery("feature_id", featureID).
+		WithQuery("sort", "-created_at").
+		WithQuery("limit", 2).
+		WithQuery("cursor", page.Value("next_cursor").String().NotEmpty().Raw()).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("banners").Array()
+	arr.Length().IsEqual(1)
+	require.Equal(t, ids[0], rawToInt64(arr.Value(0).Object().Value("banner_id").Raw()))
+}
+
+func TestBannerAdminGet_WithTotal(t *testing.T) {
+	e, _, tokenAdm := initTest(t)
+	featureID, _ := createFeatureBanners(e, tokenAdm, 3)
+
+	page := e.GET("/banner").
+		WithQuery("feature_id", featureID).
+		WithQuery("limit", 1).
+		WithQuery("with_total", true).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	page.Value("total").IsEqual(3)
+	page.Value("banners").Array().Length().IsEqual(1)
+}
+
+func TestBannerAdminGet_InvalidParameters(t *testing.T) {
+	e, _, tokenAdm := initTest(t)
+
+	cases := []map[string]any{
+		{"limit": "abc"},
+		{"limit": 0},
+		{"limit": 1001},
+		{"limit": "9223372036854775807"},
+		{"offset": -1},
+		{"feature_id": "x"},
+		{"use_last_revision": "maybe"},
+		{"sort": "text"},
+		{"cursor": "not-a-cursor"},
+		{"cursor": "eyJzIjoiaWQiLCJpZCI6MX0", "offset": 1},
+		// the cursor of the id order can't be used for the title order
+		{"cursor": "eyJzIjoiaWQiLCJpZCI6MX0", "sort": "title"},
+	}
+	for _, c := range cases {
+		req := e.GET("/banner").WithHeader("Authorization", "Bearer "+tokenAdm)
+		for k, v := range c {
+			req = req.WithQuery(k, v)
+		}
+		req.Expect().Status(http.StatusBadRequest)
+	}
+}
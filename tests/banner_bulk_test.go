@@ -0,0 +1,192 @@
+package tests
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"banners-management/internal/model/dto/banner"
+	"banners-management/internal/model/entity"
+)
+
+func TestBannerBulk_JSON(t *testing.T) {
+	e, tokenUsr, tokenAdm := initTest(t)
+	bs := []banner.CreateDTO{newCreateBannerDTO(), newCreateBannerDTO()}
+
+	ids := e.POST("/banner/bulk").
+		WithJSON(bs).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().Value("banner_ids").Array()
+	ids.Length().IsEqual(2)
+
+	for _, b := range bs {
+		e.GET("/user_banner").
+			WithQuery("feature_id", b.FeatureID).
+			WithQuery("tag_id", b.TagIDs[0]).
+			WithQuery("use_last_revision", true).
+			WithHeader("Authorization", "Bearer "+tokenUsr).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().Value("title").IsEqual(b.Content.Title)
+	}
+}
+
+func TestBannerBulk_DryRunReportsRowErrors(t *testing.T) {
+	e, _, tokenAdm := initTest(t)
+	ok := newCreateBannerDTO()
+	invalid := newCreateBannerDTO()
+	invalid.Content.URL = "not a url"
+	// is only rejected by the storage
+	unknown := createBannerDTO(unseededIDOffset+getNextFeatureID(), getNextTagIDs(1), true)
+
+	errs := e.POST("/banner/bulk").
+		WithQuery("dry_run", true).
+		WithJSON([]banner.CreateDTO{ok, invalid, unknown}).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("errors").Array()
+	errs.Length().IsEqual(2)
+	errs.Value(0).Object().Value("row").IsEqual(2)
+	errs.Value(1).Object().Value("row").IsEqual(3)
+
+	// nothing is saved
+	e.GET("/banner").
+		WithQuery("feature_id", ok.FeatureID).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("banners").Array().IsEmpty()
+
+	e.POST("/banner/bulk").
+		WithJSON([]banner.CreateDTO{ok, invalid}).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusUnprocessableEntity).
+		JSON().Object().Value("errors").Array().Length().IsEqual(1)
+
+	// all or nothing
+	e.GET("/banner").
+		WithQuery("feature_id", ok.FeatureID).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("banners").Array().IsEmpty()
+
+	// a mistyped dry run must not create the banners
+	e.POST("/banner/bulk").
+		WithQuery("dry_run", "yes").
+		WithJSON([]banner.CreateDTO{ok}).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusBadRequest)
+	e.GET("/banner").
+		WithQuery("feature_id", ok.FeatureID).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("banners").Array().IsEmpty()
+}
+
+func TestBannerBulk_NDJSONAndCSV(t *testing.T) {
+	e, _, tokenAdm := initTest(t)
+	b1, b2 := newCreateBannerDTO(), newCreateBannerDTO()
+
+	var ndjson strings.Builder
+	require.NoError(t, json.NewEncoder(&ndjson).Encode(b1))
+	e.POST("/banner/bulk").
+		WithHeader("Content-Type", "application/x-ndjson").
+		WithText(ndjson.String()).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusCreated)
+
+	csv := fmt.Sprintf("feature_id,tag_ids,title,text,url,is_active\n%d,%d;%d,%s,%s,%s,true\n",
+		b2.FeatureID, b2.TagIDs[0], b2.TagIDs[1], b2.Content.Title, b2.Content.Text, b2.Content.URL)
+	e.POST("/banner/bulk").
+		WithHeader("Content-Type", "text/csv").
+		WithText(csv).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusCreated)
+
+	e.GET("/banner").
+		WithQuery("feature_id", b2.FeatureID).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("banners").Array().Value(0).Object().Value("tag_ids").Array().Length().IsEqual(2)
+
+	e.POST("/banner/bulk").
+		WithHeader("Content-Type", "application/xml").
+		WithText("<banners/>").
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusUnsupportedMediaType)
+}
+
+func TestBannerBulk_Export(t *testing.T) {
+	e, _, tokenAdm := initTest(t)
+	b := newCreateBannerDTO()
+	id := e.POST("/banner").
+		WithJSON(b).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().Value("banner_id").Number().Raw()
+
+	body := e.GET("/banner/export").
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK).
+		HasContentType("application/x-ndjson").
+		Body().Raw()
+	found := false
+	sc := bufio.NewScanner(strings.NewReader(body))
+	for sc.Scan() {
+		var item struct {
+			BannerID int64 `json:"banner_id"`
+			Content  struct {
+				Title string `json:"title"`
+			} `json:"content"`
+		}
+		require.NoError(t, json.Unmarshal(sc.Bytes(), &item))
+		if item.BannerID == int64(id) {
+			found = item.Content.Title == b.Content.Title
+		}
+	}
+	require.True(t, found)
+
+	e.GET("/banner/export").
+		WithQuery("format", "csv").
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK).
+		HasContentType("text/csv").
+		Body().HasPrefix("banner_id,feature_id,tag_ids,").Contains(b.Content.Title)
+}
+
+func TestBannerBulk_EditorScopes(t *testing.T) {
+	e, _, _ := initTest(t)
+	own, foreign := newCreateBannerDTO(), newCreateBannerDTO()
+	token := scopedToken(t, entity.RoleEditor, entity.FeatureWriteScope(own.FeatureID))
+
+	e.POST("/banner/bulk").
+		WithJSON([]banner.CreateDTO{own, foreign}).
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(http.StatusForbidden)
+
+	e.POST("/banner/bulk").
+		WithJSON([]banner.CreateDTO{own}).
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(http.StatusCreated)
+}
@@ -0,0 +1,236 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/stretchr/testify/require"
+
+	slogdiscard "banners-management/internal/lib/logger/slogimpl"
+	"banners-management/internal/model/entity"
+	bannersvc "banners-management/internal/service/banner"
+	"banners-management/internal/storage/repo"
+	"banners-management/tests/suit"
+)
+
+// slowReader is a repo.BannerReader, that holds the first banner read by BannerByFeatureTag
+// until it's released, imitating a slow storage.
+type slowReader struct {
+	repo.BannerReader
+	once    sync.Once
+	read    chan struct{}
+	release chan struct{}
+}
+
+func newSlowReader(reader repo.BannerReader) *slowReader {
+	return &slowReader{BannerReader: reader, read: make(chan struct{}), release: make(chan struct{})}
+}
+
+func (sr *slowReader) BannerByFeatureTag(
+	ctx context.Context,
+	featureID, tagID int64,
+	useLastRevision bool,
+) (*entity.Banner, repo.Revision, error) {
+	b, rev, err := sr.BannerReader.BannerByFeatureTag(ctx, featureID, tagID, useLastRevision)
+	sr.once.Do(func() {
+		close(sr.read)
+		<-sr.release
+	})
+
+	return b, rev, err
+}
+
+// countingReader is a repo.BannerReader, that counts the banners read by BannerByFeatureTag
+// and holds them until it's released.
+type countingReader struct {
+	repo.BannerReader
+	reads   atomic.Int64
+	read    chan struct{}
+	release chan struct{}
+}
+
+func newCountingReader(reader repo.BannerReader) *countingReader {
+	return &countingReader{BannerReader: reader, read: make(chan struct{}, 1), release: make(chan struct{})}
+}
+
+func (cr *countingReader) BannerByFeatureTag(
+	ctx context.Context,
+	featureID, tagID int64,
+	useLastRevision bool,
+) (*entity.Banner, repo.Revision, error) {
+	cr.reads.Add(1)
+	select {
+	case cr.read <- struct{}{}:
+	default:
+	}
+	<-cr.release
+
+	return cr.BannerReader.BannerByFeatureTag(ctx, featureID, tagID, useLastRevision)
+}
+
+func TestBannerCache_Update_Invalidated(t *testing.T) {
+	e, tokenUsr, tokenAdm := initTest(t)
+	b := newCreateBannerDTO()
+
+	id := createBanner(e, tokenAdm, b)
+
+	waitUserBannerCached(t, e, tokenUsr, b.FeatureID, b.TagIDs[0], http.StatusOK)
+
+	updDTO := updateBannerDTO(nil, nil, nil)
+	e.PATCH("/banner/{id}", id).
+		WithJSON(updDTO).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK)
+
+	e.GET("/user_banner").
+		WithQuery("feature_id", b.FeatureID).WithQuery("tag_id", b.TagIDs[0]).
+		WithQuery("use_last_revision", false).
+		WithHeader("Authorization", "Bearer "+tokenUsr).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("title").IsEqual(*updDTO.Content.Title)
+}
+
+func TestBannerCache_UpdateFeatureTag_OldKeysInvalidated(t *testing.T) {
+	e, tokenUsr, tokenAdm := initTest(t)
+	b := newCreateBannerDTO()
+
+	id := createBanner(e, tokenAdm, b)
+
+	waitUserBannerCached(t, e, tokenUsr, b.FeatureID, b.TagIDs[1], http.StatusOK)
+
+	e.PATCH("/banner/{id}", id).
+		WithJSON(newUpdateBannerDTO()).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK)
+
+	e.GET("/user_banner").
+		WithQuery("feature_id", b.FeatureID).WithQuery("tag_id", b.TagIDs[1]).
+		WithQuery("use_last_revision", false).
+		WithHeader("Authorization", "Bearer "+tokenUsr).
+		Expect().
+		Status(http.StatusNotFound)
+}
+
+func TestBannerCache_Create_NegativeCacheInvalidated(t *testing.T) {
+	e, tokenUsr, tokenAdm := initTest(t)
+	b := newCreateBannerDTO()
+
+	waitUserBannerCached(t, e, tokenUsr, b.FeatureID, b.TagIDs[0], http.StatusNotFound)
+
+	createBanner(e, tokenAdm, b)
+
+	e.GET("/user_banner").
+		WithQuery("feature_id", b.FeatureID).WithQuery("tag_id", b.TagIDs[0]).
+		WithQuery("use_last_revision", false).
+		WithHeader("Authorization", "Bearer "+tokenUsr).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("title").IsEqual(b.Content.Title)
+}
+
+func TestBannerCache_Delete_Invalidated(t *testing.T) {
+	e, tokenUsr, tokenAdm := initTest(t)
+	b := newCreateBannerDTO()
+
+	id := createBanner(e, tokenAdm, b)
+
+	waitUserBannerCached(t, e, tokenUsr, b.FeatureID, b.TagIDs[0], http.StatusOK)
+
+	e.DELETE("/banner/{id}", id).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusNoContent)
+
+	e.GET("/user_banner").
+		WithQuery("feature_id", b.FeatureID).WithQuery("tag_id", b.TagIDs[0]).
+		WithQuery("use_last_revision", false).
+		WithHeader("Authorization", "Bearer "+tokenUsr).
+		Expect().
+		Status(http.StatusNotFound)
+}
+
+func TestBannerCache_ConcurrentMisses_Successful(t *testing.T) {
+	e, _, tokenAdm := initTest(t)
+	s := suit.Setup(t)
+	b := newCreateBannerDTO()
+	createBanner(e, tokenAdm, b)
+
+	ctx := context.Background()
+	counting := newCountingReader(s.Storage)
+	cr := bannersvc.NewCacheReader(counting, s.Cache, nil, slogdiscard.NewDiscardLogger())
+
+	const n = 20
+	var wg sync.WaitGroup
+	titles := make([]string, n)
+	errs := make([]error, n)
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, _, err := cr.BannerByFeatureTag(ctx, b.FeatureID, b.TagIDs[0], false)
+			if errs[i] = err; err == nil {
+				titles[i] = got.Title
+			}
+		}()
+	}
+	// the first read is held, so that the other misses happen while it's running
+	<-counting.read
+	time.Sleep(200 * time.Millisecond)
+	close(counting.release)
+	wg.Wait()
+
+	for i := range n {
+		require.NoError(t, errs[i])
+		require.Equal(t, b.Content.Title, titles[i])
+	}
+	require.Equal(t, int64(1), counting.reads.Load())
+}
+
+func TestBannerCache_SlowReadDuringUpdate_NotCachedAfterEviction(t *testing.T) {
+	e, tokenUsr, tokenAdm := initTest(t)
+	s := suit.Setup(t)
+	b := newCreateBannerDTO()
+	id := createBanner(e, tokenAdm, b)
+
+	// the reader and the writer share the in-process cache, like the ones of a single app instance
+	ctx := context.Background()
+	l := slogdiscard.NewDiscardLogger()
+	lc := bannersvc.NewLocalCache(ctx, 10, time.Minute, s.Cache, l)
+	slow := newSlowReader(s.Storage)
+	cr := bannersvc.NewCacheReader(slow, s.Cache, lc, l)
+	cw := bannersvc.NewCacheWriter(s.Storage, s.Cache, lc, l)
+
+	read := make(chan *entity.Banner)
+	go func() {
+		got, _, _ := cr.BannerByFeatureTag(ctx, b.FeatureID, b.TagIDs[0], false)
+		read <- got
+	}()
+	<-slow.read
+
+	// the update is committed and evicted while the read of the old banner is still running
+	title := gofakeit.Sentence(3)
+	_, _, err := cw.UpdateBanner(ctx, &entity.UpdatableBanner{ID: id, Title: &title})
+	require.NoError(t, err)
+	close(slow.release)
+	require.Equal(t, b.Content.Title, (<-read).Title)
+	time.Sleep(200 * time.Millisecond) // the redis cache is updated asynchronously
+
+	got, _, err := cr.BannerByFeatureTag(ctx, b.FeatureID, b.TagIDs[0], false)
+	require.NoError(t, err)
+	require.Equal(t, title, got.Title)
+	e.GET("/user_banner").
+		WithQuery("feature_id", b.FeatureID).WithQuery("tag_id", b.TagIDs[0]).
+		WithQuery("use_last_revision", false).
+		WithHeader("Authorization", "Bearer "+tokenUsr).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("title").IsEqual(title)
+}
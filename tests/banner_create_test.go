@@ -33,27 +33,30 @@ func TestBannerCreate_Successful(t *testing.T) {
 		Value("banner_id").Number()
 }
 
-func TestBannerCreate_BannerAlreadyExists(t *testing.T) {
-	e, _, tokenAdm := initTest(t)
-	b := newCreateBannerDTO()
+func TestBannerCreate_OverlappingBanner(t *testing.T) {
+	e, tokenUsr, tokenAdm := initTest(t)
+	b1 := newCreateBannerDTO()
+	b2 := createBannerDTO(b1.FeatureID, b1.TagIDs, true)
 
-	e.POST("/banner").
-		WithMaxRetries(5).
-		WithJSON(b).
-		WithHeader("Authorization", "Bearer "+tokenAdm).
-		Expect().
-		Status(http.StatusCreated).
-		JSON().Object().ContainsKey("banner_id").
-		Value("banner_id").Number()
+	for _, b := range []banner.CreateDTO{b1, b2} {
+		e.POST("/banner").
+			WithMaxRetries(5).
+			WithJSON(b).
+			WithHeader("Authorization", "Bearer "+tokenAdm).
+			Expect().
+			Status(http.StatusCreated).
+			JSON().Object().ContainsKey("banner_id")
+	}
 
-	e.POST("/banner").
-		WithMaxRetries(5).
-		WithJSON(b).
-		WithHeader("Authorization", "Bearer "+tokenAdm).
+	// the newest banner wins on the equal priority
+	e.GET("/user_banner").
+		WithQuery("feature_id", b1.FeatureID).
+		WithQuery("tag_id", b1.TagIDs[0]).
+		WithQuery("use_last_revision", true).
+		WithHeader("Authorization", "Bearer "+tokenUsr).
 		Expect().
-		Status(http.StatusConflict).
-		JSON().Object().ContainsKey("error").
-		Value("error").String().Length().Gt(0)
+		Status(http.StatusOK).
+		JSON().Object().Value("title").IsEqual(b2.Content.Title)
 }
 
 func TestBannerCreate_InvalidData_FailCases(t *testing.T) {
@@ -34,3 +34,199 @@ func TestBannerDeleteByFeatureTag_AsUser_Fail(t *testing.T) {
 		Status(http.StatusOK).
 		JSON().Object().Value("title").IsEqual(b.Content.Title)
 }
+
+func TestBannerDeleteByFeatureTag_Successful(t *testing.T) {
+	e, _, tokenAdm := initTest(t)
+	b := newCreateBannerDTO()
+
+	e.POST("/banner").
+		WithMaxRetries(5).
+		WithJSON(b).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusCreated)
+
+	jobID := e.DELETE("/banner").
+		WithQuery("feature_id", b.FeatureID).
+		WithQuery("tag_id", b.TagIDs[0]).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusAccepted).
+		JSON().Object().Value("job_id").String().NotEmpty().Raw()
+
+	job := waitJobFinished(t, e, tokenAdm, jobID)
+	job.Value("status").IsEqual("succeeded")
+	job.Value("attempts").IsEqual(1)
+	job.Value("affected").IsEqual(1)
+	job.ContainsKey("started_at").ContainsKey("finished_at").NotContainsKey("error_message")
+
+	e.GET("/user_banner").
+		WithQuery("feature_id", b.FeatureID).
+		WithQuery("tag_id", b.TagIDs[1]).
+		WithQuery("use_last_revision", true).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusNotFound)
+}
+
+func TestBannerDeleteByFeatureTag_NothingMatches_JobSucceeded(t *testing.T) {
+	e, _, tokenAdm := initTest(t)
+
+	jobID := e.DELETE("/banner").
+		WithQuery("feature_id", getNextFeatureID()).
+		WithQuery("tag_id", getNextTagIDs(1)[0]).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusAccepted).
+		JSON().Object().Value("job_id").String().Raw()
+
+	job := waitJobFinished(t, e, tokenAdm, jobID)
+	job.Value("status").IsEqual("succeeded")
+	job.Value("affected").IsEqual(0)
+	job.NotContainsKey("error_message")
+}
+
+func TestJob_NotFound(t *testing.T) {
+	e, tokenUser, tokenAdm := initTest(t)
+
+	e.GET("/jobs/{id}", "unknown").
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusNotFound)
+
+	e.GET("/jobs/{id}", "unknown").
+		WithHeader("Authorization", "Bearer "+tokenUser).
+		Expect().
+		Status(http.StatusForbidden)
+}
+
+func TestBannerDeleteByFeature_Successful(t *testing.T) {
+	e, _, tokenAdm := initTest(t)
+	featureID := getNextFeatureID()
+	b1 := createBannerDTO(featureID, getNextTagIDs(2), true)
+	b2 := createBannerDTO(featureID, getNextTagIDs(1), false)
+	other := newCreateBannerDTO()
+
+	for _, b := range []any{b1, b2, other} {
+		e.POST("/banner").
+			WithJSON(b).
+			WithHeader("Authorization", "Bearer "+tokenAdm).
+			Expect().
+			Status(http.StatusCreated)
+	}
+
+	jobID := e.DELETE("/banner").
+		WithQuery("feature_id", featureID).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusAccepted).
+		JSON().Object().Value("job_id").String().Raw()
+
+	job := waitJobFinished(t, e, tokenAdm, jobID)
+	job.Value("status").IsEqual("succeeded")
+	job.Value("affected").IsEqual(2)
+
+	e.GET("/banner").
+		WithQuery("feature_id", featureID).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("banners").Array().IsEmpty()
+
+	e.GET("/banner").
+		WithQuery("feature_id", other.FeatureID).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("banners").Array().Length().IsEqual(1)
+}
+
+func TestBannerDeleteByTag_Successful(t *testing.T) {
+	e, _, tokenAdm := initTest(t)
+	tagIDs := getNextTagIDs(2)
+	// b1 keeps its second tag, b2 is left with no tags and is deleted
+	b1 := createBannerDTO(getNextFeatureID(), tagIDs, true)
+	b2 := createBannerDTO(getNextFeatureID(), tagIDs[:1], true)
+
+	for _, b := range []any{b1, b2} {
+		e.POST("/banner").
+			WithJSON(b).
+			WithHeader("Authorization", "Bearer "+tokenAdm).
+			Expect().
+			Status(http.StatusCreated)
+	}
+
+	jobID := e.DELETE("/banner").
+		WithQuery("tag_id", tagIDs[0]).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusAccepted).
+		JSON().Object().Value("job_id").String().Raw()
+
+	job := waitJobFinished(t, e, tokenAdm, jobID)
+	job.Value("status").IsEqual("succeeded")
+	job.Value("affected").IsEqual(2)
+
+	resp := e.GET("/banner").
+		WithQuery("feature_id", b1.FeatureID).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("banners").Array()
+	resp.Length().IsEqual(1)
+	resp.Value(0).Object().Value("tag_ids").IsEqual([]int64{tagIDs[1]})
+
+	e.GET("/banner").
+		WithQuery("feature_id", b2.FeatureID).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("banners").Array().IsEmpty()
+
+	e.GET("/banner/{id}/versions", rawToInt64(resp.Value(0).Object().Value("banner_id").Raw())).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array().Value(0).Object().Value("tag_ids").IsEqual(tagIDs)
+}
+
+func TestBannerDeleteByFeatureTag_NoParams_BadRequest(t *testing.T) {
+	e, _, tokenAdm := initTest(t)
+
+	e.DELETE("/banner").
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusBadRequest)
+}
+
+func TestBannerDeleteByFeatureTag_MalformedID_BadRequest(t *testing.T) {
+	e, _, tokenAdm := initTest(t)
+	b := newCreateBannerDTO()
+	createBanner(e, tokenAdm, b)
+
+	for _, q := range []map[string]any{
+		{"feature_id": b.FeatureID, "tag_id": "abc"},
+		{"feature_id": "abc", "tag_id": b.TagIDs[0]},
+		{"feature_id": ""},
+	} {
+		req := e.DELETE("/banner").WithHeader("Authorization", "Bearer "+tokenAdm)
+		for k, v := range q {
+			req = req.WithQuery(k, v)
+		}
+		req.Expect().Status(http.StatusBadRequest)
+	}
+
+	// nothing is deleted
+	e.GET("/banner").
+		WithQuery("feature_id", b.FeatureID).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("banners").Array().Length().IsEqual(1)
+	e.GET("/banner").
+		WithQuery("tag_id", b.TagIDs[0]).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("banners").Array().Value(0).Object().Value("tag_ids").IsEqual(b.TagIDs)
+}
@@ -0,0 +1,118 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gavv/httpexpect/v2"
+
+	"banners-management/internal/model/dto/banner"
+)
+
+func TestBannerPriority_Resolution(t *testing.T) {
+	e, tokenUsr, tokenAdm := initTest(t)
+	low := newCreateBannerDTO()
+	high := createBannerDTO(low.FeatureID, low.TagIDs[:1], true)
+	high.Priority = 10
+	// created last, but has the lowest priority
+	newest := createBannerDTO(low.FeatureID, low.TagIDs, true)
+	newest.Priority = -1
+
+	createBanner(e, tokenAdm, low)
+	highID := createBanner(e, tokenAdm, high)
+	newestID := createBanner(e, tokenAdm, newest)
+
+	userBannerTitle(e, tokenUsr, low.FeatureID, low.TagIDs[0]).IsEqual(high.Content.Title)
+	userBannerTitle(e, tokenUsr, low.FeatureID, low.TagIDs[1]).IsEqual(low.Content.Title)
+
+	// the inactive banner falls back to the next one
+	isActive := false
+	e.PATCH("/banner/{id}", highID).
+		WithJSON(banner.UpdateDTO{IsActive: &isActive}).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK)
+	userBannerTitle(e, tokenUsr, low.FeatureID, low.TagIDs[0]).IsEqual(low.Content.Title)
+
+	priority := 1
+	e.PATCH("/banner/{id}", newestID).
+		WithJSON(banner.UpdateDTO{Priority: &priority}).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK)
+	userBannerTitle(e, tokenUsr, low.FeatureID, low.TagIDs[1]).IsEqual(newest.Content.Title)
+}
+
+func TestBannerPriority_DeleteByFeatureTag(t *testing.T) {
+	e, tokenUsr, tokenAdm := initTest(t)
+	b1 := newCreateBannerDTO()
+	b2 := createBannerDTO(b1.FeatureID, b1.TagIDs[:1], true)
+	createBanner(e, tokenAdm, b1)
+	createBanner(e, tokenAdm, b2)
+
+	jobID := e.DELETE("/banner").
+		WithQuery("feature_id", b1.FeatureID).
+		WithQuery("tag_id", b1.TagIDs[0]).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusAccepted).
+		JSON().Object().Value("job_id").String().Raw()
+
+	// both banners of the feature and tag are deleted
+	job := waitJobFinished(t, e, tokenAdm, jobID)
+	job.Value("status").IsEqual("succeeded")
+	job.Value("affected").IsEqual(2)
+
+	for _, tagID := range b1.TagIDs {
+		e.GET("/user_banner").
+			WithQuery("feature_id", b1.FeatureID).
+			WithQuery("tag_id", tagID).
+			WithQuery("use_last_revision", true).
+			WithHeader("Authorization", "Bearer "+tokenUsr).
+			Expect().
+			Status(http.StatusNotFound)
+	}
+}
+
+// userBannerTitle returns the title of the last revision of the banner, that is shown to the user.
+func TestBannerPriority_CompetingWindowStart_CacheExpired(t *testing.T) {
+	e, tokenUsr, tokenAdm := initTest(t)
+	low := newCreateBannerDTO()
+	high := createBannerDTO(low.FeatureID, low.TagIDs[:1], true)
+	high.Priority = 10
+	from := time.Now().Add(3 * time.Second)
+	high.ActiveFrom = &from
+	createBanner(e, tokenAdm, low)
+	createBanner(e, tokenAdm, high)
+
+	// the low priority banner is cached, while the high priority one is not started yet
+	waitUserBannerCached(t, e, tokenUsr, low.FeatureID, low.TagIDs[0], http.StatusOK)
+	cachedUserBanner := func() *httpexpect.Response {
+		return e.GET("/user_banner").
+			WithQuery("feature_id", low.FeatureID).
+			WithQuery("tag_id", low.TagIDs[0]).
+			WithQuery("use_last_revision", false).
+			WithHeader("Authorization", "Bearer "+tokenUsr).
+			Expect().
+			Status(http.StatusOK)
+	}
+	resp := cachedUserBanner()
+	resp.Header("X-Cache").IsEqual("HIT")
+	resp.JSON().Object().Value("title").IsEqual(low.Content.Title)
+
+	// the cache entry doesn't outlive the start of the competing banner
+	time.Sleep(time.Until(from) + 100*time.Millisecond)
+	cachedUserBanner().JSON().Object().Value("title").IsEqual(high.Content.Title)
+}
+
+func userBannerTitle(e *httpexpect.Expect, tokenUsr string, featureID, tagID int64) *httpexpect.Value {
+	return e.GET("/user_banner").
+		WithQuery("feature_id", featureID).
+		WithQuery("tag_id", tagID).
+		WithQuery("use_last_revision", true).
+		WithHeader("Authorization", "Bearer "+tokenUsr).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("title")
+}
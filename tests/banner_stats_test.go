@@ -0,0 +1,163 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/gavv/httpexpect/v2"
+
+	bannersvc "banners-management/internal/service/banner"
+)
+
+// waitTodayStats waits for the stats of the banner by id for today to have at least the given impressions
+// and clicks flushed, and returns them. variantID is ignored if it's negative.
+// The impressions and the clicks may be flushed at different times, so both of them are awaited.
+func waitTodayStats(
+	t *testing.T,
+	e *httpexpect.Expect,
+	token string,
+	id, variantID, impressions, clicks int64,
+) *httpexpect.Object {
+	t.Helper()
+
+	for range 50 {
+		req := e.GET("/banner/{id}/stats", id).
+			WithHeader("Authorization", "Bearer "+token)
+		if variantID >= 0 {
+			req = req.WithQuery("variant_id", variantID)
+		}
+		days := req.Expect().
+			Status(http.StatusOK).
+			JSON().Array()
+		days.Length().IsEqual(bannersvc.DefaultStatsDays)
+		today := days.Value(bannersvc.DefaultStatsDays - 1).Object()
+		if rawToInt64(today.Value("impressions").Raw()) >= impressions &&
+			rawToInt64(today.Value("clicks").Raw()) >= clicks {
+			return today
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	t.Fatalf("stats of banner %d were not flushed", id)
+	return nil
+}
+
+func TestBannerStats_ImpressionsAndClicks(t *testing.T) {
+	e, tokenUsr, tokenAdm := initTest(t)
+	b := newCreateBannerDTO()
+	id := createBanner(e, tokenAdm, b)
+
+	for range 3 {
+		e.GET("/user_banner").
+			WithQuery("feature_id", b.FeatureID).
+			WithQuery("tag_id", b.TagIDs[0]).
+			WithQuery("use_last_revision", true).
+			WithHeader("Authorization", "Bearer "+tokenUsr).
+			Expect().
+			Status(http.StatusOK)
+	}
+	e.GET("/user_banner/click").
+		WithQuery("feature_id", b.FeatureID).
+		WithQuery("tag_id", b.TagIDs[0]).
+		WithHeader("Authorization", "Bearer "+tokenUsr).
+		WithRedirectPolicy(httpexpect.DontFollowRedirects).
+		Expect().
+		Status(http.StatusFound).
+		Header("Location").IsEqual(b.Content.URL)
+
+	today := waitTodayStats(t, e, tokenAdm, id, -1, 3, 1)
+	today.Value("day").IsEqual(time.Now().UTC().Format(time.DateOnly))
+	today.Value("impressions").IsEqual(3)
+	today.Value("clicks").IsEqual(1)
+	today.Value("ctr").Number().InDelta(1.0/3, 1e-9)
+
+	e.GET("/banner/{id}/stats", id).
+		WithQuery("from", "2024-01-01").
+		WithQuery("to", "2024-01-07").
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array().Length().IsEqual(7)
+}
+
+func TestBannerStats_Variants(t *testing.T) {
+	e, tokenUsr, tokenAdm := initTest(t)
+	b := newCreateBannerDTO()
+	id := createBanner(e, tokenAdm, b)
+	v := newCreateVariantDTO(1)
+	vID := createVariant(e, tokenAdm, id, v)
+
+	get := func(user string) {
+		e.GET("/user_banners").
+			WithQuery("feature_id", b.FeatureID).
+			WithQuery("tag_id", b.TagIDs[0]).
+			WithQuery("user_id", user).
+			WithQuery("use_last_revision", true).
+			WithHeader("Authorization", "Bearer "+tokenUsr).
+			Expect().
+			Status(http.StatusOK)
+	}
+	// the unknown user gets the banner itself, the others get the only variant
+	get("")
+	get(gofakeit.UUID())
+	get(gofakeit.UUID())
+
+	waitTodayStats(t, e, tokenAdm, id, -1, 3, 0)
+	waitTodayStats(t, e, tokenAdm, id, vID, 2, 0).Value("impressions").IsEqual(2)
+	waitTodayStats(t, e, tokenAdm, id, 0, 1, 0).Value("impressions").IsEqual(1)
+
+	e.GET("/user_banner/click").
+		WithQuery("feature_id", b.FeatureID).
+		WithQuery("tag_id", b.TagIDs[0]).
+		WithQuery("user_id", gofakeit.UUID()).
+		WithHeader("Authorization", "Bearer "+tokenUsr).
+		WithRedirectPolicy(httpexpect.DontFollowRedirects).
+		Expect().
+		Status(http.StatusFound).
+		Header("Location").IsEqual(v.Content.URL)
+}
+
+func TestBannerStats_Errors(t *testing.T) {
+	e, tokenUsr, tokenAdm := initTest(t)
+	b := createBannerDTO(getNextFeatureID(), getNextTagIDs(1), false)
+	id := createBanner(e, tokenAdm, b)
+
+	e.GET("/user_banner/click").
+		WithQuery("feature_id", b.FeatureID).
+		WithQuery("tag_id", b.TagIDs[0]).
+		WithHeader("Authorization", "Bearer "+tokenUsr).
+		WithRedirectPolicy(httpexpect.DontFollowRedirects).
+		Expect().
+		Status(http.StatusForbidden)
+	e.GET("/user_banner/click").
+		WithQuery("feature_id", b.FeatureID).
+		WithHeader("Authorization", "Bearer "+tokenUsr).
+		Expect().
+		Status(http.StatusBadRequest)
+
+	for _, q := range []map[string]string{
+		{"from": "2024-13-01"},
+		{"to": "yesterday"},
+		{"variant_id": "first"},
+		{"from": "2024-01-02", "to": "2024-01-01"},
+		{"from": "2023-01-01", "to": "2024-01-02"},
+	} {
+		req := e.GET("/banner/{id}/stats", id).
+			WithHeader("Authorization", "Bearer "+tokenAdm)
+		for k, v := range q {
+			req = req.WithQuery(k, v)
+		}
+		req.Expect().Status(http.StatusBadRequest)
+	}
+
+	e.GET("/banner/{id}/stats", 0).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusNotFound)
+	e.GET("/banner/{id}/stats", id).
+		WithHeader("Authorization", "Bearer "+tokenUsr).
+		Expect().
+		Status(http.StatusForbidden)
+}
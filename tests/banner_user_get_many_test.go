@@ -0,0 +1,96 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestBannerUserGetMany_PicksFirstMatchingTag(t *testing.T) {
+	e, tokenUsr, tokenAdm := initTest(t)
+	featureID := getNextFeatureID()
+	tags := getNextTagIDs(3)
+	general := createBannerDTO(featureID, []int64{tags[0]}, true)
+	specific := createBannerDTO(featureID, []int64{tags[1]}, true)
+	inactive := createBannerDTO(featureID, []int64{tags[2]}, false)
+	for _, b := range []any{general, specific, inactive} {
+		e.POST("/banner").
+			WithMaxRetries(5).
+			WithJSON(b).
+			WithHeader("Authorization", "Bearer "+tokenAdm).
+			Expect().
+			Status(http.StatusCreated)
+	}
+
+	// the inactive banner is skipped, and the specific tag goes before the general one
+	arr := e.GET("/user_banners").
+		WithQuery("feature_id", featureID).
+		WithQuery("tag_ids", fmt.Sprintf("%d,%d,%d", tags[2], tags[1], tags[0])).
+		WithHeader("Authorization", "Bearer "+tokenUsr).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("banners").Array()
+	arr.Length().IsEqual(1)
+	item := arr.Value(0).Object()
+	item.Value("feature_id").IsEqual(featureID)
+	item.Value("tag_id").IsEqual(tags[1])
+	item.Value("title").IsEqual(specific.Content.Title)
+
+	e.GET("/user_banners").
+		WithQuery("feature_id", featureID).
+		WithQuery("tag_ids", fmt.Sprintf("%d,%d", tags[0], tags[1])).
+		WithQuery("use_last_revision", true).
+		WithHeader("Authorization", "Bearer "+tokenUsr).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("banners").Array().Value(0).Object().
+		Value("title").IsEqual(general.Content.Title)
+}
+
+func TestBannerUserGetMany_SeveralFeatures(t *testing.T) {
+	e, tokenUsr, tokenAdm := initTest(t)
+	tagID := getNextTagIDs(1)[0]
+	b1 := createBannerDTO(getNextFeatureID(), []int64{tagID}, true)
+	b2 := createBannerDTO(getNextFeatureID(), []int64{tagID}, true)
+	missingFeatureID := getNextFeatureID()
+	for _, b := range []any{b1, b2} {
+		e.POST("/banner").
+			WithMaxRetries(5).
+			WithJSON(b).
+			WithHeader("Authorization", "Bearer "+tokenAdm).
+			Expect().
+			Status(http.StatusCreated)
+	}
+
+	// cached and uncached banners are resolved alike
+	for range 2 {
+		arr := e.GET("/user_banners").
+			WithQuery("feature_ids", fmt.Sprintf("%d,%d,%d", b1.FeatureID, missingFeatureID, b2.FeatureID)).
+			WithQuery("tag_id", tagID).
+			WithHeader("Authorization", "Bearer "+tokenUsr).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().Value("banners").Array()
+		arr.Length().IsEqual(2)
+		arr.Value(0).Object().Value("title").IsEqual(b1.Content.Title)
+		arr.Value(1).Object().Value("title").IsEqual(b2.Content.Title)
+	}
+}
+
+func TestBannerUserGetMany_InvalidParameters(t *testing.T) {
+	e, tokenUsr, _ := initTest(t)
+
+	cases := []map[string]any{
+		{"tag_ids": "1,2"},
+		{"feature_id": 1},
+		{"feature_id": 1, "tag_ids": "1,x"},
+		{"feature_ids": "1,2,3,4,5,6,7,8,9,10,11", "tag_ids": "1,2,3,4,5,6,7,8,9,10"},
+	}
+	for _, c := range cases {
+		req := e.GET("/user_banners").WithHeader("Authorization", "Bearer "+tokenUsr)
+		for k, v := range c {
+			req = req.WithQuery(k, v)
+		}
+		req.Expect().Status(http.StatusBadRequest)
+	}
+}
@@ -123,3 +123,25 @@ func TestBannerUserGet_InvalidData_FailCases(t *testing.T) {
 		})
 	}
 }
+
+func TestBannerUserGet_RevisionHeaders(t *testing.T) {
+	e, tokenUsr, tokenAdm := initTest(t)
+	b := newCreateBannerDTO()
+
+	e.POST("/banner").
+		WithMaxRetries(5).
+		WithJSON(b).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect()
+
+	resp := e.GET("/user_banner").
+		WithMaxRetries(5).
+		WithQuery("feature_id", b.FeatureID).WithQuery("tag_id", b.TagIDs[0]).
+		WithQuery("use_last_revision", true).
+		WithHeader("Authorization", "Bearer "+tokenUsr).
+		Expect().
+		Status(http.StatusOK)
+
+	resp.Header("X-Cache").IsEqual("MISS")
+	resp.Header("Age").AsNumber().Ge(0)
+}
@@ -0,0 +1,192 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/gavv/httpexpect/v2"
+
+	"banners-management/internal/model/dto/banner"
+	"banners-management/internal/model/entity"
+)
+
+// newCreateVariantDTO returns a new banner.CreateVariantDTO with random content and the weight.
+func newCreateVariantDTO(weight int) banner.CreateVariantDTO {
+	return banner.CreateVariantDTO{
+		Content: banner.CreateContent{
+			Title: gofakeit.Word(),
+			Text:  gofakeit.Word(),
+			URL:   gofakeit.URL(),
+		},
+		Weight: &weight,
+	}
+}
+
+// createVariant creates the variant of the banner by id and returns the variant id.
+func createVariant(e *httpexpect.Expect, tokenAdm string, id int64, dto banner.CreateVariantDTO) int64 {
+	v := e.POST("/banner/{id}/variants", id).
+		WithJSON(dto).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().Value("variant_id").Raw()
+
+	return rawToInt64(v)
+}
+
+func TestBannerVariants_CRUD(t *testing.T) {
+	e, _, tokenAdm := initTest(t)
+	id := createBanner(e, tokenAdm, newCreateBannerDTO())
+
+	dto := newCreateVariantDTO(0)
+	dto.Weight = nil
+	vID := createVariant(e, tokenAdm, id, dto)
+
+	vs := e.GET("/banner/{id}/variants", id).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array()
+	vs.Length().IsEqual(1)
+	vs.Value(0).Object().Value("variant_id").IsEqual(vID)
+	vs.Value(0).Object().Value("weight").IsEqual(banner.DefaultVariantWeight)
+	vs.Value(0).Object().Value("content").Object().Value("title").IsEqual(dto.Content.Title)
+
+	weight := 5
+	e.PATCH("/banner/{id}/variants/{variant_id}", id, vID).
+		WithJSON(banner.UpdateVariantDTO{Weight: &weight}).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK)
+	e.GET("/banner/{id}/variants", id).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array().Value(0).Object().Value("weight").IsEqual(weight)
+
+	events := e.GET("/audit").
+		WithQuery("banner_id", id).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array()
+	events.Value(0).Object().Value("action").IsEqual(entity.AuditVariantUpdate)
+	events.Value(0).Object().Value("variant_id").IsEqual(vID)
+	events.Value(0).Object().Value("diff").Object().Value("weight").Object().Value("after").IsEqual(weight)
+
+	e.DELETE("/banner/{id}/variants/{variant_id}", id, vID).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusNoContent)
+	e.GET("/banner/{id}/variants", id).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array().IsEmpty()
+
+	e.DELETE("/banner/{id}/variants/{variant_id}", id, vID).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusNotFound)
+	e.POST("/banner/{id}/variants", 0).
+		WithJSON(newCreateVariantDTO(1)).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusNotFound)
+	e.POST("/banner/{id}/variants", id).
+		WithJSON(newCreateVariantDTO(-1)).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusBadRequest)
+}
+
+func TestBannerVariants_Assignment(t *testing.T) {
+	e, tokenUsr, tokenAdm := initTest(t)
+	b := newCreateBannerDTO()
+	id := createBanner(e, tokenAdm, b)
+	a, z := newCreateVariantDTO(1), newCreateVariantDTO(0)
+	aID := createVariant(e, tokenAdm, id, a)
+	zID := createVariant(e, tokenAdm, id, z)
+
+	get := func(user string) *httpexpect.Object {
+		req := e.GET("/user_banner").
+			WithQuery("feature_id", b.FeatureID).
+			WithQuery("tag_id", b.TagIDs[0]).
+			WithQuery("use_last_revision", true).
+			WithHeader("Authorization", "Bearer "+tokenUsr)
+		if user != "" {
+			req = req.WithQuery("user_id", user)
+		}
+		return req.Expect().Status(http.StatusOK).JSON().Object()
+	}
+
+	// the unknown users get the banner itself
+	get("").NotContainsKey("variant_id").Value("title").IsEqual(b.Content.Title)
+	for range 5 {
+		resp := get(gofakeit.UUID())
+		resp.Value("variant_id").IsEqual(aID)
+		resp.Value("title").IsEqual(a.Content.Title)
+	}
+
+	one := 1
+	e.PATCH("/banner/{id}/variants/{variant_id}", id, zID).
+		WithJSON(banner.UpdateVariantDTO{Weight: &one}).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK)
+	// the assignment is sticky while the weights are unchanged
+	user := gofakeit.UUID()
+	assigned := get(user).Value("variant_id").Raw()
+	for range 5 {
+		get(user).Value("variant_id").IsEqual(assigned)
+	}
+
+	zero := 0
+	e.PATCH("/banner/{id}/variants/{variant_id}", id, aID).
+		WithJSON(banner.UpdateVariantDTO{Weight: &zero}).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK)
+	get(user).Value("variant_id").IsEqual(zID)
+	get(user).Value("title").IsEqual(z.Content.Title)
+
+	e.PATCH("/banner/{id}/variants/{variant_id}", id, zID).
+		WithJSON(banner.UpdateVariantDTO{Weight: &zero}).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK)
+	get(user).NotContainsKey("variant_id").Value("title").IsEqual(b.Content.Title)
+
+	e.GET("/user_banners").
+		WithQuery("feature_id", b.FeatureID).
+		WithQuery("tag_id", b.TagIDs[0]).
+		WithQuery("user_id", user).
+		WithQuery("use_last_revision", true).
+		WithHeader("Authorization", "Bearer "+tokenUsr).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("banners").Array().Value(0).Object().NotContainsKey("variant_id")
+}
+
+func TestBannerVariants_EditorScopes(t *testing.T) {
+	e, _, tokenAdm := initTest(t)
+	own, foreign := newCreateBannerDTO(), newCreateBannerDTO()
+	ownID, foreignID := createBanner(e, tokenAdm, own), createBanner(e, tokenAdm, foreign)
+	token := scopedToken(t, entity.RoleEditor, entity.FeatureWriteScope(own.FeatureID))
+
+	e.POST("/banner/{id}/variants", ownID).
+		WithJSON(newCreateVariantDTO(1)).
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(http.StatusCreated)
+	e.POST("/banner/{id}/variants", foreignID).
+		WithJSON(newCreateVariantDTO(1)).
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(http.StatusForbidden)
+	e.GET("/banner/{id}/variants", foreignID).
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(http.StatusOK)
+}
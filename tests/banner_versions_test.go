@@ -0,0 +1,159 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestBannerVersions_AsUser_Forbidden(t *testing.T) {
+	e, tokenUser, tokenAdm := initTest(t)
+
+	v := e.POST("/banner").
+		WithMaxRetries(5).
+		WithJSON(newCreateBannerDTO()).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		JSON().Object().Value("banner_id")
+	id := rawToInt64(v.Raw())
+
+	e.GET("/banner/{id}/versions", id).
+		WithHeader("Authorization", "Bearer "+tokenUser).
+		Expect().
+		Status(http.StatusForbidden)
+
+	e.POST("/banner/{id}/versions/{version}/restore", id, 1).
+		WithHeader("Authorization", "Bearer "+tokenUser).
+		Expect().
+		Status(http.StatusForbidden)
+}
+
+func TestBannerVersions_UpdateCreatesVersion(t *testing.T) {
+	e, _, tokenAdm := initTest(t)
+	b := newCreateBannerDTO()
+
+	v := e.POST("/banner").
+		WithMaxRetries(5).
+		WithJSON(b).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		JSON().Object().Value("banner_id")
+	id := rawToInt64(v.Raw())
+
+	e.GET("/banner/{id}/versions", id).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array().IsEmpty()
+
+	e.PATCH("/banner/{id}", id).
+		WithJSON(newUpdateBannerDTO()).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK)
+
+	versions := e.GET("/banner/{id}/versions", id).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array()
+	versions.Length().IsEqual(1)
+	version := versions.Value(0).Object()
+	version.Value("version").IsEqual(1)
+	version.Value("feature_id").IsEqual(b.FeatureID)
+	version.Value("tag_ids").IsEqual(b.TagIDs)
+	version.Value("content").Object().Value("title").IsEqual(b.Content.Title)
+}
+
+func TestBannerVersions_Restore_Successful(t *testing.T) {
+	e, _, tokenAdm := initTest(t)
+	b := newCreateBannerDTO()
+
+	v := e.POST("/banner").
+		WithMaxRetries(5).
+		WithJSON(b).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		JSON().Object().Value("banner_id")
+	id := rawToInt64(v.Raw())
+
+	e.PATCH("/banner/{id}", id).
+		WithJSON(newUpdateBannerDTO()).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK)
+
+	e.POST("/banner/{id}/versions/{version}/restore", id, 1).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK)
+
+	e.GET("/user_banner").
+		WithQuery("feature_id", b.FeatureID).WithQuery("tag_id", b.TagIDs[0]).
+		WithQuery("use_last_revision", true).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("title").IsEqual(b.Content.Title)
+
+	e.GET("/banner/{id}/versions", id).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array().Length().IsEqual(2)
+}
+
+func TestBannerVersions_Restore_NotFound(t *testing.T) {
+	e, _, tokenAdm := initTest(t)
+
+	v := e.POST("/banner").
+		WithMaxRetries(5).
+		WithJSON(newCreateBannerDTO()).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		JSON().Object().Value("banner_id")
+	id := rawToInt64(v.Raw())
+
+	e.POST("/banner/{id}/versions/{version}/restore", id, 1).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusNotFound)
+
+	e.GET("/banner/{id}/versions", 0).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusNotFound)
+}
+
+func TestBannerVersions_Restore_OverlappingBanner(t *testing.T) {
+	e, _, tokenAdm := initTest(t)
+	b1 := newCreateBannerDTO()
+	b2 := newCreateBannerDTO()
+
+	v := e.POST("/banner").
+		WithMaxRetries(5).
+		WithJSON(b1).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		JSON().Object().Value("banner_id")
+	id1 := rawToInt64(v.Raw())
+
+	e.PATCH("/banner/{id}", id1).
+		WithJSON(newUpdateBannerDTO()).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK)
+
+	b2.FeatureID = b1.FeatureID
+	b2.TagIDs = b1.TagIDs
+	e.POST("/banner").
+		WithMaxRetries(5).
+		WithJSON(b2).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusCreated)
+
+	e.POST("/banner/{id}/versions/{version}/restore", id1, 1).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK)
+}
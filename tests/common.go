@@ -1,9 +1,11 @@
 package tests
 
 import (
+	"net/http"
 	"net/url"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/brianvoe/gofakeit/v6"
 	"github.com/gavv/httpexpect/v2"
@@ -56,7 +58,7 @@ func rawToInt64(f interface{}) int64 {
 
 // getNextTagIDs returns the next tag IDs.
 // It is unique for each call. It is thread-safe.
-// The uniqueness is needed to avoid conflicts with the database trigger.
+// The uniqueness is needed to keep the banners of different tests from overlapping.
 func getNextTagIDs(count int) []int64 {
 	res := make([]int64, count)
 	muTag.Lock()
@@ -70,7 +72,7 @@ func getNextTagIDs(count int) []int64 {
 
 // getNextFeatureID returns the next feature ID.
 // It is unique for each call. It is thread-safe.
-// The uniqueness is needed to avoid conflicts with the database trigger.
+// The uniqueness is needed to keep the banners of different tests from overlapping.
 func getNextFeatureID() int64 {
 	muFeature.Lock()
 	defer muFeature.Unlock()
@@ -106,6 +108,19 @@ func createBannerDTO(featureID int64, tagIDs []int64, isActive bool) banner.Crea
 	}
 }
 
+// createBanner creates the banner and returns its id.
+func createBanner(e *httpexpect.Expect, tokenAdm string, dto banner.CreateDTO) int64 {
+	v := e.POST("/banner").
+		WithMaxRetries(5).
+		WithJSON(dto).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().Value("banner_id").Raw()
+
+	return rawToInt64(v)
+}
+
 // newUpdateBannerDTO returns a new banner.UpdateDTO with random parameters.
 func newUpdateBannerDTO() banner.UpdateDTO {
 	tagIDs := getNextTagIDs(2)
@@ -144,3 +159,47 @@ func updateBannerDTO(featureID *int64, tagIDs *[]int64, isActive *bool) banner.U
 		IsActive: isActive,
 	}
 }
+
+// waitUserBannerCached requests the user banner with use_last_revision=false
+// until it is served from cache with the expected status.
+// The cache is populated asynchronously, so it may take a few requests.
+func waitUserBannerCached(t *testing.T, e *httpexpect.Expect, token string, featureID, tagID int64, status int) {
+	t.Helper()
+
+	for range 50 {
+		resp := e.GET("/user_banner").
+			WithQuery("feature_id", featureID).WithQuery("tag_id", tagID).
+			WithQuery("use_last_revision", false).
+			WithHeader("Authorization", "Bearer "+token).
+			Expect().
+			Status(status)
+		if resp.Raw().Header.Get("X-Cache") == "HIT" {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	t.Fatalf("user banner (feature %d, tag %d) was not cached with status %d (%s)",
+		featureID, tagID, status, http.StatusText(status))
+}
+
+// waitJobFinished requests the job state until the job is finished and returns its final state.
+// The jobs are executed asynchronously, so it may take a few requests.
+func waitJobFinished(t *testing.T, e *httpexpect.Expect, token, jobID string) *httpexpect.Object {
+	t.Helper()
+
+	for range 50 {
+		job := e.GET("/jobs/{id}", jobID).
+			WithHeader("Authorization", "Bearer "+token).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object()
+		if s := job.Value("status").String().Raw(); s == "succeeded" || s == "failed" {
+			return job
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	t.Fatalf("job %s was not finished", jobID)
+	return nil
+}
@@ -0,0 +1,141 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+)
+
+// unseededIDOffset is added to the generated IDs, so they're not among the seeded features and tags.
+const unseededIDOffset = 1_000_000
+
+func TestFeature_CRUD(t *testing.T) {
+	e, _, tokenAdm := initTest(t)
+	id := unseededIDOffset + getNextFeatureID()
+
+	e.POST("/feature").
+		WithJSON(map[string]any{"feature_id": id, "name": "promo", "description": "promo banners"}).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().Value("feature_id").IsEqual(id)
+
+	e.POST("/feature").
+		WithJSON(map[string]any{"feature_id": id}).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusConflict)
+
+	e.PATCH("/feature/{id}", id).
+		WithJSON(map[string]any{"name": "sale"}).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK)
+
+	f := e.GET("/feature/{id}", id).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object()
+	f.Value("name").IsEqual("sale")
+	f.Value("description").IsEqual("promo banners")
+
+	e.DELETE("/feature/{id}", id).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusNoContent)
+
+	e.GET("/feature/{id}", id).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusNotFound)
+}
+
+func TestFeature_List(t *testing.T) {
+	e, _, tokenAdm := initTest(t)
+
+	e.GET("/feature").
+		WithQuery("limit", 2).
+		WithQuery("offset", 1).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Array().Length().IsEqual(2)
+}
+
+func TestFeature_AsUser_Fail(t *testing.T) {
+	e, tokenUsr, _ := initTest(t)
+
+	e.POST("/feature").
+		WithJSON(map[string]any{"feature_id": unseededIDOffset + getNextFeatureID()}).
+		WithHeader("Authorization", "Bearer "+tokenUsr).
+		Expect().
+		Status(http.StatusForbidden)
+}
+
+func TestFeatureTag_InUse_Conflict(t *testing.T) {
+	e, _, tokenAdm := initTest(t)
+	featureID := unseededIDOffset + getNextFeatureID()
+	tagID := unseededIDOffset + getNextTagIDs(1)[0]
+
+	e.POST("/feature").
+		WithJSON(map[string]any{"feature_id": featureID}).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusCreated)
+	e.POST("/tag").
+		WithJSON(map[string]any{"tag_id": tagID, "name": "newcomers"}).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusCreated)
+
+	e.POST("/banner").
+		WithJSON(createBannerDTO(featureID, []int64{tagID}, true)).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusCreated)
+
+	e.DELETE("/feature/{id}", featureID).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusConflict)
+	e.DELETE("/tag/{id}", tagID).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusConflict)
+
+	e.GET("/tag/{id}", tagID).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("name").IsEqual("newcomers")
+}
+
+func TestBanner_UnknownReference_Unprocessable(t *testing.T) {
+	e, _, tokenAdm := initTest(t)
+
+	e.POST("/banner").
+		WithJSON(createBannerDTO(unseededIDOffset+getNextFeatureID(), getNextTagIDs(1), true)).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusUnprocessableEntity)
+
+	e.POST("/banner").
+		WithJSON(createBannerDTO(getNextFeatureID(), []int64{unseededIDOffset + getNextTagIDs(1)[0]}, true)).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusUnprocessableEntity)
+
+	b := newCreateBannerDTO()
+	id := e.POST("/banner").
+		WithJSON(b).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().Value("banner_id").Raw()
+
+	e.PATCH("/banner/{id}", rawToInt64(id)).
+		WithJSON(map[string]any{"feature_id": unseededIDOffset + getNextFeatureID()}).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusUnprocessableEntity)
+}
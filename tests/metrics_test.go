@@ -0,0 +1,42 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	e, tokenUsr, tokenAdm := initTest(t)
+	b := newCreateBannerDTO()
+	id := createBanner(e, tokenAdm, b)
+
+	waitUserBannerCached(t, e, tokenUsr, b.FeatureID, b.TagIDs[0], http.StatusOK)
+	e.GET("/banner/{id}/versions", id).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusOK)
+	jobID := e.DELETE("/banner").
+		WithQuery("feature_id", getNextFeatureID()).
+		WithQuery("tag_id", getNextTagIDs(1)[0]).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusAccepted).
+		JSON().Object().Value("job_id").String().Raw()
+	waitJobFinished(t, e, tokenAdm, jobID)
+
+	body := e.GET("/metrics").
+		Expect().
+		Status(http.StatusOK).
+		Body()
+	body.Contains(`banners_http_requests_total{method="GET",route="GET /user_banner",status="200"}`)
+	// the requests with different ids are counted by the route pattern
+	body.Contains(`route="GET /banner/{id}/versions"`)
+	body.NotContains(`route="/banner/`)
+	body.Contains(`banners_http_request_duration_seconds_bucket{method="GET",route="GET /user_banner",status="200"`)
+	body.Contains(`banners_cache_lookups_total{cache="banner",result="hit"}`)
+	body.Contains(`banners_cache_lookups_total{cache="banner",result="miss"}`)
+	body.Contains(`banners_jobs_delete_events_total{event="scheduled"}`)
+	body.Contains(`banners_jobs_delete_events_total{event="succeeded"}`)
+	body.Contains("banners_pgxpool_total_conns")
+	body.Contains("banners_redis_pool_total_conns")
+}
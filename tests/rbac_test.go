@@ -0,0 +1,144 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"banners-management/internal/model/entity"
+	"banners-management/tests/suit"
+)
+
+// scopedToken returns a token with the role and scopes.
+func scopedToken(t *testing.T, role string, scopes ...string) string {
+	t.Helper()
+	token, err := suit.Setup(t).JwtManager.GenerateToken(role, scopes...)
+	require.NoError(t, err)
+
+	return token
+}
+
+func TestRBAC_Editor_WritesOnlyScopedFeature(t *testing.T) {
+	e, _, tokenAdm := initTest(t)
+	own := newCreateBannerDTO()
+	foreign := newCreateBannerDTO()
+	tokenEditor := scopedToken(t, entity.RoleEditor, entity.FeatureWriteScope(own.FeatureID))
+
+	ownID := e.POST("/banner").
+		WithJSON(own).
+		WithHeader("Authorization", "Bearer "+tokenEditor).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().Value("banner_id").Raw()
+
+	e.POST("/banner").
+		WithJSON(foreign).
+		WithHeader("Authorization", "Bearer "+tokenEditor).
+		Expect().
+		Status(http.StatusForbidden)
+
+	foreignID := e.POST("/banner").
+		WithJSON(foreign).
+		WithHeader("Authorization", "Bearer "+tokenAdm).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().Value("banner_id").Raw()
+
+	// editors can list everything
+	e.GET("/banner").
+		WithQuery("feature_id", foreign.FeatureID).
+		WithHeader("Authorization", "Bearer "+tokenEditor).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("banners").Array().Length().IsEqual(1)
+
+	e.PATCH("/banner/{id}", foreignID).
+		WithJSON(updateBannerDTO(nil, nil, nil)).
+		WithHeader("Authorization", "Bearer "+tokenEditor).
+		Expect().
+		Status(http.StatusForbidden)
+
+	// a banner can't be moved to a feature out of the scopes
+	e.PATCH("/banner/{id}", ownID).
+		WithJSON(updateBannerDTO(&foreign.FeatureID, nil, nil)).
+		WithHeader("Authorization", "Bearer "+tokenEditor).
+		Expect().
+		Status(http.StatusForbidden)
+
+	e.PATCH("/banner/{id}", ownID).
+		WithJSON(updateBannerDTO(nil, nil, nil)).
+		WithHeader("Authorization", "Bearer "+tokenEditor).
+		Expect().
+		Status(http.StatusOK)
+
+	e.DELETE("/banner/{id}", foreignID).
+		WithHeader("Authorization", "Bearer "+tokenEditor).
+		Expect().
+		Status(http.StatusForbidden)
+
+	e.DELETE("/banner").
+		WithQuery("tag_id", own.TagIDs[0]).
+		WithHeader("Authorization", "Bearer "+tokenEditor).
+		Expect().
+		Status(http.StatusForbidden)
+
+	e.DELETE("/banner/{id}", ownID).
+		WithHeader("Authorization", "Bearer "+tokenEditor).
+		Expect().
+		Status(http.StatusNoContent)
+}
+
+func TestRBAC_Editor_AllFeaturesScope(t *testing.T) {
+	e, _, _ := initTest(t)
+	tokenEditor := scopedToken(t, entity.RoleEditor, entity.AllFeaturesWriteScope)
+
+	e.POST("/banner").
+		WithJSON(newCreateBannerDTO()).
+		WithHeader("Authorization", "Bearer "+tokenEditor).
+		Expect().
+		Status(http.StatusCreated)
+}
+
+func TestRBAC_Editor_CantManageFeatures(t *testing.T) {
+	e, _, _ := initTest(t)
+	tokenEditor := scopedToken(t, entity.RoleEditor, entity.AllFeaturesWriteScope)
+
+	e.GET("/feature").
+		WithHeader("Authorization", "Bearer "+tokenEditor).
+		Expect().
+		Status(http.StatusOK)
+
+	e.POST("/feature").
+		WithJSON(map[string]any{"feature_id": unseededIDOffset + getNextFeatureID()}).
+		WithHeader("Authorization", "Bearer "+tokenEditor).
+		Expect().
+		Status(http.StatusForbidden)
+}
+
+func TestRBAC_Viewer_ReadOnly(t *testing.T) {
+	e, _, _ := initTest(t)
+	dto := newCreateBannerDTO()
+	tokenViewer := scopedToken(t, entity.RoleViewer, entity.FeatureWriteScope(dto.FeatureID))
+
+	e.GET("/banner").
+		WithHeader("Authorization", "Bearer "+tokenViewer).
+		Expect().
+		Status(http.StatusOK)
+
+	// scopes don't grant modifications to viewers
+	e.POST("/banner").
+		WithJSON(dto).
+		WithHeader("Authorization", "Bearer "+tokenViewer).
+		Expect().
+		Status(http.StatusForbidden)
+}
+
+func TestRBAC_User_Forbidden(t *testing.T) {
+	e, tokenUsr, _ := initTest(t)
+
+	e.GET("/banner").
+		WithHeader("Authorization", "Bearer "+tokenUsr).
+		Expect().
+		Status(http.StatusForbidden)
+}
@@ -0,0 +1,80 @@
+package suit
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const stubIssuerKeyID = "stub-key"
+
+// StubIssuer is a local OIDC identity provider, that serves the discovery document and the public key,
+// and issues the tokens signed with a key generated on start.
+type StubIssuer struct {
+	Issuer string
+	key    *rsa.PrivateKey
+}
+
+// StartStubIssuer starts the stub identity provider with the issuer URL.
+func StartStubIssuer(issuer string) (*StubIssuer, error) {
+	u, err := url.Parse(issuer)
+	if err != nil {
+		return nil, err
+	}
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	l, err := net.Listen("tcp", u.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	si := &StubIssuer{Issuer: issuer, key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":   issuer,
+			"jwks_uri": issuer + "/keys",
+		})
+	})
+	mux.HandleFunc("GET /keys", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"use": "sig",
+				"alg": "RS256",
+				"kid": stubIssuerKeyID,
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	go func() { _ = http.Serve(l, mux) }() //nolint:gosec // why: test server, timeouts are not needed
+
+	return si, nil
+}
+
+// IssueToken returns a new token with the claims, that is valid for a minute.
+// The iss and exp claims are set, unless they're provided.
+func (si *StubIssuer) IssueToken(claims map[string]any) (string, error) {
+	mc := jwt.MapClaims{
+		"iss": si.Issuer,
+		"exp": time.Now().Add(time.Minute).Unix(),
+	}
+	for k, v := range claims {
+		mc[k] = v
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, mc)
+	token.Header["kid"] = stubIssuerKeyID
+	return token.SignedString(si.key)
+}
@@ -11,14 +11,27 @@ import (
 	"time"
 
 	"banners-management/internal/app"
+	"banners-management/internal/cache/redis"
 	"banners-management/internal/config"
 	"banners-management/internal/lib/jwt"
 	slogdiscard "banners-management/internal/lib/logger/slogimpl"
+	"banners-management/internal/model/dto/auth"
+	"banners-management/internal/service/apikey"
+	"banners-management/internal/service/audit"
+	authsvc "banners-management/internal/service/auth"
 	"banners-management/internal/service/banner"
+	"banners-management/internal/service/feature"
+	"banners-management/internal/service/tag"
 	"banners-management/internal/storage/pgs"
 	"banners-management/migrator"
 )
 
+const (
+	// AdminLogin and AdminPassword are the credentials of the admin, that is registered before the tests.
+	AdminLogin    = "admin"
+	AdminPassword = "admin-password"
+)
+
 var (
 	once sync.Once
 	suit *Suit
@@ -27,6 +40,10 @@ var (
 type Suit struct {
 	Cfg        *config.Config
 	JwtManager *jwt.Manager
+	OIDC       *StubIssuer
+	// Storage and Cache are shared with the running app, so the tests can build their own decorators over them.
+	Storage *pgs.Storage
+	Cache   *redis.Cache
 }
 
 func Setup(t *testing.T) *Suit {
@@ -60,14 +77,50 @@ func Setup(t *testing.T) *Suit {
 
 		// start server
 		cfg := config.MustLoad([]string{}, getenv)
-		s, err := pgs.New(ctx, cfg.DB.ConnectionString())
+		s, err := pgs.New(ctx, cfg.DB.ConnectionString(), cfg.Banner.VersionsLimit)
+		if err != nil {
+			panic(err)
+		}
+		c, err := redis.NewCache(ctx, cfg.Cache.ConnectionString())
+		if err != nil {
+			panic(err)
+		}
+		err = c.Flush(ctx)
 		if err != nil {
 			panic(err)
 		}
 		l := slogdiscard.NewDiscardLogger()
-		j := jwt.NewManager(string(cfg.JwtSettings.SecretKey), time.Duration(cfg.JwtSettings.Expire))
-		b := banner.NewService(s, s, s, s, l)
-		a := app.New(l, j, b)
+		app.RegisterMetrics(s, c)
+		j, err := app.NewJwtManager(&cfg.JwtSettings)
+		if err != nil {
+			panic(err)
+		}
+		lc := banner.NewLocalCache(ctx, cfg.Cache.LocalSize, time.Duration(cfg.Cache.LocalTTL), c, l)
+		cr := banner.NewCacheReader(s, c, lc, l)
+		cw := banner.NewCacheWriter(s, c, lc, l)
+		p := banner.RetryPolicy{
+			MaxAttempts: cfg.Jobs.MaxAttempts,
+			Backoff:     time.Duration(cfg.Jobs.RetryBackoff),
+			MaxBackoff:  time.Duration(cfg.Jobs.MaxRetryBackoff),
+		}
+		d, err := banner.NewRedisStreamDeleter(ctx, c, cw, s, p, l)
+		if err != nil {
+			panic(err)
+		}
+		sb := banner.NewStatsBuffer(ctx, s, time.Duration(cfg.Banner.StatsFlushInterval), l)
+		b := banner.NewService(cr, s, s, cw, cw, cw, cw, cw, d, sb, s, l)
+		au := authsvc.NewService(s, authsvc.NewRedisTokenStore(c), j, time.Duration(cfg.JwtSettings.RefreshExpire), l)
+		_, err = au.CreateUser(ctx, auth.CreateUserDTO{Login: AdminLogin, Password: AdminPassword, Role: "admin"})
+		if err != nil {
+			panic(err)
+		}
+		a := app.New(
+			l, j, b, feature.NewService(s, l), tag.NewService(s, l), au, apikey.NewService(s, l), audit.NewService(s, l),
+		)
+		o, err := StartStubIssuer(cfg.Auth.OIDC.Issuer)
+		if err != nil {
+			panic(err)
+		}
 		go app.RunWithConfig(ctx, []string{}, getenv, a)
 
 		// wait for server to be ready (GET /health)
@@ -76,7 +129,7 @@ func Setup(t *testing.T) *Suit {
 			panic(err)
 		}
 
-		suit = &Suit{cfg, j}
+		suit = &Suit{cfg, j, o, s, c}
 	})
 
 	return suit